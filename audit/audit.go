@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	GraphQL = "graphql"
+	REST    = "rest"
+)
+
+type Result string
+
+const (
+	Success Result = "success"
+	Failure Result = "failure"
+)
+
+// Operation describes the API operation that executes the statements, the keyspace and table are empty when the
+// operation is not bound to them, e.g. role management
+type Operation struct {
+	Api      string
+	Name     string
+	Keyspace string
+	Table    string
+}
+
+// Entry is the audit record of a statement executed as part of a mutation or schema change. The query doesn't contain
+// the bound values.
+type Entry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	UserOrRole string    `json:"userOrRole"`
+	Api        string    `json:"api"`
+	Operation  string    `json:"operation"`
+	Keyspace   string    `json:"keyspace,omitempty"`
+	Table      string    `json:"table,omitempty"`
+	Query      string    `json:"query"`
+	Result     Result    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+	LatencyMs  float64   `json:"latencyMs"`
+}
+
+// Writer persists the audit entries, implementations must be safe for concurrent use
+type Writer interface {
+	Write(entry *Entry) error
+}
+
+type contextKey int
+
+const operationContextKey contextKey = iota
+
+// WithOperation returns a context that marks the statements executed with it as part of the operation
+func WithOperation(ctx context.Context, operation *Operation) context.Context {
+	return context.WithValue(ctx, operationContextKey, operation)
+}
+
+// OperationFromContext returns the operation of the context or nil when the statements are not audited
+func OperationFromContext(ctx context.Context) *Operation {
+	if ctx == nil {
+		return nil
+	}
+	operation, _ := ctx.Value(operationContextKey).(*Operation)
+	return operation
+}
+
+type keyspaceWriter struct {
+	Writer
+	keyspaces map[string]bool
+}
+
+// ForKeyspaces returns a writer that only writes the entries of the provided keyspaces, the entries that are not bound
+// to a keyspace are always written. It returns the same writer when no keyspaces are provided.
+func ForKeyspaces(writer Writer, keyspaces []string) Writer {
+	if len(keyspaces) == 0 {
+		return writer
+	}
+
+	w := &keyspaceWriter{Writer: writer, keyspaces: make(map[string]bool, len(keyspaces))}
+	for _, keyspace := range keyspaces {
+		w.keyspaces[keyspace] = true
+	}
+	return w
+}
+
+func (w *keyspaceWriter) Write(entry *Entry) error {
+	if entry.Keyspace != "" && !w.keyspaces[entry.Keyspace] {
+		return nil
+	}
+	return w.Writer.Write(entry)
+}
+
+type multiWriter []Writer
+
+// MultiWriter returns a writer that writes the entries to all the writers, returning the first error
+func MultiWriter(writers ...Writer) Writer {
+	if len(writers) == 1 {
+		return writers[0]
+	}
+	return multiWriter(writers)
+}
+
+func (m multiWriter) Write(entry *Entry) error {
+	var result error
+	for _, writer := range m {
+		if err := writer.Write(entry); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
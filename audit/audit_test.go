@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type entriesWriter struct {
+	entries []*Entry
+}
+
+func (w *entriesWriter) Write(entry *Entry) error {
+	w.entries = append(w.entries, entry)
+	return nil
+}
+
+func TestOperationFromContext(t *testing.T) {
+	assert.Nil(t, OperationFromContext(nil))
+	assert.Nil(t, OperationFromContext(context.Background()))
+
+	operation := &Operation{Api: GraphQL, Name: "insertBooks", Keyspace: "store", Table: "books"}
+	assert.Same(t, operation, OperationFromContext(WithOperation(context.Background(), operation)))
+}
+
+func TestForKeyspaces(t *testing.T) {
+	writer := &entriesWriter{}
+	assert.Same(t, writer, ForKeyspaces(writer, nil))
+
+	filtered := ForKeyspaces(writer, []string{"ks1"})
+	for _, keyspace := range []string{"ks1", "ks2", ""} {
+		assert.NoError(t, filtered.Write(&Entry{Keyspace: keyspace}))
+	}
+
+	assert.Len(t, writer.entries, 2)
+	assert.Equal(t, "ks1", writer.entries[0].Keyspace)
+	assert.Equal(t, "", writer.entries[1].Keyspace)
+}
+
+func TestFileWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit.log")
+	entry := &Entry{Api: REST, Operation: "DELETE /v1/keyspaces/:keyspaceName", Keyspace: "ks1", Result: Success}
+	line, _ := json.Marshal(entry)
+
+	// Each file fits two entries
+	writer, err := NewFileWriter(path, int64(2*(len(line)+1)), 2)
+	assert.NoError(t, err)
+	for i := 0; i < 7; i++ {
+		assert.NoError(t, writer.Write(entry))
+	}
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, 1, countLines(t, path))
+	assert.Equal(t, 2, countLines(t, path+".1"))
+	assert.Equal(t, 2, countLines(t, path+".2"))
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	var written Entry
+	assert.NoError(t, json.NewDecoder(file).Decode(&written))
+	assert.Equal(t, *entry, written)
+}
+
+func countLines(t *testing.T, path string) int {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		count++
+	}
+	return count
+}
@@ -7,6 +7,7 @@ type contextKey struct {
 }
 
 var authKey = &contextKey{"userOrRole"}
+var claimsKey = &contextKey{"claims"}
 
 func WithContextUserOrRole(ctx context.Context, userOrRole string) context.Context {
 	if ctx == nil {
@@ -24,3 +25,22 @@ func ContextUserOrRole(ctx context.Context) string {
 	}
 	return ""
 }
+
+// WithContextClaims returns a context containing the claims of the authenticated identity, e.g. the claims of a
+// validated JWT, used to apply the row policies
+func WithContextClaims(ctx context.Context, claims map[string]interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ContextClaims returns the claims of the authenticated identity or nil when there are no claims
+func ContextClaims(ctx context.Context) map[string]interface{} {
+	if ctx != nil {
+		if val, ok := ctx.Value(claimsKey).(map[string]interface{}); ok {
+			return val
+		}
+	}
+	return nil
+}
@@ -11,3 +11,9 @@ func TestContextUserOrRole(t *testing.T) {
 	assert.Equal(t, "user1",
 		ContextUserOrRole(WithContextUserOrRole(context.Background(), "user1")))
 }
+
+func TestContextClaims(t *testing.T) {
+	assert.Nil(t, ContextClaims(context.Background()))
+	claims := map[string]interface{}{"sub": "user1"}
+	assert.Equal(t, claims, ContextClaims(WithContextClaims(context.Background(), claims)))
+}
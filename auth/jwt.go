@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// JWTValidator validates the signature and the registered claims of JSON Web Tokens signed with HS256 or RS256
+type JWTValidator struct {
+	algorithm string
+	secret    []byte
+	publicKey *rsa.PublicKey
+	// issuer is the expected "iss" claim, it's not validated when empty
+	issuer string
+	now    func() time.Time
+}
+
+// NewHS256Validator creates a validator of the tokens signed with the HMAC-SHA256 shared secret
+func NewHS256Validator(secret []byte, issuer string) *JWTValidator {
+	return &JWTValidator{algorithm: AlgorithmHS256, secret: secret, issuer: issuer, now: time.Now}
+}
+
+// NewRS256Validator creates a validator of the tokens signed with the private key of the RSA public key
+func NewRS256Validator(publicKey *rsa.PublicKey, issuer string) *JWTValidator {
+	return &JWTValidator{algorithm: AlgorithmRS256, publicKey: publicKey, issuer: issuer, now: time.Now}
+}
+
+// ParseRSAPublicKey parses a PEM encoded RSA public key, in the PKIX or PKCS #1 format
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("the public key is not a RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Validate checks the signature of the token, its expiration and not before times and, when configured, its issuer.
+// It returns the claims of the token.
+func (v *JWTValidator) Validate(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %s", err)
+	}
+
+	// The algorithm is fixed by the configuration to avoid accepting unsigned tokens or a public key as HMAC secret
+	if header.Algorithm != v.algorithm {
+		return nil, fmt.Errorf("unexpected signing algorithm '%s'", header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("invalid token signature")
+	}
+
+	if err := v.verify(parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %s", err)
+	}
+
+	now := float64(v.now().Unix())
+	if exp, ok := claims["exp"]; ok {
+		if value, isNumber := exp.(float64); !isNumber || now >= value {
+			return nil, errors.New("the token is expired")
+		}
+	}
+
+	if nbf, ok := claims["nbf"]; ok {
+		if value, isNumber := nbf.(float64); !isNumber || now < value {
+			return nil, errors.New("the token is not valid yet")
+		}
+	}
+
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return nil, errors.New("unexpected token issuer")
+	}
+
+	return claims, nil
+}
+
+func (v *JWTValidator) verify(signed string, signature []byte) error {
+	if v.algorithm == AlgorithmHS256 {
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+
+	hashed := sha256.Sum256([]byte(signed))
+	if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		return errors.New("invalid token signature")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(bytes.NewReader(data)).Decode(value)
+}
+
+// Handler adds the claims of the bearer token of the requests to their context, see WithContextClaims(). Requests
+// without a bearer token are served without claims and requests with an invalid token are rejected with a 401 status
+// code.
+func (v *JWTValidator) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := r.Header.Get("Authorization")
+		if len(value) < 7 || !strings.EqualFold(value[:7], "Bearer ") {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := v.Validate(strings.TrimSpace(value[7:]))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r.WithContext(WithContextClaims(r.Context(), claims)))
+	})
+}
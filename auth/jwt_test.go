@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var jwtNow = time.Unix(1600000000, 0)
+
+func TestJWTValidatorHS256(t *testing.T) {
+	secret := []byte("secret")
+	validator := NewHS256Validator(secret, "issuer1")
+	validator.now = func() time.Time { return jwtNow }
+
+	claims, err := validator.Validate(signHS256(t, secret, AlgorithmHS256,
+		map[string]interface{}{"sub": "user1", "iss": "issuer1", "exp": jwtNow.Unix() + 60}))
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", claims["sub"])
+
+	items := []struct {
+		token    string
+		expected string
+	}{
+		{"abc", "malformed token"},
+		{signHS256(t, []byte("other"), AlgorithmHS256, map[string]interface{}{"iss": "issuer1"}),
+			"invalid token signature"},
+		{signHS256(t, secret, "none", map[string]interface{}{"iss": "issuer1"}),
+			"unexpected signing algorithm 'none'"},
+		{signHS256(t, secret, AlgorithmHS256, map[string]interface{}{"iss": "issuer1", "exp": jwtNow.Unix()}),
+			"the token is expired"},
+		{signHS256(t, secret, AlgorithmHS256, map[string]interface{}{"iss": "issuer1", "nbf": jwtNow.Unix() + 1}),
+			"the token is not valid yet"},
+		{signHS256(t, secret, AlgorithmHS256, map[string]interface{}{"iss": "issuer2"}),
+			"unexpected token issuer"},
+	}
+
+	for _, item := range items {
+		_, err := validator.Validate(item.token)
+		assert.EqualError(t, err, item.expected)
+	}
+}
+
+func TestJWTValidatorRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	publicKey, err := ParseRSAPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.NoError(t, err)
+
+	validator := NewRS256Validator(publicKey, "")
+	signed := encodeSegment(t, map[string]string{"alg": AlgorithmRS256}) + "." +
+		encodeSegment(t, map[string]interface{}{"sub": "user1"})
+	hashed := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	assert.NoError(t, err)
+
+	claims, err := validator.Validate(signed + "." + base64.RawURLEncoding.EncodeToString(signature))
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", claims["sub"])
+
+	// The public key can not be used as a HMAC secret
+	_, err = validator.Validate(signHS256(t, der, AlgorithmHS256, map[string]interface{}{"sub": "user1"}))
+	assert.EqualError(t, err, "unexpected signing algorithm 'HS256'")
+
+	_, err = ParseRSAPublicKey([]byte("abc"))
+	assert.Error(t, err)
+}
+
+func TestJWTValidatorHandler(t *testing.T) {
+	secret := []byte("secret")
+	var claims map[string]interface{}
+	handler := NewHS256Validator(secret, "").Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims = ContextClaims(r.Context())
+	}))
+
+	serve := func(authorization string) int {
+		claims = nil
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(""))
+	assert.Nil(t, claims)
+
+	assert.Equal(t, http.StatusOK,
+		serve("Bearer "+signHS256(t, secret, AlgorithmHS256, map[string]interface{}{"sub": "user1"})))
+	assert.Equal(t, map[string]interface{}{"sub": "user1"}, claims)
+
+	assert.Equal(t, http.StatusUnauthorized,
+		serve("Bearer "+signHS256(t, []byte("other"), AlgorithmHS256, map[string]interface{}{"sub": "user1"})))
+	assert.Nil(t, claims)
+}
+
+func signHS256(t *testing.T, secret []byte, algorithm string, claims map[string]interface{}) string {
+	signed := encodeSegment(t, map[string]string{"alg": algorithm, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	assert.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
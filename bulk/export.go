@@ -0,0 +1,326 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"io"
+	"sync"
+)
+
+const (
+	DefaultConcurrency = 4
+	DefaultPageSize    = 1000
+	// Using more token ranges than workers keeps the workers busy when the data is not evenly distributed
+	splitsPerWorker = 16
+	// Maximum number of rows sent at once to the writer
+	chunkSize = 100
+)
+
+type ExportOptions struct {
+	Format      Format
+	Concurrency int
+	Splits      int
+	Consistency gocql.Consistency
+	PageSize    int
+	UserOrRole  string
+	// Access restricts the columns that are exported, the columns that are not visible are excluded
+	Access *config.ColumnAccess
+	// RowAccess restricts the partitions that are exported, the rows of other partitions are skipped
+	RowAccess *config.RowAccess
+}
+
+// Exporter reads all the rows of a table by scanning token ranges in parallel
+type Exporter struct {
+	dbClient *db.Db
+}
+
+type flusher interface {
+	Flush()
+}
+
+type rowWriter interface {
+	writeHeader() error
+	write(row map[string]interface{}) error
+	flush() error
+}
+
+func NewExporter(dbClient *db.Db) *Exporter {
+	return &Exporter{dbClient: dbClient}
+}
+
+// Export writes all the rows of the table in the provided format and returns the number of rows written.
+// Rows are written in no particular order.
+func (e *Exporter) Export(
+	ctx context.Context, keyspace string, table *gocql.TableMetadata, w io.Writer, options *ExportOptions,
+) (int, error) {
+	if err := e.dbClient.ValidateTokenRangeScan(); err != nil {
+		return 0, err
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	splits := options.Splits
+	if splits <= 0 {
+		splits = concurrency * splitsPerWorker
+	}
+
+	writer := newRowWriter(w, options.Format, table, options.Access)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ranges := make(chan db.TokenRange)
+	// Chunks of rows
+	pages := make(chan []map[string]interface{}, concurrency)
+
+	var firstErr error
+	var errOnce sync.Once
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	go func() {
+		defer close(ranges)
+		for _, tokenRange := range db.SplitRing(splits) {
+			select {
+			case ranges <- tokenRange:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for tokenRange := range ranges {
+				if err := e.scanRange(ctx, keyspace, table, tokenRange, options, pages); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(pages)
+	}()
+
+	count := 0
+	headerWritten := false
+	for page := range pages {
+		if ctx.Err() != nil {
+			// Drain the remaining pages
+			continue
+		}
+
+		if !headerWritten {
+			// Delay writing until there's data to allow the caller to report errors on the first queries
+			headerWritten = true
+			if err := writer.writeHeader(); err != nil {
+				fail(err)
+				continue
+			}
+		}
+
+		for _, row := range page {
+			if err := writer.write(row); err != nil {
+				fail(err)
+				break
+			}
+			count++
+		}
+
+		if err := writer.flush(); err != nil {
+			fail(err)
+		}
+	}
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+
+	if firstErr == nil && !headerWritten {
+		if err := writer.writeHeader(); err != nil {
+			return count, err
+		}
+		return count, writer.flush()
+	}
+
+	return count, firstErr
+}
+
+func (e *Exporter) scanRange(
+	ctx context.Context,
+	keyspace string,
+	table *gocql.TableMetadata,
+	tokenRange db.TokenRange,
+	options *ExportOptions,
+	pages chan<- []map[string]interface{},
+) error {
+	pageSize := options.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	consistency := options.Consistency
+	if consistency == gocql.Any {
+		// ANY is not valid for reads, use it as the zero value
+		consistency = config.DefaultConsistencyLevel
+	}
+
+	info := &db.TokenRangeSelectInfo{
+		Keyspace: keyspace,
+		Table:    table,
+		Range:    tokenRange,
+	}
+
+	var pageState []byte
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		rows, err := e.dbClient.SelectTokenRange(info, db.NewQueryOptions().
+			WithUserOrRole(options.UserOrRole).
+			WithConsistency(consistency).
+			WithPageSize(pageSize).
+			WithPageState(pageState))
+
+		if err != nil {
+			return err
+		}
+
+		if !sendRows(ctx, rows, table, options, pages) {
+			// Cancelled
+			_ = rows.Close()
+			return nil
+		}
+
+		if err := rows.Close(); err != nil {
+			return err
+		}
+
+		pageState = rows.PageState()
+		if len(pageState) == 0 {
+			return nil
+		}
+	}
+}
+
+// sendRows scans the rows of the page and sends them in chunks, it returns false when the context was cancelled
+func sendRows(
+	ctx context.Context,
+	rows db.RowIterator,
+	table *gocql.TableMetadata,
+	options *ExportOptions,
+	pages chan<- []map[string]interface{},
+) bool {
+	chunk := make([]map[string]interface{}, 0, chunkSize)
+	send := func() bool {
+		if len(chunk) == 0 {
+			return true
+		}
+		select {
+		case pages <- chunk:
+			chunk = make([]map[string]interface{}, 0, chunkSize)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for rows.Next() {
+		row := rows.Row()
+		if !options.RowAccess.Matches(row) {
+			continue
+		}
+
+		chunk = append(chunk, types.ToJsonRow(options.Access.ApplyToRow(row), table))
+		if len(chunk) == chunkSize && !send() {
+			return false
+		}
+	}
+
+	return send()
+}
+
+func newRowWriter(w io.Writer, format Format, table *gocql.TableMetadata, access *config.ColumnAccess) rowWriter {
+	if format == CSV {
+		columns := make([]string, 0, len(table.Columns))
+		for _, name := range db.TableColumnNames(table) {
+			if access.Visible(name) {
+				columns = append(columns, name)
+			}
+		}
+		return &csvRowWriter{w: w, csv: csv.NewWriter(w), columns: columns}
+	}
+	return &ndjsonRowWriter{w: w, encoder: json.NewEncoder(w)}
+}
+
+type ndjsonRowWriter struct {
+	w       io.Writer
+	encoder *json.Encoder
+}
+
+func (n *ndjsonRowWriter) writeHeader() error {
+	return nil
+}
+
+func (n *ndjsonRowWriter) write(row map[string]interface{}) error {
+	return n.encoder.Encode(row)
+}
+
+func (n *ndjsonRowWriter) flush() error {
+	if f, ok := n.w.(flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+type csvRowWriter struct {
+	w       io.Writer
+	csv     *csv.Writer
+	columns []string
+}
+
+func (c *csvRowWriter) writeHeader() error {
+	return c.csv.Write(c.columns)
+}
+
+func (c *csvRowWriter) write(row map[string]interface{}) error {
+	record := make([]string, len(c.columns))
+	for i, name := range c.columns {
+		value, err := csvValue(row[name])
+		if err != nil {
+			return err
+		}
+		record[i] = value
+	}
+	return c.csv.Write(record)
+}
+
+func (c *csvRowWriter) flush() error {
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	if f, ok := c.w.(flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
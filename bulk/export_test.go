@@ -0,0 +1,127 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"math"
+	"strings"
+	"testing"
+)
+
+const exportQuery = `SELECT * FROM "store"."books" WHERE token("title") > ? AND token("title") <= ?`
+
+func newBooksTable() *gocql.TableMetadata {
+	keyspace := db.NewKeyspaceMock("store", map[string][]*gocql.ColumnMetadata{
+		"books": db.BooksColumnsMock,
+	})
+	return keyspace.Tables["books"]
+}
+
+func newExportSessionMock(partitioner string, rows []map[string]interface{}) *db.SessionMock {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetPartitioner(partitioner)
+
+	sessionMock.On("ExecuteRowIterator", exportQuery, mock.Anything, []interface{}{int64(math.MinInt64), int64(-1)}).
+		Return(db.NewRowIteratorMock(rows, nil, nil), nil)
+	sessionMock.On("ExecuteRowIterator", exportQuery, mock.Anything, []interface{}{int64(-1), int64(math.MaxInt64)}).
+		Return(db.NewRowIteratorMock(nil, nil, nil), nil)
+	return sessionMock
+}
+
+func bookRow(title string, pages int) map[string]interface{} {
+	return map[string]interface{}{"title": &title, "pages": &pages}
+}
+
+func TestExportNDJSON(t *testing.T) {
+	sessionMock := newExportSessionMock("org.apache.cassandra.dht.Murmur3Partitioner", []map[string]interface{}{
+		bookRow("Dune", 412),
+		bookRow("Emma", 320),
+	})
+
+	var buf bytes.Buffer
+	count, err := NewExporter(db.NewDbWithSession(sessionMock)).
+		Export(context.Background(), "store", newBooksTable(), &buf, &ExportOptions{Concurrency: 2, Splits: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, "{\"pages\":412,\"title\":\"Dune\"}\n{\"pages\":320,\"title\":\"Emma\"}\n", buf.String())
+	sessionMock.AssertNumberOfCalls(t, "ExecuteRowIterator", 2)
+}
+
+func TestExportCSV(t *testing.T) {
+	sessionMock := newExportSessionMock("org.apache.cassandra.dht.Murmur3Partitioner", []map[string]interface{}{
+		bookRow("Dune, the novel", 412),
+	})
+
+	var buf bytes.Buffer
+	count, err := NewExporter(db.NewDbWithSession(sessionMock)).
+		Export(context.Background(), "store", newBooksTable(), &buf,
+			&ExportOptions{Format: CSV, Concurrency: 1, Splits: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{"title,first_name,last_name,pages", `"Dune, the novel",,,412`}, lines)
+}
+
+func TestExportUnsupportedPartitioner(t *testing.T) {
+	sessionMock := newExportSessionMock("org.apache.cassandra.dht.RandomPartitioner", nil)
+
+	var buf bytes.Buffer
+	_, err := NewExporter(db.NewDbWithSession(sessionMock)).
+		Export(context.Background(), "store", newBooksTable(), &buf, &ExportOptions{})
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, buf.Len())
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, NDJSON, format)
+
+	format, err = ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, CSV, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestExportPaging(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetPartitioner("org.apache.cassandra.dht.Murmur3Partitioner")
+	firstPage := func(options *db.QueryOptions) bool { return len(options.PageState) == 0 }
+	secondPage := func(options *db.QueryOptions) bool { return string(options.PageState) == "next" }
+	sessionMock.On("ExecuteRowIterator", exportQuery, mock.MatchedBy(firstPage), mock.Anything).
+		Return(db.NewRowIteratorMock([]map[string]interface{}{bookRow("Dune", 412)}, []byte("next"), nil), nil)
+	sessionMock.On("ExecuteRowIterator", exportQuery, mock.MatchedBy(secondPage), mock.Anything).
+		Return(db.NewRowIteratorMock([]map[string]interface{}{bookRow("Emma", 320)}, nil, nil), nil)
+
+	var buf bytes.Buffer
+	count, err := NewExporter(db.NewDbWithSession(sessionMock)).
+		Export(context.Background(), "store", newBooksTable(), &buf, &ExportOptions{Concurrency: 1, Splits: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	sessionMock.AssertNumberOfCalls(t, "ExecuteRowIterator", 2)
+}
+
+func TestExportQueryError(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetPartitioner("org.apache.cassandra.dht.Murmur3Partitioner")
+	sessionMock.On("ExecuteRowIterator", exportQuery, mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock(nil, nil, errors.New("read timeout")), nil)
+
+	var buf bytes.Buffer
+	_, err := NewExporter(db.NewDbWithSession(sessionMock)).
+		Export(context.Background(), "store", newBooksTable(), &buf, &ExportOptions{Concurrency: 2, Splits: 4})
+
+	assert.EqualError(t, err, "read timeout")
+	assert.Equal(t, 0, buf.Len())
+}
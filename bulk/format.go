@@ -0,0 +1,72 @@
+package bulk
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Format represents the encoding used to represent rows in bulk operations
+type Format int
+
+const (
+	NDJSON Format = iota
+	CSV
+)
+
+// ParseFormat parses the format name, it defaults to NDJSON when the value is empty
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "", "ndjson", "json":
+		return NDJSON, nil
+	case "csv":
+		return CSV, nil
+	}
+	return NDJSON, fmt.Errorf("unsupported format '%s'", value)
+}
+
+func (f Format) String() string {
+	switch f {
+	case CSV:
+		return "csv"
+	default:
+		return "ndjson"
+	}
+}
+
+// ContentType returns the media type for the format
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// csvValue formats a json value (as returned by types.ToJsonValues) as a CSV cell, text values are represented as is
+// and the rest of the values as JSON
+func csvValue(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,385 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultBatchSize = 100
+	DefaultMaxErrors = 100
+)
+
+type ImportOptions struct {
+	Format      Format
+	Concurrency int
+	Consistency gocql.Consistency
+	// BatchByPartition groups consecutive records of the same partition into unlogged batches
+	BatchByPartition bool
+	BatchSize        int
+	// MaxErrors is the maximum number of record errors included in the result
+	MaxErrors  int
+	UserOrRole string
+	// Access restricts the columns that can be imported, the records containing restricted columns are rejected
+	Access *config.ColumnAccess
+	// RowAccess restricts the partitions that can be imported, the records of other partitions are rejected
+	RowAccess *config.RowAccess
+}
+
+type ImportResult struct {
+	Inserted int
+	Failed   int
+	Errors   []ImportError
+}
+
+// ImportError describes a record that could not be inserted
+type ImportError struct {
+	Line    int
+	Message string
+}
+
+// Importer inserts the rows read from a stream into a table
+type Importer struct {
+	dbClient *db.Db
+}
+
+type importRecord struct {
+	line         int
+	columns      []string
+	values       []interface{}
+	partitionKey string
+	err          error
+}
+
+type recordReader interface {
+	// next returns the next record, per record errors are set on the record and errors reading the stream are
+	// returned. It returns io.EOF at the end of the stream.
+	next() (*importRecord, error)
+}
+
+func NewImporter(dbClient *db.Db) *Importer {
+	return &Importer{dbClient: dbClient}
+}
+
+// Import reads the records from the stream and inserts them into the table. Records that fail to be parsed or inserted
+// are reported in the result without stopping the import, an error is only returned when the stream can not be read.
+// When the stream fails partway through, the result of the records read before the failure is returned with the error.
+func (i *Importer) Import(
+	ctx context.Context, keyspace string, table *gocql.TableMetadata, r io.Reader, options *ImportOptions,
+) (*ImportResult, error) {
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	batchSize := 1
+	if options.BatchByPartition {
+		batchSize = options.BatchSize
+		if batchSize <= 0 {
+			batchSize = DefaultBatchSize
+		}
+	}
+
+	maxErrors := options.MaxErrors
+	if maxErrors <= 0 {
+		maxErrors = DefaultMaxErrors
+	}
+
+	reader, err := newRecordReader(r, options.Format, table)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Errors: make([]ImportError, 0)}
+	var mu sync.Mutex
+	report := func(records []*importRecord, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil {
+			result.Inserted += len(records)
+			return
+		}
+		result.Failed += len(records)
+		for _, record := range records {
+			if len(result.Errors) < maxErrors {
+				result.Errors = append(result.Errors, ImportError{Line: record.line, Message: err.Error()})
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make(chan []*importRecord, concurrency)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if ctx.Err() != nil {
+					continue
+				}
+				report(batch, i.insert(ctx, keyspace, table.Name, batch, options))
+			}
+		}()
+	}
+
+	var readErr error
+	batch := make([]*importRecord, 0, batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			batches <- batch
+			batch = make([]*importRecord, 0, batchSize)
+		}
+	}
+
+	for {
+		if readErr = ctx.Err(); readErr != nil {
+			break
+		}
+
+		record, err := reader.next()
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
+		}
+
+		if record.err == nil {
+			record.err = options.Access.CheckUnrestricted(record.columns...)
+		}
+
+		if record.err == nil {
+			record.err = options.RowAccess.Check(record.columns, record.values)
+		}
+
+		if record.err != nil {
+			report([]*importRecord{record}, record.err)
+			continue
+		}
+
+		if len(batch) > 0 && (len(batch) == batchSize || batch[0].partitionKey != record.partitionKey) {
+			flush()
+		}
+		batch = append(batch, record)
+	}
+
+	flush()
+	close(batches)
+	wg.Wait()
+
+	return result, readErr
+}
+
+func (i *Importer) insert(
+	ctx context.Context, keyspace string, tableName string, records []*importRecord, options *ImportOptions,
+) error {
+	queryOptions := db.NewQueryOptions().
+		WithUserOrRole(options.UserOrRole).
+		WithConsistency(options.Consistency).
+		WithContext(ctx)
+
+	infos := make([]*db.InsertInfo, len(records))
+	for index, record := range records {
+		infos[index] = &db.InsertInfo{
+			Keyspace:    keyspace,
+			Table:       tableName,
+			Columns:     record.columns,
+			QueryParams: record.values,
+			TTL:         -1,
+		}
+	}
+
+	var err error
+	if len(infos) == 1 {
+		_, err = i.dbClient.Insert(infos[0], queryOptions)
+	} else {
+		_, err = i.dbClient.InsertBatch(infos, queryOptions)
+	}
+	return err
+}
+
+func newRecordReader(r io.Reader, format Format, table *gocql.TableMetadata) (recordReader, error) {
+	if format == CSV {
+		return newCsvRecordReader(r, table)
+	}
+	return &ndjsonRecordReader{reader: bufio.NewReader(r), table: table}, nil
+}
+
+// newImportRecord converts the json values using the table metadata and validates that the primary key is set
+func newImportRecord(line int, values map[string]interface{}, table *gocql.TableMetadata) *importRecord {
+	record := &importRecord{line: line}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	record.columns = names
+	record.values = make([]interface{}, len(names))
+	for index, name := range names {
+		column, ok := table.Columns[name]
+		if !ok {
+			record.err = fmt.Errorf("unknown column '%s'", name)
+			return record
+		}
+
+		if values[name] == nil {
+			continue
+		}
+
+		value, err := types.FromJsonValue(values[name], column.Type)
+		if err != nil {
+			record.err = fmt.Errorf("invalid value for column '%s': %s", name, err)
+			return record
+		}
+		record.values[index] = value
+	}
+
+	partitionKey := make([]string, len(table.PartitionKey))
+	for index, column := range table.PartitionKey {
+		value, ok := values[column.Name]
+		if !ok || value == nil {
+			record.err = fmt.Errorf("missing partition key column '%s'", column.Name)
+			return record
+		}
+		partitionKey[index] = fmt.Sprint(value)
+	}
+
+	for _, column := range table.ClusteringColumns {
+		if value, ok := values[column.Name]; !ok || value == nil {
+			record.err = fmt.Errorf("missing clustering column '%s'", column.Name)
+			return record
+		}
+	}
+
+	record.partitionKey = strings.Join(partitionKey, ":")
+	return record
+}
+
+type ndjsonRecordReader struct {
+	reader *bufio.Reader
+	table  *gocql.TableMetadata
+	line   int
+}
+
+func (n *ndjsonRecordReader) next() (*importRecord, error) {
+	for {
+		buf, err := n.reader.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(buf) == 0) {
+			return nil, err
+		}
+
+		n.line++
+		buf = bytes.TrimSpace(buf)
+		if len(buf) == 0 {
+			// Skip empty lines
+			continue
+		}
+
+		var values map[string]interface{}
+		if err := json.Unmarshal(buf, &values); err != nil {
+			return &importRecord{line: n.line, err: fmt.Errorf("invalid json: %s", err)}, nil
+		}
+
+		return newImportRecord(n.line, values, n.table), nil
+	}
+}
+
+type csvRecordReader struct {
+	reader  *csv.Reader
+	table   *gocql.TableMetadata
+	columns []*gocql.ColumnMetadata
+	header  []string
+	// line is the number of the last record read, the header being the first one. It does not account for line breaks
+	// within quoted fields.
+	line int
+}
+
+func newCsvRecordReader(r io.Reader, table *gocql.TableMetadata) (*csvRecordReader, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("missing csv header")
+		}
+		return nil, err
+	}
+
+	columns := make([]*gocql.ColumnMetadata, len(header))
+	for index, name := range header {
+		column, ok := table.Columns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column '%s' in csv header", name)
+		}
+		columns[index] = column
+	}
+
+	return &csvRecordReader{reader: reader, table: table, columns: columns, header: header, line: 1}, nil
+}
+
+func (c *csvRecordReader) next() (*importRecord, error) {
+	cells, err := c.reader.Read()
+	if err != io.EOF {
+		c.line++
+	}
+	line := c.line
+	if err != nil {
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			return &importRecord{line: parseErr.StartLine, err: parseErr.Err}, nil
+		}
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(cells))
+	for index, cell := range cells {
+		if cell == "" {
+			// Empty cells are not inserted to avoid creating tombstones
+			continue
+		}
+
+		value, err := csvCellValue(cell, c.columns[index].Type)
+		if err != nil {
+			return &importRecord{
+				line: line,
+				err:  fmt.Errorf("invalid value for column '%s': %s", c.header[index], err),
+			}, nil
+		}
+		values[c.header[index]] = value
+	}
+
+	return newImportRecord(line, values, c.table), nil
+}
+
+// csvCellValue parses a CSV cell into a json value, the inverse of csvValue
+func csvCellValue(cell string, typeInfo gocql.TypeInfo) (interface{}, error) {
+	switch typeInfo.Type() {
+	case gocql.TypeVarchar, gocql.TypeText, gocql.TypeAscii, gocql.TypeInet, gocql.TypeUUID, gocql.TypeTimeUUID,
+		gocql.TypeBigInt, gocql.TypeCounter, gocql.TypeVarint, gocql.TypeDecimal, gocql.TypeTimestamp,
+		gocql.TypeBlob, gocql.TypeTime, gocql.TypeDate:
+		// Represented as JSON strings
+		return cell, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(cell), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,97 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+const insertQuery = `INSERT INTO "store"."books" ("pages", "title") VALUES (?, ?)`
+
+func TestImportNDJSON(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.On("ExecuteIter", insertQuery, mock.Anything, []interface{}{412, "Dune"}).
+		Return(db.ResultMock{}, nil)
+	sessionMock.On("ExecuteIter", insertQuery, mock.Anything, []interface{}{320, "Emma"}).
+		Return(&db.ResultMock{}, errors.New("write timeout"))
+
+	body := `{"title":"Dune","pages":412}
+
+{"title":"Emma","pages":320}
+{"title":"Ulysses","pages":"many"}
+{"title":
+{"title":"Walden","year":1854}
+{"pages":100}
+`
+
+	result, err := NewImporter(db.NewDbWithSession(sessionMock)).
+		Import(context.Background(), "store", newBooksTable(), strings.NewReader(body), &ImportOptions{Concurrency: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 5, result.Failed)
+	lines := make(map[int]string, len(result.Errors))
+	for _, importErr := range result.Errors {
+		lines[importErr.Line] = importErr.Message
+	}
+	assert.Equal(t, "write timeout", lines[3])
+	assert.Contains(t, lines[4], "invalid value for column 'pages'")
+	assert.Contains(t, lines[5], "invalid json")
+	assert.Equal(t, "unknown column 'year'", lines[6])
+	assert.Equal(t, "missing partition key column 'title'", lines[7])
+}
+
+func TestImportCSV(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(db.ResultMock{}, nil)
+
+	body := "title,pages\n\"Dune, the novel\",412\nEmma,\nWalden\n"
+
+	result, err := NewImporter(db.NewDbWithSession(sessionMock)).
+		Import(context.Background(), "store", newBooksTable(), strings.NewReader(body),
+			&ImportOptions{Format: CSV, Concurrency: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Inserted)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 4, result.Errors[0].Line)
+	sessionMock.AssertCalled(t, "ExecuteIter", insertQuery, mock.Anything, []interface{}{412, "Dune, the novel"})
+	sessionMock.AssertCalled(t, "ExecuteIter", `INSERT INTO "store"."books" ("title") VALUES (?)`, mock.Anything,
+		[]interface{}{"Emma"})
+}
+
+func TestImportCSVUnknownHeader(t *testing.T) {
+	_, err := NewImporter(db.NewDbWithSession(db.NewSessionMock())).
+		Import(context.Background(), "store", newBooksTable(), strings.NewReader("title,year\n"),
+			&ImportOptions{Format: CSV})
+
+	assert.Error(t, err)
+}
+
+func TestImportBatchByPartition(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(db.ResultMock{}, nil)
+
+	body := `{"title":"Dune","pages":1}
+{"title":"Dune","pages":2}
+{"title":"Dune","pages":3}
+{"title":"Emma","pages":4}
+`
+
+	result, err := NewImporter(db.NewDbWithSession(sessionMock)).
+		Import(context.Background(), "store", newBooksTable(), strings.NewReader(body),
+			&ImportOptions{Concurrency: 1, BatchByPartition: true, BatchSize: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Inserted)
+	sessionMock.AssertNumberOfCalls(t, "ExecuteIter", 3)
+	sessionMock.AssertCalled(t, "ExecuteIter",
+		"BEGIN UNLOGGED BATCH "+insertQuery+"; "+insertQuery+"; APPLY BATCH", mock.Anything,
+		[]interface{}{1, "Dune", 2, "Dune"})
+	sessionMock.AssertCalled(t, "ExecuteIter", insertQuery, mock.Anything, []interface{}{3, "Dune"})
+	sessionMock.AssertCalled(t, "ExecuteIter", insertQuery, mock.Anything, []interface{}{4, "Emma"})
+}
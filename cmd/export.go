@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/datastax/cassandra-data-apis/bulk"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/gocql/gocql"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+)
+
+func newExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export --hosts [HOSTS] --keyspace [KEYSPACE] --table [TABLE] [OPTIONS]",
+		Short: "Export all the rows of a table as NDJSON or CSV",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(getStringSlice("hosts")) == 0 {
+				return errors.New("hosts are required")
+			}
+
+			if viper.GetString("keyspace") == "" {
+				return errors.New("keyspace is required")
+			}
+
+			if table, _ := cmd.Flags().GetString("table"); table == "" {
+				return errors.New("table is required")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			flags := cmd.Flags()
+			keyspace := viper.GetString("keyspace")
+			table, _ := flags.GetString("table")
+			output, _ := flags.GetString("output")
+
+			options, err := exportOptionsFromFlags(cmd)
+			if err != nil {
+				logger.Fatal("invalid export options", "error", err)
+			}
+
+			dbClient, err := db.NewDb(dbConfig(), getStringSlice("hosts")...)
+			if err != nil {
+				logger.Fatal("unable to connect to the cluster", "error", err)
+			}
+
+			tableMetadata, err := dbClient.Table(keyspace, table)
+			if err != nil {
+				logger.Fatal("unable to get table metadata", "keyspace", keyspace, "table", table, "error", err)
+			}
+
+			var w io.Writer = os.Stdout
+			if output != "" && output != "-" {
+				file, err := os.Create(output)
+				if err != nil {
+					logger.Fatal("unable to create output file", "output", output, "error", err)
+				}
+				defer file.Close()
+				w = file
+			}
+
+			buffered := bufio.NewWriter(w)
+			count, err := bulk.NewExporter(dbClient).Export(context.Background(), keyspace, tableMetadata, buffered, options)
+			if flushErr := buffered.Flush(); err == nil {
+				err = flushErr
+			}
+
+			if err != nil {
+				logger.Fatal("unable to export table", "keyspace", keyspace, "table", table, "rows", count, "error", err)
+			}
+
+			logger.Info("table exported", "keyspace", keyspace, "table", table, "rows", count)
+		},
+	}
+
+	flags := exportCmd.Flags()
+	flags.String("table", "", "name of the table to export")
+	flags.String("format", "ndjson", "output format. options: ndjson,csv")
+	flags.StringP("output", "o", "", "output file path, it defaults to the standard output")
+	flags.Int("concurrency", bulk.DefaultConcurrency, "number of token ranges scanned in parallel")
+	flags.Int("splits", 0, "number of token ranges the ring is split into, it defaults to 16 per concurrent scan")
+	flags.Int("page-size", bulk.DefaultPageSize, "number of rows retrieved per request")
+	flags.String("consistency", gocql.LocalQuorum.String(), "consistency level used to read the rows")
+
+	return exportCmd
+}
+
+func exportOptionsFromFlags(cmd *cobra.Command) (*bulk.ExportOptions, error) {
+	flags := cmd.Flags()
+	formatName, _ := flags.GetString("format")
+	concurrency, _ := flags.GetInt("concurrency")
+	splits, _ := flags.GetInt("splits")
+	pageSize, _ := flags.GetInt("page-size")
+	consistencyName, _ := flags.GetString("consistency")
+
+	format, err := bulk.ParseFormat(formatName)
+	if err != nil {
+		return nil, err
+	}
+
+	consistency, err := gocql.ParseConsistencyWrapper(consistencyName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bulk.ExportOptions{
+		Format:      format,
+		Concurrency: concurrency,
+		Splits:      splits,
+		PageSize:    pageSize,
+		Consistency: consistency,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/datastax/cassandra-data-apis/bulk"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/gocql/gocql"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+)
+
+func newImportCmd() *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import --hosts [HOSTS] --keyspace [KEYSPACE] --table [TABLE] [OPTIONS]",
+		Short: "Import rows into a table from NDJSON or CSV",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(getStringSlice("hosts")) == 0 {
+				return errors.New("hosts are required")
+			}
+
+			if viper.GetString("keyspace") == "" {
+				return errors.New("keyspace is required")
+			}
+
+			if table, _ := cmd.Flags().GetString("table"); table == "" {
+				return errors.New("table is required")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			flags := cmd.Flags()
+			keyspace := viper.GetString("keyspace")
+			table, _ := flags.GetString("table")
+			input, _ := flags.GetString("input")
+
+			options, err := importOptionsFromFlags(cmd)
+			if err != nil {
+				logger.Fatal("invalid import options", "error", err)
+			}
+
+			dbClient, err := db.NewDb(dbConfig(), getStringSlice("hosts")...)
+			if err != nil {
+				logger.Fatal("unable to connect to the cluster", "error", err)
+			}
+
+			tableMetadata, err := dbClient.Table(keyspace, table)
+			if err != nil {
+				logger.Fatal("unable to get table metadata", "keyspace", keyspace, "table", table, "error", err)
+			}
+
+			var r io.Reader = os.Stdin
+			if input != "" && input != "-" {
+				file, err := os.Open(input)
+				if err != nil {
+					logger.Fatal("unable to open input file", "input", input, "error", err)
+				}
+				defer file.Close()
+				r = file
+			}
+
+			result, err := bulk.NewImporter(dbClient).
+				Import(context.Background(), keyspace, tableMetadata, bufio.NewReader(r), options)
+			if err != nil {
+				logger.Fatal("unable to import rows", "keyspace", keyspace, "table", table, "error", err)
+			}
+
+			for _, importErr := range result.Errors {
+				logger.Warn("unable to import row", "line", importErr.Line, "error", importErr.Message)
+			}
+
+			logger.Info("rows imported",
+				"keyspace", keyspace,
+				"table", table,
+				"inserted", result.Inserted,
+				"failed", result.Failed)
+
+			if result.Failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := importCmd.Flags()
+	flags.String("table", "", "name of the table to import the rows into")
+	flags.String("format", "ndjson", "input format. options: ndjson,csv")
+	flags.StringP("input", "i", "", "input file path, it defaults to the standard input")
+	flags.Int("concurrency", bulk.DefaultConcurrency, "number of concurrent inserts")
+	flags.Bool("batch-by-partition", false, "group consecutive rows of the same partition into unlogged batches")
+	flags.Int("batch-size", bulk.DefaultBatchSize, "maximum number of rows per batch")
+	flags.Int("max-errors", bulk.DefaultMaxErrors, "maximum number of row errors reported")
+	flags.String("consistency", gocql.LocalQuorum.String(), "consistency level used to write the rows")
+
+	return importCmd
+}
+
+func importOptionsFromFlags(cmd *cobra.Command) (*bulk.ImportOptions, error) {
+	flags := cmd.Flags()
+	formatName, _ := flags.GetString("format")
+	concurrency, _ := flags.GetInt("concurrency")
+	batchByPartition, _ := flags.GetBool("batch-by-partition")
+	batchSize, _ := flags.GetInt("batch-size")
+	maxErrors, _ := flags.GetInt("max-errors")
+	consistencyName, _ := flags.GetString("consistency")
+
+	format, err := bulk.ParseFormat(formatName)
+	if err != nil {
+		return nil, err
+	}
+
+	consistency, err := gocql.ParseConsistencyWrapper(consistencyName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bulk.ImportOptions{
+		Format:           format,
+		Concurrency:      concurrency,
+		Consistency:      consistency,
+		BatchByPartition: batchByPartition,
+		BatchSize:        batchSize,
+		MaxErrors:        maxErrors,
+	}, nil
+}
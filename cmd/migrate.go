@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/migration"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+)
+
+func newMigrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate --hosts [HOSTS] --schema [FILE] [--apply] [OPTIONS]",
+		Short: "Print or apply the statements needed to change the schema into the state described by a JSON document",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(getStringSlice("hosts")) == 0 {
+				return errors.New("hosts are required")
+			}
+
+			if schema, _ := cmd.Flags().GetString("schema"); schema == "" {
+				return errors.New("schema is required")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			flags := cmd.Flags()
+			schemaPath, _ := flags.GetString("schema")
+			apply, _ := flags.GetBool("apply")
+			allowDestructive, _ := flags.GetBool("allow-destructive")
+
+			var r io.Reader = os.Stdin
+			if schemaPath != "-" {
+				file, err := os.Open(schemaPath)
+				if err != nil {
+					logger.Fatal("unable to open schema file", "schema", schemaPath, "error", err)
+				}
+				defer file.Close()
+				r = file
+			}
+
+			schema, err := migration.ParseSchema(bufio.NewReader(r))
+			if err != nil {
+				logger.Fatal("invalid schema", "schema", schemaPath, "error", err)
+			}
+
+			dbClient, err := db.NewDb(dbConfig(), getStringSlice("hosts")...)
+			if err != nil {
+				logger.Fatal("unable to connect to the cluster", "error", err)
+			}
+
+			migrator := migration.NewMigrator(dbClient)
+			plan, err := migrator.Plan(schema)
+			if err != nil {
+				logger.Fatal("unable to plan the migration", "error", err)
+			}
+
+			for _, statement := range plan.Statements {
+				if statement.Destructive {
+					fmt.Println("-- destructive")
+				}
+				fmt.Printf("%s;\n", statement.Query)
+			}
+
+			if !apply {
+				return
+			}
+
+			applied, err := migrator.Apply(context.Background(), plan, &migration.ApplyOptions{
+				AllowDestructive: allowDestructive,
+			})
+			if err == migration.ErrDestructive {
+				logger.Fatal("the plan contains destructive statements, use --allow-destructive to apply it")
+			}
+
+			if err != nil {
+				logger.Fatal("unable to apply the migration", "applied", applied, "error", err)
+			}
+
+			logger.Info("migration applied", "statements", applied)
+		},
+	}
+
+	flags := migrateCmd.Flags()
+	flags.String("schema", "", "path of the JSON schema document, use - for the standard input")
+	flags.Bool("apply", false, "apply the statements instead of only printing them")
+	flags.Bool("allow-destructive", false, "allow applying statements that remove tables, columns, types or indexes")
+
+	return migrateCmd
+}
@@ -4,17 +4,22 @@ import (
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"github.com/datastax/cassandra-data-apis/audit"
+	"github.com/datastax/cassandra-data-apis/auth"
 	"github.com/datastax/cassandra-data-apis/config"
 	"github.com/datastax/cassandra-data-apis/db"
 	"github.com/datastax/cassandra-data-apis/endpoint"
+	"github.com/datastax/cassandra-data-apis/events"
 	"github.com/datastax/cassandra-data-apis/graphql"
 	"github.com/datastax/cassandra-data-apis/log"
+	restEndpointV1 "github.com/datastax/cassandra-data-apis/rest/endpoint/v1"
 	"github.com/datastax/cassandra-data-apis/types"
 	"github.com/julienschmidt/httprouter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"io/ioutil"
 	log2 "log"
 	"net/http"
 	"os"
@@ -33,6 +38,7 @@ const envVarPrefix = "data_api"
 var cfgFile string
 var logger log.Logger
 var cfg *endpoint.DataEndpointConfig
+var jwtValidator *auth.JWTValidator
 
 var serverCmd = &cobra.Command{
 	Use:   os.Args[0] + " --hosts [HOSTS] [--start-graph|--start-rest] [OPTIONS]",
@@ -59,8 +65,6 @@ var serverCmd = &cobra.Command{
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		endpoint := createEndpoint()
-
 		graphqlPort := viper.GetInt("graphql-port")
 		restPort := viper.GetInt("rest-port")
 
@@ -73,12 +77,24 @@ var serverCmd = &cobra.Command{
 			logger.Fatal("invalid supported operation", "operations", supportedOps, "error", err)
 		}
 
+		if ops.IsSupported(config.RoleManagement) {
+			// The server doesn't authenticate the requests, the superuser role can only be provided when embedding
+			// the endpoints
+			logger.Fatal("role management is not supported by the server, it can only be used when embedding the "+
+				"endpoints and adding the role with auth.WithContextUserOrRole()",
+				"operations", supportedOps)
+		}
+
+		jwtValidator = createJWTValidator()
+		endpoint := createEndpoint()
+
 		if graphqlPort == restPort {
 			if startGraphQL && startREST && viper.GetString("graphql-path") == viper.GetString("rest-path") {
 				logger.Fatal("graphql and rest paths can not be the same when using the same port")
 			}
 
 			router := createRouter()
+			var handler http.Handler = router
 			endpointNames := ""
 			if startGraphQL {
 				addGraphQLRoutes(router, endpoint, ops)
@@ -86,12 +102,13 @@ var serverCmd = &cobra.Command{
 			}
 			if startREST {
 				addRESTRoutes(router, endpoint, ops)
+				handler = restEndpointV1.RowsActions(router)
 				if endpointNames != "" {
 					endpointNames += "/"
 				}
 				endpointNames += "REST"
 			}
-			listenAndServe(router, graphqlPort, endpointNames)
+			listenAndServe(handler, graphqlPort, endpointNames)
 		} else {
 			finish := make(chan bool)
 			if startGraphQL {
@@ -102,7 +119,7 @@ var serverCmd = &cobra.Command{
 			if startREST {
 				router := httprouter.New()
 				addRESTRoutes(router, endpoint, ops)
-				go listenAndServe(router, restPort, "REST")
+				go listenAndServe(restEndpointV1.RowsActions(router), restPort, "REST")
 			}
 			<-finish
 		}
@@ -133,8 +150,25 @@ func Execute() {
 	flags.StringSlice("operations", []string{
 		"TableCreate",
 		"KeyspaceCreate",
-	}, "list of supported table and keyspace management operations. options: TableCreate,TableDrop,TableAlterAdd,TableAlterDrop,KeyspaceCreate,KeyspaceDrop")
+	}, "list of supported schema management operations. options: TableCreate,TableDrop,TableAlterAdd,TableAlterDrop,KeyspaceCreate,KeyspaceDrop,IndexCreate,IndexDrop,ViewCreate,ViewDrop,TypeCreate,TypeAlterAdd,TypeDrop,TableAlterOptions,KeyspaceAlter,SchemaMigration")
 	flags.String("access-control-allow-origin", "", "Access-Control-Allow-Origin header value")
+	flags.StringSlice("relationships", nil, "links between tables exposed as nested GraphQL fields, e.g. \"ks.videos.videoid -> comments_by_video.videoid\"")
+	flags.Bool("relay-connections", false, "generate Relay-style connection queries for each table")
+	flags.StringSlice("webhook-urls", nil, "urls receiving the change events of the successful mutations")
+	flags.String("webhook-secret", "", "secret used to sign the webhook requests with HMAC-SHA256")
+	flags.Int("webhook-max-retries", events.DefaultWebhookMaxRetries, "maximum amount of retries of a failed webhook request")
+	flags.String("events-file", "", "file where the change events of the successful mutations are appended as NDJSON")
+	flags.String("audit-file", "", "file where the audit entries of the mutations and schema changes are appended as NDJSON")
+	flags.Int("audit-file-max-size", audit.DefaultFileMaxSize/(1024*1024), "size in megabytes at which the audit file is rotated")
+	flags.Int("audit-file-max-backups", audit.DefaultFileMaxBackups, "maximum amount of rotated audit files to keep")
+	flags.String("audit-table", "", "table where the audit entries are inserted, in the \"keyspace.table\" format")
+	flags.StringSlice("audit-keyspaces", nil, "keyspaces whose operations are audited, all keyspaces when not set")
+	flags.String("column-policies", "", "YAML file with the columns each role is allowed, denied or masked")
+	flags.String("row-policies", "", "YAML file with the partition key columns that must match the JWT claims")
+	flags.String("jwt-secret", "", "secret used to validate the JWTs signed with HS256")
+	flags.String("jwt-public-key", "", "PEM file with the RSA public key used to validate the JWTs signed with RS256")
+	flags.String("jwt-issuer", "", "expected issuer of the JWTs, the issuer is not validated when not set")
+	flags.String("exposure", "", "YAML file with the tables and columns exposed and the data operations allowed per table")
 
 	// SSL
 	flags.Bool("ssl-enabled", false, "enable SSL (client-to-node encryption)?")
@@ -166,6 +200,8 @@ func Execute() {
 		}
 	})
 
+	serverCmd.AddCommand(newExportCmd(), newImportCmd(), newMigrateCmd())
+
 	cobra.OnInitialize(initialize)
 
 	viper.SetEnvPrefix(envVarPrefix)
@@ -186,24 +222,105 @@ func createEndpoint() *endpoint.DataEndpoint {
 		updateInterval = endpoint.DefaultSchemaUpdateDuration
 	}
 
-	var sslOptions *db.SslOptions
-	if viper.GetBool("ssl-enabled") {
-		sslOptions = &db.SslOptions{
-			CaPath:           viper.GetString("ssl-ca-cert-path"),
-			CertPath:         viper.GetString("ssl-client-cert-path"),
-			KeyPath:          viper.GetString("ssl-client-key-path"),
-			HostVerification: viper.GetBool("ssl-host-verification"),
+	relationships, err := config.ParseRelationships(getStringSlice("relationships"))
+	if err != nil {
+		logger.Fatal("invalid relationships setting",
+			"error", err)
+	}
+
+	var sinks []events.Sink
+	for _, url := range getStringSlice("webhook-urls") {
+		sinks = append(sinks, events.NewWebhookSink(
+			url, viper.GetString("webhook-secret"), viper.GetInt("webhook-max-retries"), logger))
+	}
+
+	if path := viper.GetString("events-file"); path != "" {
+		sink, err := events.NewFileSink(path)
+		if err != nil {
+			logger.Fatal("unable to open events file",
+				"path", path,
+				"error", err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if path := viper.GetString("audit-file"); path != "" {
+		writer, err := audit.NewFileWriter(path,
+			int64(viper.GetInt("audit-file-max-size"))*1024*1024, viper.GetInt("audit-file-max-backups"))
+		if err != nil {
+			logger.Fatal("unable to open audit file",
+				"path", path,
+				"error", err)
+		}
+		cfg.WithAuditWriter(writer)
+	}
+
+	if table := viper.GetString("audit-table"); table != "" {
+		parts := strings.Split(table, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			logger.Fatal("invalid audit table setting, expected \"keyspace.table\"",
+				"table", table)
+		}
+		cfg.WithAuditTable(parts[0], parts[1])
+	}
+
+	var columnPolicies *config.ColumnPolicies
+	if path := viper.GetString("column-policies"); path != "" {
+		columnPolicies, err = config.LoadColumnPolicies(path)
+		if err != nil {
+			logger.Fatal("unable to load column policies",
+				"path", path,
+				"error", err)
+		}
+
+		// The server doesn't authenticate the requests as a role, only the policies of any role can apply
+		for _, role := range columnPolicies.Roles() {
+			if role != config.AnyRole {
+				logger.Fatal("column policies of specific roles are not supported by the server, use the \"*\" "+
+					"role or embed the endpoints and add the role with auth.WithContextUserOrRole()",
+					"path", path,
+					"role", role)
+			}
+		}
+	}
+
+	var rowPolicies *config.RowPolicies
+	if path := viper.GetString("row-policies"); path != "" {
+		// The claims of the end users are read from their JWTs
+		if jwtValidator == nil {
+			logger.Fatal("row policies require the jwt-secret or jwt-public-key setting to validate the JWTs",
+				"path", path)
+		}
+
+		rowPolicies, err = config.LoadRowPolicies(path)
+		if err != nil {
+			logger.Fatal("unable to load row policies",
+				"path", path,
+				"error", err)
+		}
+	}
+
+	var exposure *config.Exposure
+	if path := viper.GetString("exposure"); path != "" {
+		exposure, err = config.LoadExposure(path)
+		if err != nil {
+			logger.Fatal("unable to load exposure",
+				"path", path,
+				"error", err)
 		}
 	}
 
 	cfg.
-		WithDbConfig(db.Config{
-			Username:   viper.GetString("username"),
-			Password:   viper.GetString("password"),
-			SslOptions: sslOptions,
-		}).
+		WithDbConfig(dbConfig()).
 		WithExcludedKeyspaces(getStringSlice("excluded-keyspaces")).
-		WithSchemaUpdateInterval(updateInterval)
+		WithSchemaUpdateInterval(updateInterval).
+		WithRelationships(relationships).
+		WithRelayConnections(viper.GetBool("relay-connections")).
+		WithEventSinks(sinks).
+		WithAuditKeyspaces(getStringSlice("audit-keyspaces")).
+		WithColumnPolicies(columnPolicies).
+		WithRowPolicies(rowPolicies).
+		WithExposure(exposure)
 
 	dataEndpoint, err := cfg.NewEndpoint()
 	if err != nil {
@@ -214,6 +331,24 @@ func createEndpoint() *endpoint.DataEndpoint {
 	return dataEndpoint
 }
 
+func dbConfig() db.Config {
+	var sslOptions *db.SslOptions
+	if viper.GetBool("ssl-enabled") {
+		sslOptions = &db.SslOptions{
+			CaPath:           viper.GetString("ssl-ca-cert-path"),
+			CertPath:         viper.GetString("ssl-client-cert-path"),
+			KeyPath:          viper.GetString("ssl-client-key-path"),
+			HostVerification: viper.GetBool("ssl-host-verification"),
+		}
+	}
+
+	return db.Config{
+		Username:   viper.GetString("username"),
+		Password:   viper.GetString("password"),
+		SslOptions: sslOptions,
+	}
+}
+
 func addGraphQLRoutes(router *httprouter.Router, endpoint *endpoint.DataEndpoint, ops config.SchemaOperations) {
 	var routes []types.Route
 	var err error
@@ -283,6 +418,47 @@ func addRESTRoutes(router *httprouter.Router, endpoint *endpoint.DataEndpoint, o
 	}
 }
 
+// createJWTValidator creates the validator of the JWTs of the requests, it returns nil when JWTs are not enabled
+func createJWTValidator() *auth.JWTValidator {
+	secret := viper.GetString("jwt-secret")
+	publicKeyPath := viper.GetString("jwt-public-key")
+	issuer := viper.GetString("jwt-issuer")
+
+	if secret != "" && publicKeyPath != "" {
+		logger.Fatal("jwt-secret and jwt-public-key can not be used together")
+	}
+
+	if secret != "" {
+		return auth.NewHS256Validator([]byte(secret), issuer)
+	}
+
+	if publicKeyPath != "" {
+		data, err := ioutil.ReadFile(publicKeyPath)
+		if err != nil {
+			logger.Fatal("unable to read jwt public key",
+				"path", publicKeyPath,
+				"error", err)
+		}
+
+		publicKey, err := auth.ParseRSAPublicKey(data)
+		if err != nil {
+			logger.Fatal("invalid jwt public key",
+				"path", publicKeyPath,
+				"error", err)
+		}
+		return auth.NewRS256Validator(publicKey, issuer)
+	}
+
+	return nil
+}
+
+func maybeAddJWTValidation(handler http.Handler) http.Handler {
+	if jwtValidator != nil {
+		return jwtValidator.Handler(handler)
+	}
+	return handler
+}
+
 func maybeAddRequestLogging(handler http.Handler) http.Handler {
 	if viper.GetBool("request-logging") {
 		handler = log.NewLoggingHandler(handler, logger)
@@ -331,7 +507,7 @@ func listenAndServe(handler http.Handler, port int, endpointNames string) {
 	logger.Info("server listening",
 		"port", port,
 		"type", endpointNames)
-	handler = maybeAddCORS(maybeAddRequestLogging(handler))
+	handler = maybeAddCORS(maybeAddRequestLogging(maybeAddJWTValidation(handler)))
 	err := http.ListenAndServe(fmt.Sprintf(":%d", port), handler)
 	if err != nil {
 		logger.Fatal("unable to start server",
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"fmt"
+	"github.com/gocql/gocql"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// AnyRole is the role of the policies that apply to the roles without a policy for the table
+const AnyRole = "*"
+
+// ColumnPolicy restricts the columns of a table that a role can access. When Allow is not empty, only the listed
+// columns can be accessed. Denied columns can not be read nor written. Masked columns are read with the mask as value,
+// or null when the column is not a text column, and can not be written nor used in conditions.
+type ColumnPolicy struct {
+	Keyspace string
+	Table    string
+	Role     string
+	Allow    []string
+	Deny     []string
+	Mask     map[string]string
+}
+
+// ColumnPolicies contains the column policies by keyspace, table and role
+type ColumnPolicies struct {
+	policies map[string]*ColumnPolicy
+}
+
+type columnPolicyDefinition struct {
+	Table string            `yaml:"table"`
+	Role  string            `yaml:"role"`
+	Allow []string          `yaml:"allow"`
+	Deny  []string          `yaml:"deny"`
+	Mask  map[string]string `yaml:"mask"`
+}
+
+// LoadColumnPolicies reads the column policies from a YAML file, see ParseColumnPolicies()
+func LoadColumnPolicies(path string) (*ColumnPolicies, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseColumnPolicies(data)
+}
+
+// ParseColumnPolicies parses a YAML list of policies with the "table", "role", "allow", "deny" and "mask" keys, the
+// table is in the "keyspace.table" format
+func ParseColumnPolicies(data []byte) (*ColumnPolicies, error) {
+	var definitions []columnPolicyDefinition
+	if err := yaml.UnmarshalStrict(data, &definitions); err != nil {
+		return nil, fmt.Errorf("invalid column policies: %s", err)
+	}
+
+	policies := NewColumnPolicies()
+	for _, definition := range definitions {
+		parts := strings.Split(definition.Table, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid column policy table '%s', expected \"keyspace.table\"", definition.Table)
+		}
+
+		if definition.Role == "" {
+			return nil, fmt.Errorf("the role of the column policy for table '%s' is required", definition.Table)
+		}
+
+		if err := policies.Add(&ColumnPolicy{
+			Keyspace: parts[0],
+			Table:    parts[1],
+			Role:     definition.Role,
+			Allow:    definition.Allow,
+			Deny:     definition.Deny,
+			Mask:     definition.Mask,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return policies, nil
+}
+
+func NewColumnPolicies() *ColumnPolicies {
+	return &ColumnPolicies{policies: make(map[string]*ColumnPolicy)}
+}
+
+// Add adds a policy, there can only be a policy per table and role
+func (p *ColumnPolicies) Add(policy *ColumnPolicy) error {
+	key := policyKey(policy.Keyspace, policy.Table, policy.Role)
+	if _, ok := p.policies[key]; ok {
+		return fmt.Errorf("duplicate column policy for table '%s.%s' and role '%s'",
+			policy.Keyspace, policy.Table, policy.Role)
+	}
+	p.policies[key] = policy
+	return nil
+}
+
+// Roles returns the sorted roles of the policies, including AnyRole when there are policies for any role
+func (p *ColumnPolicies) Roles() []string {
+	if p == nil {
+		return nil
+	}
+
+	unique := make(map[string]bool)
+	for _, policy := range p.policies {
+		unique[policy.Role] = true
+	}
+
+	roles := make([]string, 0, len(unique))
+	for role := range unique {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Access returns the access of the role to the columns of the table, it returns nil when the access is not restricted
+func (p *ColumnPolicies) Access(table *gocql.TableMetadata, role string) *ColumnAccess {
+	if p == nil || len(p.policies) == 0 {
+		return nil
+	}
+
+	policy, ok := p.policies[policyKey(table.Keyspace, table.Name, role)]
+	if !ok {
+		policy, ok = p.policies[policyKey(table.Keyspace, table.Name, AnyRole)]
+	}
+	if !ok {
+		return nil
+	}
+
+	access := &ColumnAccess{
+		table: table.Name,
+		deny:  make(map[string]bool, len(policy.Deny)),
+		masks: make(map[string]interface{}, len(policy.Mask)),
+	}
+
+	if len(policy.Allow) > 0 {
+		access.allow = make(map[string]bool, len(policy.Allow))
+		for _, column := range policy.Allow {
+			access.allow[column] = true
+		}
+	}
+
+	for _, column := range policy.Deny {
+		access.deny[column] = true
+	}
+
+	for column, mask := range policy.Mask {
+		var value interface{}
+		if info, ok := table.Columns[column]; ok && isTextType(info.Type) {
+			value = mask
+		}
+		access.masks[column] = value
+	}
+
+	return access
+}
+
+func policyKey(keyspace string, table string, role string) string {
+	return keyspace + "." + table + "/" + role
+}
+
+func isTextType(info gocql.TypeInfo) bool {
+	switch info.Type() {
+	case gocql.TypeText, gocql.TypeVarchar, gocql.TypeAscii:
+		return true
+	}
+	return false
+}
+
+// ColumnAccess is the access of a role to the columns of a table, a nil access is not restricted
+type ColumnAccess struct {
+	table string
+	allow map[string]bool
+	deny  map[string]bool
+	masks map[string]interface{}
+}
+
+// WithDenied returns an access that also denies the columns of the table, e.g. the columns that are not exposed. It
+// returns the same access when there are no columns.
+func (a *ColumnAccess) WithDenied(table string, columns []string) *ColumnAccess {
+	if len(columns) == 0 {
+		return a
+	}
+
+	result := &ColumnAccess{table: table, deny: make(map[string]bool, len(columns))}
+	if a != nil {
+		result.allow = a.allow
+		result.masks = a.masks
+		for column := range a.deny {
+			result.deny[column] = true
+		}
+	}
+
+	for _, column := range columns {
+		result.deny[column] = true
+	}
+	return result
+}
+
+// Visible determines whether the column can be read, the value might be masked
+func (a *ColumnAccess) Visible(column string) bool {
+	if a == nil {
+		return true
+	}
+	return !a.deny[column] && (a.allow == nil || a.allow[column])
+}
+
+// Unrestricted determines whether the column is visible and not masked, only unrestricted columns can be written or
+// used in conditions
+func (a *ColumnAccess) Unrestricted(column string) bool {
+	if a == nil {
+		return true
+	}
+	_, masked := a.masks[column]
+	return !masked && a.Visible(column)
+}
+
+// CheckUnrestricted returns an error when any of the columns is restricted
+func (a *ColumnAccess) CheckUnrestricted(columns ...string) error {
+	for _, column := range columns {
+		if !a.Unrestricted(column) {
+			return fmt.Errorf("access denied to column '%s' of table '%s'", column, a.table)
+		}
+	}
+	return nil
+}
+
+// ApplyToRow removes the columns that are not visible and masks the values of the row, the row is modified in place
+func (a *ColumnAccess) ApplyToRow(row map[string]interface{}) map[string]interface{} {
+	if a == nil {
+		return row
+	}
+
+	for column := range row {
+		if mask, masked := a.masks[column]; masked {
+			row[column] = mask
+		}
+		if !a.Visible(column) {
+			delete(row, column)
+		}
+	}
+	return row
+}
+
+// ApplyToRows applies the access to each row, see ApplyToRow()
+func (a *ColumnAccess) ApplyToRows(rows []map[string]interface{}) []map[string]interface{} {
+	if a == nil {
+		return rows
+	}
+
+	for _, row := range rows {
+		a.ApplyToRow(row)
+	}
+	return rows
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var customersTable = &gocql.TableMetadata{
+	Keyspace: "store",
+	Name:     "customers",
+	Columns: map[string]*gocql.ColumnMetadata{
+		"id":    {Name: "id", Type: gocql.NewNativeType(0, gocql.TypeUUID, "")},
+		"name":  {Name: "name", Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		"email": {Name: "email", Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		"ssn":   {Name: "ssn", Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		"age":   {Name: "age", Type: gocql.NewNativeType(0, gocql.TypeInt, "")},
+	},
+}
+
+func TestParseColumnPolicies(t *testing.T) {
+	policies, err := ParseColumnPolicies([]byte(`
+- table: store.customers
+  role: support
+  deny: [ssn]
+  mask:
+    email: "***"
+    age: "0"
+- table: store.customers
+  role: "*"
+  allow: [id, name]
+`))
+	assert.NoError(t, err)
+
+	access := policies.Access(customersTable, "support")
+	assert.True(t, access.Visible("email"))
+	assert.False(t, access.Unrestricted("email"))
+	assert.False(t, access.Visible("ssn"))
+	assert.True(t, access.Unrestricted("name"))
+	assert.NoError(t, access.CheckUnrestricted("id", "name"))
+	assert.EqualError(t, access.CheckUnrestricted("name", "email"),
+		"access denied to column 'email' of table 'customers'")
+
+	name, email, ssn, age := "Jane", "jane@example.com", "123", 42
+	assert.Equal(t, map[string]interface{}{"name": &name, "email": "***", "age": nil},
+		access.ApplyToRow(map[string]interface{}{"name": &name, "email": &email, "ssn": &ssn, "age": &age}))
+
+	access = policies.Access(customersTable, "app")
+	assert.True(t, access.Unrestricted("name"))
+	assert.False(t, access.Visible("email"))
+
+	assert.Nil(t, policies.Access(&gocql.TableMetadata{Keyspace: "store", Name: "orders"}, "support"))
+	assert.Equal(t, []string{"*", "support"}, policies.Roles())
+}
+
+func TestParseColumnPoliciesInvalid(t *testing.T) {
+	for _, value := range []string{
+		"- table: customers\n  role: support",
+		"- table: store.customers\n  deny: [ssn]",
+		"- table: store.customers\n  role: support\n  hide: [ssn]",
+		"- table: store.customers\n  role: support\n- table: store.customers\n  role: support",
+	} {
+		_, err := ParseColumnPolicies([]byte(value))
+		assert.Error(t, err, value)
+	}
+}
+
+func TestColumnAccessNil(t *testing.T) {
+	var policies *ColumnPolicies
+	access := policies.Access(customersTable, "support")
+	assert.Nil(t, access)
+	assert.True(t, access.Unrestricted("ssn"))
+	assert.NoError(t, access.CheckUnrestricted("ssn"))
+
+	row := map[string]interface{}{"ssn": "123"}
+	assert.Equal(t, row, access.ApplyToRow(row))
+}
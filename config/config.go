@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"github.com/datastax/cassandra-data-apis/events"
 	"github.com/datastax/cassandra-data-apis/log"
 	"github.com/gocql/gocql"
 	"github.com/julienschmidt/httprouter"
@@ -20,6 +21,25 @@ type Config interface {
 	SchemaUpdateInterval() time.Duration
 	Naming() NamingConventionFn
 	UseUserOrRoleAuth() bool
+	// SuperuserRole is the role required to use the role management API, role management is disabled when empty
+	SuperuserRole() string
+	// Relationships are the links between tables exposed as nested fields in the GraphQL table types
+	Relationships() []*Relationship
+	// RelayConnections determines whether Relay-style connection queries are generated for each table
+	RelayConnections() bool
+	// Broker distributes the changes written through the API to the GraphQL subscriptions, an in-process broker is
+	// used when nil
+	Broker() events.Broker
+	// EventSinks receive the change events of the successful mutations of the GraphQL and REST APIs
+	EventSinks() []events.Sink
+	// ColumnPolicies restrict the columns of the tables that each role can access, the access is not restricted when nil
+	ColumnPolicies() *ColumnPolicies
+	// RowPolicies restrict the partitions of the tables that can be accessed using the claims of the identity, the access
+	// is not restricted when nil
+	RowPolicies() *RowPolicies
+	// Exposure determines the tables and columns that are exposed and the data operations allowed on each table, all
+	// are exposed and allowed when nil
+	Exposure() *Exposure
 	Logger() log.Logger
 	RouterInfo() HttpRouterInfo
 }
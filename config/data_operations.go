@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DataOperations are the operations on the rows of a table
+type DataOperations int
+
+const (
+	DataRead DataOperations = 1 << iota
+	DataInsert
+	DataUpdate
+	DataDelete
+)
+
+const AllDataOperations = DataRead | DataInsert | DataUpdate | DataDelete
+
+// DataOps parses the operation names: "read", "insert", "update" and "delete"
+func DataOps(ops ...string) (DataOperations, error) {
+	var o DataOperations
+	err := o.Add(ops...)
+	return o, err
+}
+
+// ParseDataOps parses a comma-separated list of operation names, e.g. "read,insert"
+func ParseDataOps(value string) (DataOperations, error) {
+	var ops []string
+	for _, op := range strings.Split(value, ",") {
+		if op = strings.TrimSpace(op); op != "" {
+			ops = append(ops, op)
+		}
+	}
+	return DataOps(ops...)
+}
+
+func (o *DataOperations) Set(ops DataOperations)             { *o |= ops }
+func (o *DataOperations) Clear(ops DataOperations)           { *o &= ^ops }
+func (o DataOperations) IsSupported(ops DataOperations) bool { return o&ops != 0 }
+
+func (o *DataOperations) Add(ops ...string) error {
+	for _, op := range ops {
+		switch op {
+		case "read":
+			o.Set(DataRead)
+		case "insert":
+			o.Set(DataInsert)
+		case "update":
+			o.Set(DataUpdate)
+		case "delete":
+			o.Set(DataDelete)
+		default:
+			return fmt.Errorf("invalid data operation: %s", op)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseDataOps(t *testing.T) {
+	ops, err := ParseDataOps("read, insert")
+	assert.NoError(t, err)
+	assert.Equal(t, DataRead|DataInsert, ops)
+	assert.True(t, ops.IsSupported(DataRead))
+	assert.True(t, ops.IsSupported(DataInsert))
+	assert.False(t, ops.IsSupported(DataUpdate))
+	assert.False(t, ops.IsSupported(DataDelete))
+
+	ops, err = ParseDataOps("read,insert,update,delete")
+	assert.NoError(t, err)
+	assert.Equal(t, AllDataOperations, ops)
+
+	_, err = ParseDataOps("read,upsert")
+	assert.EqualError(t, err, "invalid data operation: upsert")
+}
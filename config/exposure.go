@@ -0,0 +1,241 @@
+package config
+
+import (
+	"fmt"
+	"github.com/gocql/gocql"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"sort"
+)
+
+// KeyspaceExposure determines the tables and columns of a keyspace that are exposed and the data operations allowed on
+// each table. The primary key columns are always exposed.
+type KeyspaceExposure struct {
+	Keyspace string
+	// IncludeTables contains the exposed tables, all the tables are exposed when empty
+	IncludeTables []string
+	ExcludeTables []string
+	// IncludeColumns contains the exposed columns by table, all the columns are exposed when the table is not present
+	IncludeColumns map[string][]string
+	ExcludeColumns map[string][]string
+	// Operations contains the allowed data operations by table, all the operations are allowed when the table is not
+	// present
+	Operations map[string]DataOperations
+}
+
+// Exposure contains the keyspace exposures by keyspace name, a nil exposure exposes everything
+type Exposure struct {
+	keyspaces map[string]*keyspaceExposure
+}
+
+type keyspaceExposure struct {
+	includeTables  map[string]bool
+	excludeTables  map[string]bool
+	includeColumns map[string]map[string]bool
+	excludeColumns map[string]map[string]bool
+	operations     map[string]DataOperations
+}
+
+type exposureDefinition struct {
+	Keyspace string `yaml:"keyspace"`
+	Tables   struct {
+		Include []string `yaml:"include"`
+		Exclude []string `yaml:"exclude"`
+	} `yaml:"tables"`
+	Columns map[string]struct {
+		Include []string `yaml:"include"`
+		Exclude []string `yaml:"exclude"`
+	} `yaml:"columns"`
+	Operations map[string]string `yaml:"operations"`
+}
+
+// LoadExposure reads the exposure of the keyspaces from a YAML file, see ParseExposure()
+func LoadExposure(path string) (*Exposure, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseExposure(data)
+}
+
+// ParseExposure parses a YAML list of keyspaces with the "keyspace", "tables", "columns" and "operations" keys. The
+// tables and the columns of each table contain "include" and "exclude" lists, the operations of each table are a
+// comma-separated list, e.g. "read,insert".
+func ParseExposure(data []byte) (*Exposure, error) {
+	var definitions []exposureDefinition
+	if err := yaml.UnmarshalStrict(data, &definitions); err != nil {
+		return nil, fmt.Errorf("invalid exposure: %s", err)
+	}
+
+	exposure := NewExposure()
+	for _, definition := range definitions {
+		keyspace := &KeyspaceExposure{
+			Keyspace:       definition.Keyspace,
+			IncludeTables:  definition.Tables.Include,
+			ExcludeTables:  definition.Tables.Exclude,
+			IncludeColumns: make(map[string][]string, len(definition.Columns)),
+			ExcludeColumns: make(map[string][]string, len(definition.Columns)),
+			Operations:     make(map[string]DataOperations, len(definition.Operations)),
+		}
+
+		for table, columns := range definition.Columns {
+			keyspace.IncludeColumns[table] = columns.Include
+			keyspace.ExcludeColumns[table] = columns.Exclude
+		}
+
+		for table, value := range definition.Operations {
+			ops, err := ParseDataOps(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid operations of table '%s.%s': %s", definition.Keyspace, table, err)
+			}
+			keyspace.Operations[table] = ops
+		}
+
+		if err := exposure.Add(keyspace); err != nil {
+			return nil, err
+		}
+	}
+
+	return exposure, nil
+}
+
+func NewExposure() *Exposure {
+	return &Exposure{keyspaces: make(map[string]*keyspaceExposure)}
+}
+
+// Add adds the exposure of a keyspace, there can only be one per keyspace
+func (e *Exposure) Add(keyspace *KeyspaceExposure) error {
+	if keyspace.Keyspace == "" {
+		return fmt.Errorf("the keyspace of the exposure is required")
+	}
+
+	if _, ok := e.keyspaces[keyspace.Keyspace]; ok {
+		return fmt.Errorf("duplicate exposure for keyspace '%s'", keyspace.Keyspace)
+	}
+
+	k := &keyspaceExposure{
+		excludeTables:  toSet(keyspace.ExcludeTables),
+		includeColumns: make(map[string]map[string]bool, len(keyspace.IncludeColumns)),
+		excludeColumns: make(map[string]map[string]bool, len(keyspace.ExcludeColumns)),
+		operations:     keyspace.Operations,
+	}
+
+	if len(keyspace.IncludeTables) > 0 {
+		k.includeTables = toSet(keyspace.IncludeTables)
+	}
+
+	for table, columns := range keyspace.IncludeColumns {
+		if len(columns) > 0 {
+			k.includeColumns[table] = toSet(columns)
+		}
+	}
+
+	for table, columns := range keyspace.ExcludeColumns {
+		k.excludeColumns[table] = toSet(columns)
+	}
+
+	e.keyspaces[keyspace.Keyspace] = k
+	return nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// TableExposed determines whether the table is exposed
+func (e *Exposure) TableExposed(keyspace string, table string) bool {
+	if e == nil {
+		return true
+	}
+
+	k, ok := e.keyspaces[keyspace]
+	if !ok {
+		return true
+	}
+
+	return !k.excludeTables[table] && (k.includeTables == nil || k.includeTables[table])
+}
+
+// ColumnExposed determines whether the column of the table is exposed, the primary key columns are always exposed
+func (e *Exposure) ColumnExposed(table *gocql.TableMetadata, column string) bool {
+	if e == nil {
+		return true
+	}
+
+	k, ok := e.keyspaces[table.Keyspace]
+	if !ok {
+		return true
+	}
+
+	if info, ok := table.Columns[column]; ok &&
+		(info.Kind == gocql.ColumnPartitionKey || info.Kind == gocql.ColumnClusteringKey) {
+		return true
+	}
+
+	include := k.includeColumns[table.Name]
+	return !k.excludeColumns[table.Name][column] && (include == nil || include[column])
+}
+
+// HiddenColumns returns the columns of the table that are not exposed
+func (e *Exposure) HiddenColumns(table *gocql.TableMetadata) []string {
+	if e == nil {
+		return nil
+	}
+
+	var columns []string
+	for name := range table.Columns {
+		if !e.ColumnExposed(table, name) {
+			columns = append(columns, name)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// Table returns a copy of the table metadata without the hidden columns, it returns the same table when all the
+// columns are exposed
+func (e *Exposure) Table(table *gocql.TableMetadata) *gocql.TableMetadata {
+	hidden := e.HiddenColumns(table)
+	if len(hidden) == 0 {
+		return table
+	}
+
+	hiddenSet := toSet(hidden)
+	result := *table
+	result.Columns = make(map[string]*gocql.ColumnMetadata, len(table.Columns)-len(hidden))
+	for name, column := range table.Columns {
+		if !hiddenSet[name] {
+			result.Columns[name] = column
+		}
+	}
+
+	result.OrderedColumns = make([]string, 0, len(table.OrderedColumns))
+	for _, name := range table.OrderedColumns {
+		if !hiddenSet[name] {
+			result.OrderedColumns = append(result.OrderedColumns, name)
+		}
+	}
+	return &result
+}
+
+// Operations returns the data operations allowed on the table
+func (e *Exposure) Operations(keyspace string, table string) DataOperations {
+	if e == nil {
+		return AllDataOperations
+	}
+
+	k, ok := e.keyspaces[keyspace]
+	if !ok {
+		return AllDataOperations
+	}
+
+	ops, ok := k.operations[table]
+	if !ok {
+		return AllDataOperations
+	}
+	return ops
+}
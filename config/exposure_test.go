@@ -0,0 +1,82 @@
+package config
+
+import (
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseExposure(t *testing.T) {
+	exposure, err := ParseExposure([]byte(`
+- keyspace: store
+  tables:
+    include: [customers, audit_events, sessions]
+    exclude: [sessions]
+  columns:
+    customers:
+      exclude: [internal_notes]
+    audit_events:
+      include: [details]
+  operations:
+    audit_events: read,insert
+`))
+	assert.NoError(t, err)
+
+	assert.True(t, exposure.TableExposed("store", "customers"))
+	assert.True(t, exposure.TableExposed("store", "audit_events"))
+	assert.False(t, exposure.TableExposed("store", "sessions"))
+	assert.False(t, exposure.TableExposed("store", "orders"))
+	assert.True(t, exposure.TableExposed("other", "orders"))
+
+	assert.Equal(t, DataRead|DataInsert, exposure.Operations("store", "audit_events"))
+	assert.Equal(t, AllDataOperations, exposure.Operations("store", "customers"))
+	assert.Equal(t, AllDataOperations, exposure.Operations("other", "audit_events"))
+
+	customers := &gocql.TableMetadata{
+		Keyspace: "store",
+		Name:     "customers",
+		Columns: map[string]*gocql.ColumnMetadata{
+			"id":             {Name: "id", Kind: gocql.ColumnPartitionKey},
+			"name":           {Name: "name", Kind: gocql.ColumnRegular},
+			"internal_notes": {Name: "internal_notes", Kind: gocql.ColumnRegular},
+		},
+		OrderedColumns: []string{"id", "internal_notes", "name"},
+	}
+	assert.Equal(t, []string{"internal_notes"}, exposure.HiddenColumns(customers))
+	table := exposure.Table(customers)
+	assert.Len(t, table.Columns, 2)
+	assert.Nil(t, table.Columns["internal_notes"])
+	assert.Equal(t, []string{"id", "name"}, table.OrderedColumns)
+	// The metadata is not modified
+	assert.Len(t, customers.Columns, 3)
+
+	events := &gocql.TableMetadata{
+		Keyspace: "store",
+		Name:     "audit_events",
+		Columns: map[string]*gocql.ColumnMetadata{
+			"id":      {Name: "id", Kind: gocql.ColumnPartitionKey},
+			"ts":      {Name: "ts", Kind: gocql.ColumnClusteringKey},
+			"user":    {Name: "user", Kind: gocql.ColumnRegular},
+			"details": {Name: "details", Kind: gocql.ColumnRegular},
+		},
+	}
+	// Primary key columns are always exposed
+	assert.Equal(t, []string{"user"}, exposure.HiddenColumns(events))
+
+	var nilExposure *Exposure
+	assert.True(t, nilExposure.TableExposed("store", "sessions"))
+	assert.Equal(t, AllDataOperations, nilExposure.Operations("store", "audit_events"))
+	assert.Same(t, customers, nilExposure.Table(customers))
+}
+
+func TestParseExposureInvalid(t *testing.T) {
+	for _, value := range []string{
+		"- tables: {include: [orders]}",
+		"- keyspace: store\n  operations:\n    orders: read,upsert",
+		"- keyspace: store\n  tables: {hide: [orders]}",
+		"- keyspace: store\n- keyspace: store",
+	} {
+		_, err := ParseExposure([]byte(value))
+		assert.Error(t, err, value)
+	}
+}
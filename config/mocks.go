@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/datastax/cassandra-data-apis/events"
 	"github.com/datastax/cassandra-data-apis/log"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -20,6 +21,14 @@ func (o *ConfigMock) Default() *ConfigMock {
 	o.On("SchemaUpdateInterval").Return(10 * time.Second)
 	o.On("Naming").Return(NamingConventionFn(NewDefaultNaming))
 	o.On("UseUserOrRoleAuth").Return(false)
+	o.On("SuperuserRole").Return("")
+	o.On("Relationships").Return([]*Relationship(nil))
+	o.On("RelayConnections").Return(false)
+	o.On("Broker").Return(events.Broker(nil))
+	o.On("EventSinks").Return([]events.Sink(nil))
+	o.On("ColumnPolicies").Return((*ColumnPolicies)(nil))
+	o.On("RowPolicies").Return((*RowPolicies)(nil))
+	o.On("Exposure").Return((*Exposure)(nil))
 	o.On("Logger").Return(log.NewZapLogger(zap.NewExample()))
 	return o
 }
@@ -44,6 +53,47 @@ func (o *ConfigMock) UseUserOrRoleAuth() bool {
 	return args.Get(0).(bool)
 }
 
+func (o *ConfigMock) SuperuserRole() string {
+	args := o.Called()
+	return args.Get(0).(string)
+}
+
+func (o *ConfigMock) Relationships() []*Relationship {
+	args := o.Called()
+	return args.Get(0).([]*Relationship)
+}
+
+func (o *ConfigMock) RelayConnections() bool {
+	args := o.Called()
+	return args.Get(0).(bool)
+}
+
+func (o *ConfigMock) Broker() events.Broker {
+	args := o.Called()
+	broker, _ := args.Get(0).(events.Broker)
+	return broker
+}
+
+func (o *ConfigMock) EventSinks() []events.Sink {
+	args := o.Called()
+	return args.Get(0).([]events.Sink)
+}
+
+func (o *ConfigMock) ColumnPolicies() *ColumnPolicies {
+	args := o.Called()
+	return args.Get(0).(*ColumnPolicies)
+}
+
+func (o *ConfigMock) RowPolicies() *RowPolicies {
+	args := o.Called()
+	return args.Get(0).(*RowPolicies)
+}
+
+func (o *ConfigMock) Exposure() *Exposure {
+	args := o.Called()
+	return args.Get(0).(*Exposure)
+}
+
 func (o *ConfigMock) Logger() log.Logger {
 	args := o.Called()
 	return args.Get(0).(log.Logger)
@@ -104,7 +104,7 @@ func generateAvailableName(baseName string, nameMap map[string]string) string {
 func isReserved(name string) bool {
 	switch name {
 	case "BasicType", "Bigint", "Blob", "Column", "ColumnInput", "ColumnKind", "Consistency", "ClusteringKeyInput",
-		"DataType", "DataTypeInput", "Decimal", "QueryOptions", "Table", "Query", "Mutation", "Time",
+		"DataType", "DataTypeInput", "Decimal", "MutationOperation", "PageInfo", "QueryOptions", "Table", "Query", "Mutation", "Time",
 		"Timestamp", "TimeUuid", "UpdateOptions", "Uuid", "Varint":
 		return true
 	}
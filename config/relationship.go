@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Relationship links the rows of a table to the rows of another table of the same keyspace, the target columns must
+// be a prefix of the target table primary key containing at least the partition key
+type Relationship struct {
+	Keyspace      string
+	Table         string
+	Columns       []string
+	TargetTable   string
+	TargetColumns []string
+	// OneToOne determines whether the nested field is a single row or a paged list of rows
+	OneToOne bool
+	// Field is the name of the nested field, by default it's named after the target table
+	Field string
+}
+
+var relationshipRegex = regexp.MustCompile(
+	`^(\w+)\.(\w+)\.(\w+(?:\+\w+)*)\s*->\s*(\w+)\.(\w+(?:\+\w+)*)(?:\s+(one-to-one|one-to-many))?(?:\s+as\s+(\w+))?$`)
+
+// ParseRelationship parses a relationship definition with the format
+// "<keyspace>.<table>.<columns> -> <table>.<columns> [one-to-one|one-to-many] [as <field>]", multiple columns are
+// separated with a plus sign, e.g. "store.orders.customer_id+region -> orders_by_customer.customer_id+region"
+func ParseRelationship(value string) (*Relationship, error) {
+	match := relationshipRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return nil, fmt.Errorf("invalid relationship '%s'", value)
+	}
+
+	relationship := &Relationship{
+		Keyspace:      match[1],
+		Table:         match[2],
+		Columns:       strings.Split(match[3], "+"),
+		TargetTable:   match[4],
+		TargetColumns: strings.Split(match[5], "+"),
+		OneToOne:      match[6] == "one-to-one",
+		Field:         match[7],
+	}
+
+	if len(relationship.Columns) != len(relationship.TargetColumns) {
+		return nil, fmt.Errorf("invalid relationship '%s': the amount of columns on both sides must match", value)
+	}
+
+	return relationship, nil
+}
+
+// ParseRelationships parses a list of relationship definitions
+func ParseRelationships(values []string) ([]*Relationship, error) {
+	result := make([]*Relationship, 0, len(values))
+	for _, value := range values {
+		relationship, err := ParseRelationship(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, relationship)
+	}
+	return result, nil
+}
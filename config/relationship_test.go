@@ -0,0 +1,41 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseRelationship(t *testing.T) {
+	relationship, err := ParseRelationship("killrvideo.videos.videoid -> comments_by_video.videoid")
+	assert.NoError(t, err)
+	assert.Equal(t, &Relationship{
+		Keyspace:      "killrvideo",
+		Table:         "videos",
+		Columns:       []string{"videoid"},
+		TargetTable:   "comments_by_video",
+		TargetColumns: []string{"videoid"},
+	}, relationship)
+
+	relationship, err = ParseRelationship("store.orders.customer_id+region->customers.id+region one-to-one as customer")
+	assert.NoError(t, err)
+	assert.Equal(t, &Relationship{
+		Keyspace:      "store",
+		Table:         "orders",
+		Columns:       []string{"customer_id", "region"},
+		TargetTable:   "customers",
+		TargetColumns: []string{"id", "region"},
+		OneToOne:      true,
+		Field:         "customer",
+	}, relationship)
+}
+
+func TestParseRelationshipInvalid(t *testing.T) {
+	for _, value := range []string{
+		"videos.videoid -> comments_by_video.videoid",
+		"killrvideo.videos.videoid -> comments_by_video.videoid many-to-many",
+		"killrvideo.videos.videoid+userid -> comments_by_video.videoid",
+	} {
+		_, err := ParseRelationship(value)
+		assert.Error(t, err, value)
+	}
+}
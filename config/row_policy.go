@@ -0,0 +1,235 @@
+package config
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RowPolicy restricts the rows of a table to the partitions whose key columns match the claims of the authenticated
+// identity, e.g. the "user_id" column must be equal to the "sub" claim
+type RowPolicy struct {
+	Keyspace string
+	Table    string
+	// Claims contains the claim name by partition key column
+	Claims map[string]string
+}
+
+// RowPolicies contains the row policies by keyspace and table
+type RowPolicies struct {
+	policies map[string]*RowPolicy
+}
+
+type rowPolicyDefinition struct {
+	Table  string            `yaml:"table"`
+	Claims map[string]string `yaml:"claims"`
+}
+
+// LoadRowPolicies reads the row policies from a YAML file, see ParseRowPolicies()
+func LoadRowPolicies(path string) (*RowPolicies, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRowPolicies(data)
+}
+
+// ParseRowPolicies parses a YAML list of policies with the "table" and "claims" keys, the table is in the
+// "keyspace.table" format and the claims map partition key columns to claim names
+func ParseRowPolicies(data []byte) (*RowPolicies, error) {
+	var definitions []rowPolicyDefinition
+	if err := yaml.UnmarshalStrict(data, &definitions); err != nil {
+		return nil, fmt.Errorf("invalid row policies: %s", err)
+	}
+
+	policies := NewRowPolicies()
+	for _, definition := range definitions {
+		parts := strings.Split(definition.Table, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid row policy table '%s', expected \"keyspace.table\"", definition.Table)
+		}
+
+		if err := policies.Add(&RowPolicy{
+			Keyspace: parts[0],
+			Table:    parts[1],
+			Claims:   definition.Claims,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return policies, nil
+}
+
+func NewRowPolicies() *RowPolicies {
+	return &RowPolicies{policies: make(map[string]*RowPolicy)}
+}
+
+// Add adds a policy, there can only be a policy per table
+func (p *RowPolicies) Add(policy *RowPolicy) error {
+	if len(policy.Claims) == 0 {
+		return fmt.Errorf("the row policy for table '%s.%s' must contain at least one claim",
+			policy.Keyspace, policy.Table)
+	}
+
+	key := policy.Keyspace + "." + policy.Table
+	if _, ok := p.policies[key]; ok {
+		return fmt.Errorf("duplicate row policy for table '%s'", key)
+	}
+	p.policies[key] = policy
+	return nil
+}
+
+// Access returns the partitions of the table that can be accessed with the claims, it returns nil when the access is
+// not restricted. It returns an error when the table doesn't contain the columns of the policy or a claim is missing.
+func (p *RowPolicies) Access(table *gocql.TableMetadata, claims map[string]interface{}) (*RowAccess, error) {
+	if p == nil || len(p.policies) == 0 {
+		return nil, nil
+	}
+
+	policy, ok := p.policies[table.Keyspace+"."+table.Name]
+	if !ok {
+		return nil, nil
+	}
+
+	access := &RowAccess{table: table.Name, values: make(map[string]string, len(policy.Claims))}
+	for column, claim := range policy.Claims {
+		if !isPartitionKey(table, column) {
+			return nil, fmt.Errorf("the row policy column '%s' is not part of the partition key of table '%s'",
+				column, table.Name)
+		}
+
+		value, ok := claims[claim]
+		if !ok || value == nil {
+			return nil, access.denied()
+		}
+
+		access.columns = append(access.columns, column)
+		access.values[column] = claimValue(value)
+	}
+
+	sort.Strings(access.columns)
+	return access, nil
+}
+
+func isPartitionKey(table *gocql.TableMetadata, column string) bool {
+	for _, key := range table.PartitionKey {
+		if key.Name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// claimValue returns the string representation of the claim, it's used as the value of the conditions as the driver
+// converts strings to the numeric and uuid types
+func claimValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		// JSON numbers
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// RowAccess contains the partition key values that can be accessed, a nil access is not restricted
+type RowAccess struct {
+	table   string
+	columns []string
+	values  map[string]string
+}
+
+func (a *RowAccess) denied() error {
+	return fmt.Errorf("access denied to the rows of table '%s'", a.table)
+}
+
+// Restrict returns the conditions including the mandatory partition key conditions. It returns an error when the
+// conditions already restrict a partition key column of the policy to a different value.
+func (a *RowAccess) Restrict(where []types.ConditionItem) ([]types.ConditionItem, error) {
+	if a == nil {
+		return where, nil
+	}
+
+	result := append(make([]types.ConditionItem, 0, len(where)+len(a.columns)), where...)
+	for _, column := range a.columns {
+		restricted := false
+		for _, item := range where {
+			if item.Column != column {
+				continue
+			}
+			if item.Operator != "=" || !a.matches(column, item.Value) {
+				return nil, a.denied()
+			}
+			restricted = true
+		}
+
+		if !restricted {
+			result = append(result, types.ConditionItem{Column: column, Operator: "=", Value: a.values[column]})
+		}
+	}
+	return result, nil
+}
+
+// Check returns an error when the values don't contain the partition key values that can be accessed, it's used to
+// validate the values of the mutations
+func (a *RowAccess) Check(columns []string, values []interface{}) error {
+	if a == nil {
+		return nil
+	}
+
+	row := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		row[column] = values[i]
+	}
+
+	if !a.Matches(row) {
+		return a.denied()
+	}
+	return nil
+}
+
+// Matches determines whether the row belongs to a partition that can be accessed
+func (a *RowAccess) Matches(row map[string]interface{}) bool {
+	if a == nil {
+		return true
+	}
+
+	for _, column := range a.columns {
+		value, ok := row[column]
+		if !ok || !a.matches(column, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *RowAccess) matches(column string, value interface{}) bool {
+	v := reflect.ValueOf(value)
+	for v.IsValid() && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			// e.g. *big.Int and *inf.Dec
+			return s.String() == a.values[column]
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return false
+	}
+
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String() == a.values[column]
+	}
+	return claimValue(v.Interface()) == a.values[column]
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var ordersTable = &gocql.TableMetadata{
+	Keyspace: "store",
+	Name:     "orders",
+	PartitionKey: []*gocql.ColumnMetadata{
+		{Name: "user_id", Type: gocql.NewNativeType(0, gocql.TypeUUID, "")},
+		{Name: "tenant", Type: gocql.NewNativeType(0, gocql.TypeInt, "")},
+	},
+}
+
+func TestParseRowPolicies(t *testing.T) {
+	policies, err := ParseRowPolicies([]byte(`
+- table: store.orders
+  claims:
+    user_id: sub
+    tenant: tid
+`))
+	assert.NoError(t, err)
+
+	userId := "d6c5a8a6-3a4c-11ea-b77f-2e728ce88125"
+	access, err := policies.Access(ordersTable, map[string]interface{}{"sub": userId, "tid": float64(10)})
+	assert.NoError(t, err)
+
+	where, err := access.Restrict([]types.ConditionItem{{Column: "order_id", Operator: ">", Value: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ConditionItem{
+		{Column: "order_id", Operator: ">", Value: 1},
+		{Column: "tenant", Operator: "=", Value: "10"},
+		{Column: "user_id", Operator: "=", Value: userId},
+	}, where)
+
+	uuid, _ := gocql.ParseUUID(userId)
+	tenant := 10
+	where, err = access.Restrict([]types.ConditionItem{{Column: "user_id", Operator: "=", Value: uuid}})
+	assert.NoError(t, err)
+	assert.Len(t, where, 2)
+
+	_, err = access.Restrict([]types.ConditionItem{{Column: "tenant", Operator: "=", Value: 11}})
+	assert.EqualError(t, err, "access denied to the rows of table 'orders'")
+	_, err = access.Restrict([]types.ConditionItem{{Column: "tenant", Operator: "IN", Value: []interface{}{10}}})
+	assert.Error(t, err)
+
+	assert.NoError(t, access.Check([]string{"user_id", "tenant", "total"}, []interface{}{&uuid, &tenant, 5}))
+	assert.Error(t, access.Check([]string{"user_id", "total"}, []interface{}{uuid, 5}))
+	assert.True(t, access.Matches(map[string]interface{}{"user_id": uuid, "tenant": &tenant}))
+	assert.False(t, access.Matches(map[string]interface{}{"user_id": uuid, "tenant": (*int)(nil)}))
+
+	// Missing claim
+	_, err = policies.Access(ordersTable, map[string]interface{}{"sub": userId})
+	assert.Error(t, err)
+
+	access, err = policies.Access(&gocql.TableMetadata{Keyspace: "store", Name: "products"}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, access)
+}
+
+func TestParseRowPoliciesInvalid(t *testing.T) {
+	for _, value := range []string{
+		"- table: orders\n  claims: {user_id: sub}",
+		"- table: store.orders",
+		"- table: store.orders\n  claims: {user_id: sub}\n  role: app",
+		"- table: store.orders\n  claims: {user_id: sub}\n- table: store.orders\n  claims: {tenant: tid}",
+	} {
+		_, err := ParseRowPolicies([]byte(value))
+		assert.Error(t, err, value)
+	}
+
+	policies, err := ParseRowPolicies([]byte("- table: store.orders\n  claims: {total: sub}"))
+	assert.NoError(t, err)
+	_, err = policies.Access(ordersTable, map[string]interface{}{"sub": "a"})
+	assert.EqualError(t, err, "the row policy column 'total' is not part of the partition key of table 'orders'")
+}
@@ -13,9 +13,22 @@ const (
 	TableAlterDrop
 	KeyspaceCreate
 	KeyspaceDrop
+	IndexCreate
+	IndexDrop
+	ViewCreate
+	ViewDrop
+	TypeCreate
+	TypeAlterAdd
+	TypeDrop
+	TableAlterOptions
+	KeyspaceAlter
+	RoleManagement
+	SchemaMigration
 )
 
-const AllSchemaOperations = TableCreate | TableDrop | TableAlterAdd | TableAlterDrop | KeyspaceCreate | KeyspaceDrop
+const AllSchemaOperations = TableCreate | TableDrop | TableAlterAdd | TableAlterDrop | KeyspaceCreate | KeyspaceDrop |
+	IndexCreate | IndexDrop | ViewCreate | ViewDrop | TypeCreate | TypeAlterAdd | TypeDrop | TableAlterOptions |
+	KeyspaceAlter | RoleManagement | SchemaMigration
 
 func Ops(ops ...string) (SchemaOperations, error) {
 	var o SchemaOperations
@@ -42,6 +55,28 @@ func (o *SchemaOperations) Add(ops ...string) error {
 			o.Set(KeyspaceCreate)
 		case "KeyspaceDrop":
 			o.Set(KeyspaceDrop)
+		case "IndexCreate":
+			o.Set(IndexCreate)
+		case "IndexDrop":
+			o.Set(IndexDrop)
+		case "ViewCreate":
+			o.Set(ViewCreate)
+		case "ViewDrop":
+			o.Set(ViewDrop)
+		case "TypeCreate":
+			o.Set(TypeCreate)
+		case "TypeAlterAdd":
+			o.Set(TypeAlterAdd)
+		case "TypeDrop":
+			o.Set(TypeDrop)
+		case "TableAlterOptions":
+			o.Set(TableAlterOptions)
+		case "KeyspaceAlter":
+			o.Set(KeyspaceAlter)
+		case "RoleManagement":
+			o.Set(RoleManagement)
+		case "SchemaMigration":
+			o.Set(SchemaMigration)
 		default:
 			return fmt.Errorf("invalid operation: %s", op)
 		}
@@ -25,11 +25,24 @@ func TestOperationsAdd(t *testing.T) {
 
 	assert.Equal(t, op, SchemaOperations(0))
 
-	op.Add("TableCreate", "TableDrop", "TableAlterAdd", "TableAlterDrop", "KeyspaceCreate", "KeyspaceDrop")
+	op.Add("TableCreate", "TableDrop", "TableAlterAdd", "TableAlterDrop", "KeyspaceCreate", "KeyspaceDrop",
+		"IndexCreate", "IndexDrop", "ViewCreate", "ViewDrop", "TypeCreate", "TypeAlterAdd", "TypeDrop",
+		"TableAlterOptions", "KeyspaceAlter", "RoleManagement", "SchemaMigration")
 	assert.True(t, op.IsSupported(TableCreate))
 	assert.True(t, op.IsSupported(TableDrop))
 	assert.True(t, op.IsSupported(TableAlterAdd))
 	assert.True(t, op.IsSupported(TableAlterDrop))
 	assert.True(t, op.IsSupported(KeyspaceCreate))
 	assert.True(t, op.IsSupported(KeyspaceDrop))
+	assert.True(t, op.IsSupported(IndexCreate))
+	assert.True(t, op.IsSupported(IndexDrop))
+	assert.True(t, op.IsSupported(ViewCreate))
+	assert.True(t, op.IsSupported(ViewDrop))
+	assert.True(t, op.IsSupported(TypeCreate))
+	assert.True(t, op.IsSupported(TypeAlterAdd))
+	assert.True(t, op.IsSupported(TypeDrop))
+	assert.True(t, op.IsSupported(TableAlterOptions))
+	assert.True(t, op.IsSupported(KeyspaceAlter))
+	assert.True(t, op.IsSupported(RoleManagement))
+	assert.True(t, op.IsSupported(SchemaMigration))
 }
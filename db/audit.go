@@ -0,0 +1,115 @@
+package db
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/audit"
+	"github.com/datastax/cassandra-data-apis/log"
+	"github.com/gocql/gocql"
+	"regexp"
+	"time"
+)
+
+// auditSession records the statements executed with an audit operation in the context, row iterators are used for
+// reads and they are not audited
+type auditSession struct {
+	Session
+	writer audit.Writer
+	logger log.Logger
+}
+
+// WithAudit returns a db that writes the entries of the audited statements, see audit.WithOperation()
+func (db *Db) WithAudit(writer audit.Writer, logger log.Logger) *Db {
+	return NewDbWithSession(&auditSession{Session: db.session, writer: writer, logger: logger})
+}
+
+func (s *auditSession) Execute(query string, options *QueryOptions, values ...interface{}) error {
+	start := time.Now()
+	err := s.Session.Execute(query, options, values...)
+	s.record(query, options, start, err)
+	return err
+}
+
+func (s *auditSession) ExecuteIter(query string, options *QueryOptions, values ...interface{}) (ResultSet, error) {
+	start := time.Now()
+	result, err := s.Session.ExecuteIter(query, options, values...)
+	s.record(query, options, start, err)
+	return result, err
+}
+
+func (s *auditSession) ChangeSchema(query string, options *QueryOptions) error {
+	start := time.Now()
+	err := s.Session.ChangeSchema(query, options)
+	s.record(query, options, start, err)
+	return err
+}
+
+func (s *auditSession) record(query string, options *QueryOptions, start time.Time, err error) {
+	if options == nil {
+		return
+	}
+
+	operation := audit.OperationFromContext(options.Context)
+	if operation == nil {
+		return
+	}
+
+	entry := &audit.Entry{
+		Timestamp:  start,
+		UserOrRole: options.UserOrRole,
+		Api:        operation.Api,
+		Operation:  operation.Name,
+		Keyspace:   operation.Keyspace,
+		Table:      operation.Table,
+		Query:      redactPasswords(query),
+		Result:     audit.Success,
+		LatencyMs:  float64(time.Since(start)) / float64(time.Millisecond),
+	}
+
+	if err != nil {
+		entry.Result = audit.Failure
+		entry.Error = redactPasswords(err.Error())
+	}
+
+	if writeErr := s.writer.Write(entry); writeErr != nil {
+		s.logger.Error("unable to write audit entry",
+			"operation", operation.Name,
+			"keyspace", operation.Keyspace,
+			"error", writeErr)
+	}
+}
+
+// passwordRegex matches the password option of the role statements, including the escaped quotes of the literal
+var passwordRegex = regexp.MustCompile(`(?i)\bPASSWORD\s*=\s*'(?:[^']|'')*'`)
+
+// redactPasswords replaces the passwords of CREATE ROLE and ALTER ROLE statements, the audit entries must not contain
+// credentials
+func redactPasswords(query string) string {
+	return passwordRegex.ReplaceAllString(query, "PASSWORD = '*****'")
+}
+
+// auditTableWriter inserts the entries in a table with the following definition:
+//
+//	CREATE TABLE audit_log (
+//	  date date, id timeuuid, user_or_role text, api text, operation text, keyspace_name text, table_name text,
+//	  query text, result text, error text, latency_ms double,
+//	  PRIMARY KEY (date, id)) WITH CLUSTERING ORDER BY (id DESC)
+type auditTableWriter struct {
+	session Session
+	query   string
+}
+
+// NewAuditTableWriter returns a writer that inserts the entries in the table, the inserts are not audited
+func (db *Db) NewAuditTableWriter(keyspace string, table string) audit.Writer {
+	return &auditTableWriter{
+		session: db.session,
+		query: fmt.Sprintf(`INSERT INTO "%s"."%s" (date, id, user_or_role, api, operation, keyspace_name, table_name, `+
+			`query, result, error, latency_ms) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, keyspace, table),
+	}
+}
+
+func (w *auditTableWriter) Write(entry *audit.Entry) error {
+	timestamp := entry.Timestamp.UTC()
+	return w.session.Execute(w.query, NewQueryOptions().WithConsistency(gocql.LocalQuorum),
+		timestamp.Truncate(24*time.Hour), gocql.UUIDFromTime(timestamp), entry.UserOrRole, entry.Api, entry.Operation,
+		entry.Keyspace, entry.Table, entry.Query, string(entry.Result), entry.Error, entry.LatencyMs)
+}
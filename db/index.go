@@ -0,0 +1,160 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StorageAttachedIndex is the class name of the storage-attached index (SAI) implementation
+const StorageAttachedIndex = "StorageAttachedIndex"
+
+// Index targets for collection columns, an empty target indexes the values of lists and sets and the values of maps
+const (
+	IndexTargetKeys    = "KEYS"
+	IndexTargetValues  = "VALUES"
+	IndexTargetEntries = "ENTRIES"
+	IndexTargetFull    = "FULL"
+)
+
+var indexTargets = map[string]bool{
+	IndexTargetKeys:    true,
+	IndexTargetValues:  true,
+	IndexTargetEntries: true,
+	IndexTargetFull:    true,
+}
+
+type CreateIndexInfo struct {
+	Keyspace string
+	Table    string
+	// Name of the index, when empty the server generates one
+	Name   string
+	Column string
+	// Target is one of KEYS, VALUES, ENTRIES or FULL for collection columns, empty otherwise
+	Target string
+	// Class is the custom index implementation, e.g. StorageAttachedIndex, empty for a regular secondary index
+	Class       string
+	IfNotExists bool
+}
+
+type DropIndexInfo struct {
+	Keyspace string
+	Name     string
+	IfExists bool
+}
+
+// IndexMetadata describes an existing index
+type IndexMetadata struct {
+	Name   string
+	Table  string
+	Column string
+	// Target is one of KEYS, VALUES, ENTRIES or FULL for collection columns, empty otherwise
+	Target string
+	// Class is the custom index implementation, empty for a regular secondary index
+	Class string
+}
+
+func (db *Db) CreateIndex(info *CreateIndexInfo, options *QueryOptions) error {
+	query, err := createIndexQuery(info)
+	if err != nil {
+		return err
+	}
+	return db.session.ChangeSchema(query, options)
+}
+
+func (db *Db) DropIndex(info *DropIndexInfo, options *QueryOptions) error {
+	query := fmt.Sprintf(`DROP INDEX %s%s`, ifExistsStr(info.IfExists), QualifiedName(info.Keyspace, info.Name))
+	return db.session.ChangeSchema(query, options)
+}
+
+// Indexes returns the indexes of the keyspace by table name
+func (db *Db) Indexes(ksName string) (map[string][]*IndexMetadata, error) {
+	iter, err := db.session.ExecuteIter(
+		"SELECT table_name, index_name, options FROM system_schema.indexes WHERE keyspace_name = ?", nil, ksName)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[string][]*IndexMetadata)
+	for _, row := range iter.Values() {
+		index := &IndexMetadata{
+			Name:  *row["index_name"].(*string),
+			Table: *row["table_name"].(*string),
+		}
+
+		var indexOptions map[string]string
+		switch value := row["options"].(type) {
+		case *map[string]string:
+			if value != nil {
+				indexOptions = *value
+			}
+		case map[string]string:
+			indexOptions = value
+		}
+
+		index.Column, index.Target = parseIndexTarget(indexOptions["target"])
+		if className := indexOptions["class_name"]; className != "" {
+			index.Class = className[strings.LastIndex(className, ".")+1:]
+		}
+
+		indexes[index.Table] = append(indexes[index.Table], index)
+	}
+
+	for _, tableIndexes := range indexes {
+		sort.Slice(tableIndexes, func(i, j int) bool {
+			return tableIndexes[i].Name < tableIndexes[j].Name
+		})
+	}
+
+	return indexes, nil
+}
+
+func createIndexQuery(info *CreateIndexInfo) (string, error) {
+	target := strings.ToUpper(info.Target)
+	if target != "" && !indexTargets[target] {
+		return "", fmt.Errorf("invalid index target '%s'", info.Target)
+	}
+
+	if info.Column == "" {
+		return "", fmt.Errorf("column name must be provided")
+	}
+
+	column := QuoteIdentifier(info.Column)
+	if target != "" {
+		column = fmt.Sprintf("%s(%s)", target, column)
+	}
+
+	name := ""
+	if info.Name != "" {
+		name = fmt.Sprintf(`%s `, QuoteIdentifier(info.Name))
+	}
+
+	custom := ""
+	using := ""
+	if info.Class != "" {
+		custom = "CUSTOM "
+		using = fmt.Sprintf(" USING '%s'", strings.ReplaceAll(info.Class, "'", "''"))
+	}
+
+	return fmt.Sprintf(`CREATE %sINDEX %s%sON %s (%s)%s`,
+		custom, ifNotExistsStr(info.IfNotExists), name, QualifiedName(info.Keyspace, info.Table), column, using), nil
+}
+
+// parseIndexTarget returns the column name and the collection target from the index "target" option,
+// e.g. "keys(tags)" or "\"Name\""
+func parseIndexTarget(target string) (column string, kind string) {
+	if open := strings.Index(target, "("); open > 0 && strings.HasSuffix(target, ")") {
+		kind = strings.ToUpper(target[:open])
+		if indexTargets[kind] {
+			target = target[open+1 : len(target)-1]
+		} else {
+			kind = ""
+		}
+	}
+
+	if len(target) > 1 && strings.HasPrefix(target, `"`) && strings.HasSuffix(target, `"`) {
+		target = strings.ReplaceAll(target[1:len(target)-1], `""`, `"`)
+	}
+
+	return target, kind
+}
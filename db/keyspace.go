@@ -1,13 +1,28 @@
 package db
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type CreateKeyspaceInfo struct {
-	Name        string
-	DCReplicas  map[string]int
-	IfNotExists bool
+	Name string
+	// DCReplicas contains the replicas per data center using NetworkTopologyStrategy
+	DCReplicas map[string]int
+	// ReplicationFactor is used with SimpleStrategy when no data center replicas are provided
+	ReplicationFactor int
+	// DurableWrites uses the server default when nil
+	DurableWrites *bool
+	IfNotExists   bool
+}
+
+type AlterKeyspaceInfo struct {
+	Name              string
+	DCReplicas        map[string]int
+	ReplicationFactor int
+	DurableWrites     *bool
 }
 
 type DropKeyspaceInfo struct {
@@ -16,17 +31,83 @@ type DropKeyspaceInfo struct {
 }
 
 func (db *Db) CreateKeyspace(info *CreateKeyspaceInfo, options *QueryOptions) error {
-	dcs := ""
-	for name, replicas := range info.DCReplicas {
-		dcs += fmt.Sprintf(", '%s': %d", name, replicas)
+	replication, err := replicationStr(info.DCReplicas, info.ReplicationFactor)
+	if err != nil {
+		return err
+	}
+
+	if replication == "" {
+		return errors.New("data center replicas or a replication factor must be provided")
+	}
+
+	query := fmt.Sprintf(`CREATE KEYSPACE %s%s WITH REPLICATION = %s%s`, ifNotExistsStr(info.IfNotExists),
+		QuoteIdentifier(info.Name), replication, durableWritesStr(info.DurableWrites, " AND "))
+	return db.session.ChangeSchema(query, options)
+}
+
+func (db *Db) AlterKeyspace(info *AlterKeyspaceInfo, options *QueryOptions) error {
+	replication, err := replicationStr(info.DCReplicas, info.ReplicationFactor)
+	if err != nil {
+		return err
+	}
+
+	var query string
+	if replication != "" {
+		query = fmt.Sprintf(`ALTER KEYSPACE %s WITH REPLICATION = %s%s`,
+			QuoteIdentifier(info.Name), replication, durableWritesStr(info.DurableWrites, " AND "))
+	} else if info.DurableWrites != nil {
+		query = fmt.Sprintf(`ALTER KEYSPACE %s WITH %s`, QuoteIdentifier(info.Name), durableWritesStr(info.DurableWrites, ""))
+	} else {
+		return errors.New("replication or durable writes must be provided")
 	}
 
-	query := fmt.Sprintf(`CREATE KEYSPACE %s"%s" WITH REPLICATION  = { 'class': 'NetworkTopologyStrategy', %s }`,
-		ifNotExistsStr(info.IfNotExists), info.Name, dcs[2:])
 	return db.session.ChangeSchema(query, options)
 }
 
 func (db *Db) DropKeyspace(info *DropKeyspaceInfo, options *QueryOptions) error {
-	query := fmt.Sprintf(`DROP KEYSPACE %s"%s"`, ifExistsStr(info.IfExists), info.Name)
+	query := fmt.Sprintf(`DROP KEYSPACE %s%s`, ifExistsStr(info.IfExists), QuoteIdentifier(info.Name))
 	return db.session.ChangeSchema(query, options)
 }
+
+// replicationStr returns the replication map literal, using NetworkTopologyStrategy when data center replicas are
+// provided, SimpleStrategy when only a replication factor is provided, or an empty string when neither is set
+func replicationStr(dcReplicas map[string]int, replicationFactor int) (string, error) {
+	if len(dcReplicas) > 0 {
+		if replicationFactor > 0 {
+			return "", errors.New("data center replicas and replication factor can not be used together")
+		}
+
+		names := make([]string, 0, len(dcReplicas))
+		for name := range dcReplicas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		dcs := make([]string, 0, len(names))
+		for _, name := range names {
+			if dcReplicas[name] < 0 {
+				return "", fmt.Errorf("invalid replicas for data center '%s'", name)
+			}
+			dcs = append(dcs, fmt.Sprintf("'%s': %d", strings.ReplaceAll(name, "'", "''"), dcReplicas[name]))
+		}
+
+		return fmt.Sprintf("{ 'class': 'NetworkTopologyStrategy', %s }", strings.Join(dcs, ", ")), nil
+	}
+
+	if replicationFactor < 0 {
+		return "", fmt.Errorf("invalid replication factor %d", replicationFactor)
+	}
+
+	if replicationFactor > 0 {
+		return fmt.Sprintf("{ 'class': 'SimpleStrategy', 'replication_factor': %d }", replicationFactor), nil
+	}
+
+	return "", nil
+}
+
+func durableWritesStr(durableWrites *bool, prefix string) string {
+	if durableWrites == nil {
+		return ""
+	}
+	return fmt.Sprintf("%sDURABLE_WRITES = %t", prefix, *durableWrites)
+}
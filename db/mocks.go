@@ -1,9 +1,11 @@
 package db
 
 import (
+	"fmt"
 	"github.com/gocql/gocql"
 	"github.com/stretchr/testify/mock"
 	"sort"
+	"strings"
 )
 
 type SessionMock struct {
@@ -20,6 +22,11 @@ func (o *SessionMock) ExecuteIter(query string, options *QueryOptions, values ..
 	return args.Get(0).(ResultSet), args.Error(1)
 }
 
+func (o *SessionMock) ExecuteRowIterator(query string, options *QueryOptions, values ...interface{}) (RowIterator, error) {
+	args := o.Called(query, options, values)
+	return args.Get(0).(RowIterator), args.Error(1)
+}
+
 func (o *SessionMock) ChangeSchema(query string, options *QueryOptions) error {
 	args := o.Called(query, options)
 	return args.Error(0)
@@ -43,6 +50,38 @@ func (o ResultMock) Values() []map[string]interface{} {
 	return args.Get(0).([]map[string]interface{})
 }
 
+// RowIteratorMock is a RowIterator over rows in memory, the error is returned by Close
+type RowIteratorMock struct {
+	rows      []map[string]interface{}
+	pageState []byte
+	err       error
+	index     int
+}
+
+func NewRowIteratorMock(rows []map[string]interface{}, pageState []byte, err error) *RowIteratorMock {
+	return &RowIteratorMock{rows: rows, pageState: pageState, err: err, index: -1}
+}
+
+func (o *RowIteratorMock) Next() bool {
+	if o.index+1 >= len(o.rows) {
+		return false
+	}
+	o.index++
+	return true
+}
+
+func (o *RowIteratorMock) Row() map[string]interface{} {
+	return o.rows[o.index]
+}
+
+func (o *RowIteratorMock) PageState() []byte {
+	return o.pageState
+}
+
+func (o *RowIteratorMock) Close() error {
+	return o.err
+}
+
 var BooksColumnsMock = []*gocql.ColumnMetadata{
 	&gocql.ColumnMetadata{
 		Name: "title",
@@ -108,6 +147,7 @@ func (o *SessionMock) Default() *SessionMock {
 			"books": BooksColumnsMock,
 		}))
 	o.AddViews(nil)
+	o.AddIndexes(nil)
 	return o
 }
 
@@ -122,6 +162,17 @@ func (o *SessionMock) SetSchemaVersion(version string) *mock.Call {
 		Return(schemaVersionResultMock, nil)
 }
 
+func (o *SessionMock) SetPartitioner(partitioner string) *mock.Call {
+	partitionerResultMock := &ResultMock{}
+	partitionerResultMock.
+		On("Values").Return([]map[string]interface{}{
+		map[string]interface{}{"partitioner": &partitioner},
+	}, nil)
+
+	return o.On("ExecuteIter", "SELECT partitioner FROM system.local", mock.Anything, mock.Anything).
+		Return(partitionerResultMock, nil)
+}
+
 func (o *SessionMock) AddViews(views []string) *mock.Call {
 	values := make([]map[string]interface{}, 0, len(views))
 	for _, value := range views {
@@ -137,6 +188,121 @@ func (o *SessionMock) AddViews(views []string) *mock.Call {
 		Return(viewsResultMock, nil)
 }
 
+func (o *SessionMock) AddIndexes(indexes []*IndexMetadata) *mock.Call {
+	values := make([]map[string]interface{}, 0, len(indexes))
+	for _, index := range indexes {
+		target := fmt.Sprintf(`"%s"`, index.Column)
+		if index.Target != "" {
+			target = fmt.Sprintf(`%s(%s)`, strings.ToLower(index.Target), target)
+		}
+		options := map[string]string{"target": target}
+		if index.Class != "" {
+			options["class_name"] = "org.apache.cassandra.index.sai." + index.Class
+		}
+		tableName := index.Table
+		indexName := index.Name
+		values = append(values, map[string]interface{}{
+			"table_name": &tableName,
+			"index_name": &indexName,
+			"options":    &options,
+		})
+	}
+	indexesResultMock := &ResultMock{}
+	indexesResultMock.
+		On("Values").Return(values, nil)
+
+	return o.On("ExecuteIter",
+		"SELECT table_name, index_name, options FROM system_schema.indexes WHERE keyspace_name = ?",
+		mock.Anything, mock.Anything).
+		Return(indexesResultMock, nil)
+}
+
+func (o *SessionMock) AddViewsMetadata(views []*ViewMetadata) {
+	viewValues := make([]map[string]interface{}, 0, len(views))
+	columnValues := make([]map[string]interface{}, 0)
+	for _, view := range views {
+		viewName := view.Name
+		baseTable := view.BaseTable
+		whereClause := view.WhereClause
+		includeAllColumns := view.IncludeAllColumns
+		viewValues = append(viewValues, map[string]interface{}{
+			"view_name":           &viewName,
+			"base_table_name":     &baseTable,
+			"where_clause":        &whereClause,
+			"include_all_columns": &includeAllColumns,
+		})
+
+		kinds := make(map[string]string)
+		positions := make(map[string]int)
+		orders := make(map[string]string)
+		for i, name := range view.PartitionKeys {
+			kinds[name] = "partition_key"
+			positions[name] = i
+		}
+		for i, key := range view.ClusteringKeys {
+			kinds[key.Column] = "clustering"
+			positions[key.Column] = i
+			orders[key.Column] = strings.ToLower(key.Order)
+		}
+		for _, name := range view.Columns {
+			kind, ok := kinds[name]
+			if !ok {
+				kind = "regular"
+				positions[name] = -1
+			}
+			columnName := name
+			position := positions[name]
+			order := orders[name]
+			if order == "" {
+				order = "none"
+			}
+			columnValues = append(columnValues, map[string]interface{}{
+				"table_name":       &viewName,
+				"column_name":      &columnName,
+				"kind":             &kind,
+				"position":         &position,
+				"clustering_order": &order,
+			})
+		}
+	}
+
+	viewsResultMock := &ResultMock{}
+	viewsResultMock.On("Values").Return(viewValues, nil)
+	o.On("ExecuteIter",
+		"SELECT view_name, base_table_name, where_clause, include_all_columns FROM system_schema.views "+
+			"WHERE keyspace_name = ?",
+		mock.Anything, mock.Anything).
+		Return(viewsResultMock, nil)
+
+	columnsResultMock := &ResultMock{}
+	columnsResultMock.On("Values").Return(columnValues, nil)
+	o.On("ExecuteIter",
+		"SELECT table_name, column_name, kind, position, clustering_order FROM system_schema.columns "+
+			"WHERE keyspace_name = ?",
+		mock.Anything, mock.Anything).
+		Return(columnsResultMock, nil)
+}
+
+func (o *SessionMock) AddTableOptions(options *TableOptions) *mock.Call {
+	row := map[string]interface{}{
+		"default_time_to_live":   options.DefaultTimeToLive,
+		"gc_grace_seconds":       options.GcGraceSeconds,
+		"bloom_filter_fp_chance": options.BloomFilterFpChance,
+		"comment":                options.Comment,
+		"compaction":             &options.Compaction,
+		"compression":            &options.Compression,
+		"caching":                &options.Caching,
+	}
+	optionsResultMock := &ResultMock{}
+	optionsResultMock.On("Values").Return([]map[string]interface{}{row}, nil)
+
+	return o.On("ExecuteIter",
+		"SELECT default_time_to_live, gc_grace_seconds, bloom_filter_fp_chance, comment, compaction, compression, "+
+			"caching FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ?",
+		mock.Anything, mock.Anything).
+		Return(optionsResultMock, nil)
+}
+
 func (o *SessionMock) AddKeyspace(keyspace *gocql.KeyspaceMetadata) *mock.Call {
 	return o.On("KeyspaceMetadata", keyspace.Name).Return(keyspace, nil)
 }
@@ -5,15 +5,123 @@ import (
 	"fmt"
 	"github.com/datastax/cassandra-data-apis/types"
 	"github.com/gocql/gocql"
+	"regexp"
+	"sort"
+	"strings"
 )
 
 type SelectInfo struct {
-	Keyspace string
-	Table    string
-	Columns  []string
-	Where    []types.ConditionItem
-	Options  *types.QueryOptions
-	OrderBy  []ColumnOrder
+	Keyspace   string
+	Table      string
+	Columns    []SelectColumn
+	Where      []types.ConditionItem
+	Options    *types.QueryOptions
+	OrderBy    []ColumnOrder
+	Aggregates []Aggregate
+	GroupBy    []string
+}
+
+// SelectColumn represents a column in the selection, optionally retrieving the column metadata instead of the value
+type SelectColumn struct {
+	Name string
+	// Function is the name of the metadata function to apply to the column: WRITETIME or TTL. When empty, the value of
+	// the column is selected
+	Function string
+	Alias    string
+}
+
+var metadataFunctions = map[string]bool{
+	"WRITETIME": true,
+	"TTL":       true,
+}
+
+// HasCellMetadata determines whether the WRITETIME and TTL of the column can be selected, the metadata is not available
+// for primary key columns, multi-cell collections and counters
+func HasCellMetadata(column *gocql.ColumnMetadata) bool {
+	if column.Kind == gocql.ColumnPartitionKey || column.Kind == gocql.ColumnClusteringKey {
+		return false
+	}
+
+	switch column.Type.Type() {
+	case gocql.TypeList, gocql.TypeSet, gocql.TypeMap, gocql.TypeCounter:
+		return false
+	}
+	return true
+}
+
+// NewSelectColumns returns the selection of the values of the provided columns
+func NewSelectColumns(names ...string) []SelectColumn {
+	columns := make([]SelectColumn, 0, len(names))
+	for _, name := range names {
+		columns = append(columns, SelectColumn{Name: name})
+	}
+	return columns
+}
+
+// TableColumnNames returns the names of the table columns: partition keys, clustering keys and then the rest of the
+// columns sorted by name
+func TableColumnNames(table *gocql.TableMetadata) []string {
+	columns := make([]string, 0, len(table.Columns))
+	for _, column := range table.PartitionKey {
+		columns = append(columns, column.Name)
+	}
+	for _, column := range table.ClusteringColumns {
+		columns = append(columns, column.Name)
+	}
+
+	regular := make([]string, 0, len(table.Columns))
+	for name, column := range table.Columns {
+		if column.Kind != gocql.ColumnPartitionKey && column.Kind != gocql.ColumnClusteringKey {
+			regular = append(regular, name)
+		}
+	}
+	sort.Strings(regular)
+
+	return append(columns, regular...)
+}
+
+// Aggregate represents an aggregate function in the selection
+type Aggregate struct {
+	// Function is the name of the function: COUNT, MIN, MAX, SUM or AVG
+	Function string
+	// Column is the name of the column to aggregate, it can only be empty for COUNT(*)
+	Column string
+	Alias  string
+}
+
+// aggregateFunctions contains the supported aggregate functions
+var aggregateFunctions = map[string]bool{
+	"COUNT": true,
+	"MIN":   true,
+	"MAX":   true,
+	"SUM":   true,
+	"AVG":   true,
+}
+
+// aliasRegex matches the aliases that can be provided by the clients
+var aliasRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// ValidateAlias checks that an alias provided by the client only contains letters, digits and underscores
+func ValidateAlias(alias string) error {
+	if !aliasRegex.MatchString(alias) {
+		return fmt.Errorf("alias '%s' is not valid, only letters, digits and underscores are allowed", alias)
+	}
+	return nil
+}
+
+// ValidateAggregate checks that the aggregate function is supported, the name is case-insensitive, and that the column
+// is only omitted for COUNT(*)
+func ValidateAggregate(function string, column string) error {
+	name := strings.ToUpper(function)
+	if !aggregateFunctions[name] {
+		return fmt.Errorf("aggregate function '%s' not supported", function)
+	}
+
+	if column == "" && name != "COUNT" {
+		return fmt.Errorf("aggregate function '%s' requires a column", function)
+	}
+
+	return nil
 }
 
 type InsertInfo struct {
@@ -23,6 +131,8 @@ type InsertInfo struct {
 	QueryParams []interface{}
 	IfNotExists bool
 	TTL         int
+	// Timestamp is the write timestamp in microseconds, when 0 the timestamp is generated by the server
+	Timestamp int64
 }
 
 type DeleteInfo struct {
@@ -32,6 +142,8 @@ type DeleteInfo struct {
 	QueryParams []interface{}
 	IfCondition []types.ConditionItem
 	IfExists    bool
+	// Timestamp is the write timestamp in microseconds, when 0 the timestamp is generated by the server
+	Timestamp int64
 }
 
 type UpdateInfo struct {
@@ -42,6 +154,8 @@ type UpdateInfo struct {
 	IfCondition []types.ConditionItem
 	IfExists    bool
 	TTL         int
+	// Timestamp is the write timestamp in microseconds, when 0 the timestamp is generated by the server
+	Timestamp int64
 }
 
 type ColumnOrder struct {
@@ -50,23 +164,86 @@ type ColumnOrder struct {
 }
 
 func (db *Db) Select(info *SelectInfo, options *QueryOptions) (ResultSet, error) {
+	query, values, err := buildSelectQuery(info)
+	if err != nil {
+		return nil, err
+	}
+	return db.session.ExecuteIter(query, options, values...)
+}
+
+// SelectIter executes the select query and returns an iterator that scans the rows of the page lazily
+func (db *Db) SelectIter(info *SelectInfo, options *QueryOptions) (RowIterator, error) {
+	query, values, err := buildSelectQuery(info)
+	if err != nil {
+		return nil, err
+	}
+	return db.session.ExecuteRowIterator(query, options, values...)
+}
+
+// ValidateGroupBy checks that the columns are the full partition key optionally followed by clustering columns, in the
+// order declared in the primary key
+func ValidateGroupBy(table *gocql.TableMetadata, groupBy []string) error {
+	if len(groupBy) == 0 {
+		return nil
+	}
+
+	if len(groupBy) < len(table.PartitionKey) {
+		return errors.New("group by must include all the partition key columns")
+	}
+
+	primaryKey := append(append([]*gocql.ColumnMetadata{}, table.PartitionKey...), table.ClusteringColumns...)
+	if len(groupBy) > len(primaryKey) {
+		return errors.New("group by can only include primary key columns")
+	}
+
+	for i, columnName := range groupBy {
+		if primaryKey[i].Name != columnName {
+			return fmt.Errorf("group by columns must follow the primary key order, expected '%s' but found '%s'",
+				primaryKey[i].Name, columnName)
+		}
+	}
+
+	return nil
+}
+
+func buildSelectQuery(info *SelectInfo) (string, []interface{}, error) {
 	values := make([]interface{}, 0, len(info.Where))
 	whereClause := buildCondition(info.Where, &values)
-	columns := "  *"
 
-	if len(info.Columns) > 0 {
-		columns = ""
-		for _, columnName := range info.Columns {
-			columns += fmt.Sprintf(`, "%s"`, columnName)
+	columns, err := buildSelectInfoColumns(info.Columns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(info.Aggregates) > 0 {
+		aggregates, err := buildAggregates(info.Aggregates)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if len(info.Columns) > 0 {
+			columns += ", " + aggregates
+		} else {
+			columns = aggregates
 		}
 	}
 
-	query := fmt.Sprintf(`SELECT %s FROM "%s"."%s"`, columns[2:], info.Keyspace, info.Table)
+	query := fmt.Sprintf(`SELECT %s FROM "%s"."%s"`, columns, info.Keyspace, info.Table)
 
 	if whereClause != "" {
 		query += fmt.Sprintf(" WHERE %s", whereClause)
 	}
 
+	if len(info.GroupBy) > 0 {
+		query += " GROUP BY "
+		for i, columnName := range info.GroupBy {
+			if i > 0 {
+				query += ", "
+			}
+			query += QuoteIdentifier(columnName)
+		}
+	}
+
 	if len(info.OrderBy) > 0 {
 		query += " ORDER BY "
 		for i, order := range info.OrderBy {
@@ -82,10 +259,96 @@ func (db *Db) Select(info *SelectInfo, options *QueryOptions) (ResultSet, error)
 		values = append(values, info.Options.Limit)
 	}
 
-	return db.session.ExecuteIter(query, options, values...)
+	return query, values, nil
+}
+
+func buildSelectInfoColumns(columns []SelectColumn) (string, error) {
+	if len(columns) == 0 {
+		return "*", nil
+	}
+
+	result := ""
+	for _, column := range columns {
+		if column.Function == "" {
+			result += ", " + QuoteIdentifier(column.Name)
+		} else {
+			function := strings.ToUpper(column.Function)
+			if !metadataFunctions[function] {
+				return "", fmt.Errorf("function '%s' not supported", column.Function)
+			}
+			result += fmt.Sprintf(`, %s(%s)`, function, QuoteIdentifier(column.Name))
+		}
+
+		if column.Alias != "" {
+			result += " AS " + QuoteIdentifier(column.Alias)
+		}
+	}
+
+	// Remove the initial ", " token
+	return result[2:], nil
+}
+
+func buildAggregates(aggregates []Aggregate) (string, error) {
+	result := ""
+	for _, aggregate := range aggregates {
+		if err := ValidateAggregate(aggregate.Function, aggregate.Column); err != nil {
+			return "", err
+		}
+
+		column := "*"
+		if aggregate.Column != "" {
+			column = QuoteIdentifier(aggregate.Column)
+		}
+
+		result += fmt.Sprintf(", %s(%s)", strings.ToUpper(aggregate.Function), column)
+		if aggregate.Alias != "" {
+			result += " AS " + QuoteIdentifier(aggregate.Alias)
+		}
+	}
+
+	// Remove the initial ", " token
+	return result[2:], nil
+}
+
+func buildSelectColumns(columnNames []string) string {
+	if len(columnNames) == 0 {
+		return "*"
+	}
+
+	columns := ""
+	for _, columnName := range columnNames {
+		columns += fmt.Sprintf(`, "%s"`, columnName)
+	}
+
+	// Remove the initial ", " token
+	return columns[2:]
 }
 
 func (db *Db) Insert(info *InsertInfo, options *QueryOptions) (ResultSet, error) {
+	query, queryParameters := buildInsertQuery(info)
+	return db.session.ExecuteIter(query, options, queryParameters...)
+}
+
+// InsertBatch executes the inserts in a single unlogged batch, it should only be used with inserts targeting the same
+// partition
+func (db *Db) InsertBatch(infos []*InsertInfo, options *QueryOptions) (ResultSet, error) {
+	if len(infos) == 0 {
+		return nil, errors.New("batch must include at least one insert")
+	}
+
+	query := "BEGIN UNLOGGED BATCH "
+	queryParameters := make([]interface{}, 0)
+	for _, info := range infos {
+		insertQuery, insertParameters := buildInsertQuery(info)
+		query += insertQuery + "; "
+		queryParameters = append(queryParameters, insertParameters...)
+	}
+	query += "APPLY BATCH"
+
+	return db.session.ExecuteIter(query, options, queryParameters...)
+}
+
+func buildInsertQuery(info *InsertInfo) (string, []interface{}) {
 	placeholders := ""
 	columns := ""
 	for _, columnName := range info.Columns {
@@ -102,19 +365,50 @@ func (db *Db) Insert(info *InsertInfo, options *QueryOptions) (ResultSet, error)
 		query += " IF NOT EXISTS"
 	}
 
-	if info.TTL >= 0 {
-		query += " USING TTL ?"
-		info.QueryParams = append(info.QueryParams, info.TTL)
+	queryParameters := make([]interface{}, len(info.QueryParams), len(info.QueryParams)+2)
+	copy(queryParameters, info.QueryParams)
+	query += buildUsingClause(info.TTL, info.Timestamp, &queryParameters)
+
+	return query, queryParameters
+}
+
+// ValidateTimestamp checks that a client-supplied write timestamp is not combined with a condition (IF, IF EXISTS or
+// IF NOT EXISTS), Cassandra doesn't allow custom timestamps in conditional updates
+func ValidateTimestamp(timestamp int64, conditional bool) error {
+	if timestamp != 0 && conditional {
+		return errors.New("a timestamp can not be used along with conditions")
 	}
+	return nil
+}
 
-	return db.session.ExecuteIter(query, options, info.QueryParams...)
+// buildUsingClause returns the USING clause for the TTL (when greater or equal than 0) and the write timestamp (when
+// not 0), appending the parameters
+func buildUsingClause(ttl int, timestamp int64, queryParameters *[]interface{}) string {
+	clause := ""
+	if ttl >= 0 {
+		clause += " AND TTL ?"
+		*queryParameters = append(*queryParameters, ttl)
+	}
+
+	if timestamp != 0 {
+		clause += " AND TIMESTAMP ?"
+		*queryParameters = append(*queryParameters, timestamp)
+	}
+
+	if clause == "" {
+		return ""
+	}
+
+	// Replace the initial " AND" token
+	return " USING" + clause[4:]
 }
 
 func (db *Db) Delete(info *DeleteInfo, options *QueryOptions) (ResultSet, error) {
 	whereClause := buildWhereClause(info.Columns)
-	query := fmt.Sprintf(`DELETE FROM "%s"."%s" WHERE %s`, info.Keyspace, info.Table, whereClause)
-	queryParameters := make([]interface{}, len(info.QueryParams))
-	copy(queryParameters, info.QueryParams)
+	queryParameters := make([]interface{}, 0, len(info.QueryParams)+1)
+	using := buildUsingClause(-1, info.Timestamp, &queryParameters)
+	queryParameters = append(queryParameters, info.QueryParams...)
+	query := fmt.Sprintf(`DELETE FROM "%s"."%s"%s WHERE %s`, info.Keyspace, info.Table, using, whereClause)
 
 	if info.IfExists {
 		query += " IF EXISTS"
@@ -152,11 +446,7 @@ func (db *Db) Update(info *UpdateInfo, options *QueryOptions) (ResultSet, error)
 
 	queryParameters := make([]interface{}, 0, len(info.QueryParams))
 
-	ttl := ""
-	if info.TTL >= 0 {
-		ttl = " USING TTL ?"
-		queryParameters = append(queryParameters, info.TTL)
-	}
+	using := buildUsingClause(info.TTL, info.Timestamp, &queryParameters)
 
 	for _, v := range setParameters {
 		queryParameters = append(queryParameters, v)
@@ -171,7 +461,7 @@ func (db *Db) Update(info *UpdateInfo, options *QueryOptions) (ResultSet, error)
 	setClause = setClause[2:]
 
 	query := fmt.Sprintf(
-		`UPDATE "%s"."%s"%s SET %s WHERE %s`, info.Keyspace, info.Table.Name, ttl, setClause, whereClause)
+		`UPDATE "%s"."%s"%s SET %s WHERE %s`, info.Keyspace, info.Table.Name, using, setClause, whereClause)
 
 	if info.IfExists {
 		query += " IF EXISTS"
@@ -192,6 +482,21 @@ func buildWhereClause(columnNames []string) string {
 	return whereClause[5:]
 }
 
+func buildColumnTuple(tuple types.ColumnTuple, operator string) string {
+	columns := make([]string, 0, len(tuple.Columns))
+	markers := make([]string, 0, len(tuple.Columns))
+	for _, name := range tuple.Columns {
+		columns = append(columns, fmt.Sprintf(`"%s"`, name))
+		markers = append(markers, "?")
+	}
+
+	if tuple.Token {
+		return fmt.Sprintf("token(%s) %s token(%s)",
+			strings.Join(columns, ", "), operator, strings.Join(markers, ", "))
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), operator, strings.Join(markers, ", "))
+}
+
 func buildCondition(condition []types.ConditionItem, queryParameters *[]interface{}) string {
 	if len(condition) == 0 {
 		return ""
@@ -199,6 +504,16 @@ func buildCondition(condition []types.ConditionItem, queryParameters *[]interfac
 
 	conditionClause := ""
 	for _, item := range condition {
+		if entry, ok := item.Value.(types.MapEntry); ok {
+			conditionClause += fmt.Sprintf(` AND "%s"[?] %s ?`, item.Column, item.Operator)
+			*queryParameters = append(*queryParameters, entry.Key, entry.Value)
+			continue
+		}
+		if tuple, ok := item.Value.(types.ColumnTuple); ok {
+			conditionClause += " AND " + buildColumnTuple(tuple, item.Operator)
+			*queryParameters = append(*queryParameters, tuple.Values...)
+			continue
+		}
 		conditionClause += fmt.Sprintf(` AND "%s" %s ?`, item.Column, item.Operator)
 		*queryParameters = append(*queryParameters, item.Value)
 	}
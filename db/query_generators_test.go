@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
+	"math"
 	"testing"
 )
 
@@ -198,6 +199,99 @@ var _ = Describe("db", func() {
 		}
 	})
 
+	Describe("InsertBatch", func() {
+		It("Should generate an unlogged BATCH statement", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(ResultMock{}, nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			_, err := db.InsertBatch([]*InsertInfo{
+				{Keyspace: "ks1", Table: "tbl1", Columns: []string{"a", "b"}, QueryParams: []interface{}{1, 2}, TTL: -1},
+				{Keyspace: "ks1", Table: "tbl1", Columns: []string{"a", "b"}, QueryParams: []interface{}{1, 3}, TTL: 60},
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`BEGIN UNLOGGED BATCH INSERT INTO "ks1"."tbl1" ("a", "b") VALUES (?, ?); `+
+					`INSERT INTO "ks1"."tbl1" ("a", "b") VALUES (?, ?) USING TTL ?; APPLY BATCH`,
+				mock.Anything, []interface{}{1, 2, 1, 3, 60})
+		})
+
+		It("Should return an error when there are no inserts", func() {
+			db := &Db{
+				session: &SessionMock{},
+			}
+
+			_, err := db.InsertBatch(nil, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Write timestamp", func() {
+		var sessionMock *SessionMock
+		var db *Db
+
+		BeforeEach(func() {
+			sessionMock = &SessionMock{}
+			sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(ResultMock{}, nil)
+			db = &Db{
+				session: sessionMock,
+			}
+		})
+
+		It("Should generate INSERT statement with USING TTL and TIMESTAMP", func() {
+			_, err := db.Insert(&InsertInfo{
+				Keyspace:    "ks1",
+				Table:       "tbl1",
+				Columns:     []string{"a", "b"},
+				QueryParams: []interface{}{1, 2},
+				TTL:         60,
+				Timestamp:   1589932350000000,
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`INSERT INTO "ks1"."tbl1" ("a", "b") VALUES (?, ?) USING TTL ? AND TIMESTAMP ?`, mock.Anything,
+				[]interface{}{1, 2, 60, int64(1589932350000000)})
+		})
+
+		It("Should generate UPDATE statement with USING TIMESTAMP", func() {
+			table := &gocql.TableMetadata{
+				Name: "tbl1",
+				Columns: map[string]*gocql.ColumnMetadata{
+					"pk1": {Name: "pk1", Kind: gocql.ColumnPartitionKey},
+				},
+			}
+			_, err := db.Update(&UpdateInfo{
+				Keyspace:    "ks1",
+				Table:       table,
+				Columns:     []string{"pk1", "a"},
+				QueryParams: []interface{}{1, 2},
+				TTL:         -1,
+				Timestamp:   1589932350000000,
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`UPDATE "ks1"."tbl1" USING TIMESTAMP ? SET "a" = ? WHERE "pk1" = ?`, mock.Anything,
+				[]interface{}{int64(1589932350000000), 2, 1})
+		})
+
+		It("Should generate DELETE statement with USING TIMESTAMP", func() {
+			_, err := db.Delete(&DeleteInfo{
+				Keyspace:    "ks1",
+				Table:       "tbl1",
+				Columns:     []string{"a"},
+				QueryParams: []interface{}{1},
+				Timestamp:   1589932350000000,
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`DELETE FROM "ks1"."tbl1" USING TIMESTAMP ? WHERE "a" = ?`, mock.Anything,
+				[]interface{}{int64(1589932350000000), 1})
+		})
+	})
+
 	Describe("Select", func() {
 		items := []struct {
 			description string
@@ -258,7 +352,7 @@ var _ = Describe("db", func() {
 				_, err := db.Select(&SelectInfo{
 					Keyspace: "ks1",
 					Table:    "tbl1",
-					Columns:  item.columns,
+					Columns:  NewSelectColumns(item.columns...),
 					Where:    item.where,
 					Options:  item.options,
 					OrderBy:  item.orderBy,
@@ -269,6 +363,786 @@ var _ = Describe("db", func() {
 			})
 		}
 	})
+
+	Describe("Select with multi-column conditions", func() {
+		It("Should generate SELECT statement with tuple and token relations", func() {
+			resultMock := &ResultMock{}
+			resultMock.
+				On("PageState").Return([]byte{}).
+				On("Values").Return([]map[string]interface{}{}, nil)
+			sessionMock := SessionMock{}
+			sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(resultMock, nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			_, err := db.Select(&SelectInfo{
+				Keyspace: "ks1",
+				Table:    "tbl1",
+				Where: []types.ConditionItem{
+					{Column: "a", Operator: "=", Value: 1},
+					{Column: "b, c", Operator: ">", Value: types.ColumnTuple{
+						Columns: []string{"b", "c"},
+						Values:  []interface{}{2, 3},
+					}},
+				},
+				Options: &types.QueryOptions{},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`SELECT * FROM "ks1"."tbl1" WHERE "a" = ? AND ("b", "c") > (?, ?)`, mock.Anything,
+				[]interface{}{1, 2, 3})
+
+			_, err = db.Select(&SelectInfo{
+				Keyspace: "ks1",
+				Table:    "tbl1",
+				Where: []types.ConditionItem{
+					{Column: "a, b", Operator: ">", Value: types.ColumnTuple{
+						Columns: []string{"a", "b"},
+						Values:  []interface{}{1, 2},
+						Token:   true,
+					}},
+				},
+				Options: &types.QueryOptions{Limit: 10},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`SELECT * FROM "ks1"."tbl1" WHERE token("a", "b") > token(?, ?) LIMIT ?`, mock.Anything,
+				[]interface{}{1, 2, 10})
+		})
+	})
+
+	Describe("Select with aggregates", func() {
+		items := []struct {
+			description string
+			columns     []string
+			aggregates  []Aggregate
+			groupBy     []string
+			query       string
+		}{
+			{"COUNT(*)", nil, []Aggregate{{Function: "COUNT"}},
+				nil, `SELECT COUNT(*) FROM "ks1"."tbl1" WHERE "a" = ?`},
+			{"aliases", nil, []Aggregate{{Function: "count", Alias: "c"}, {Function: "max", Column: "b", Alias: "m"}},
+				nil, `SELECT COUNT(*) AS "c", MAX("b") AS "m" FROM "ks1"."tbl1" WHERE "a" = ?`},
+			{"quoted identifiers", nil, []Aggregate{{Function: "max", Column: `b"`, Alias: `x" FROM "ks2"."tbl2" --`}},
+				nil, `SELECT MAX("b""") AS "x"" FROM ""ks2"".""tbl2"" --" FROM "ks1"."tbl1" WHERE "a" = ?`},
+			{"GROUP BY", []string{"a", "ck"}, []Aggregate{{Function: "SUM", Column: "b"}, {Function: "AVG", Column: "b"}},
+				[]string{"a", "ck"},
+				`SELECT "a", "ck", SUM("b"), AVG("b") FROM "ks1"."tbl1" WHERE "a" = ? GROUP BY "a", "ck"`},
+		}
+
+		for i := 0; i < len(items); i++ {
+			// Capture the item in the closure
+			item := items[i]
+
+			It("Should generate SELECT statement with "+item.description, func() {
+				sessionMock := SessionMock{}
+				sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(ResultMock{}, nil)
+				db := &Db{
+					session: &sessionMock,
+				}
+
+				_, err := db.Select(&SelectInfo{
+					Keyspace:   "ks1",
+					Table:      "tbl1",
+					Columns:    NewSelectColumns(item.columns...),
+					Where:      []types.ConditionItem{{Column: "a", Operator: "=", Value: 1}},
+					Aggregates: item.aggregates,
+					GroupBy:    item.groupBy,
+				}, nil)
+				Expect(err).NotTo(HaveOccurred())
+				sessionMock.AssertCalled(GinkgoT(), "ExecuteIter", item.query, mock.Anything, []interface{}{1})
+			})
+		}
+
+		It("Should return an error when the function is not supported", func() {
+			db := &Db{
+				session: &SessionMock{},
+			}
+
+			_, err := db.Select(&SelectInfo{
+				Keyspace:   "ks1",
+				Table:      "tbl1",
+				Aggregates: []Aggregate{{Function: "MEDIAN", Column: "b"}},
+			}, nil)
+			Expect(err).To(HaveOccurred())
+
+			_, err = db.Select(&SelectInfo{
+				Keyspace:   "ks1",
+				Table:      "tbl1",
+				Aggregates: []Aggregate{{Function: "MAX"}},
+			}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should validate the aggregate functions ignoring the case", func() {
+			Expect(ValidateAggregate("count", "")).NotTo(HaveOccurred())
+			Expect(ValidateAggregate("Avg", "b")).NotTo(HaveOccurred())
+			Expect(ValidateAggregate("median", "b")).To(MatchError("aggregate function 'median' not supported"))
+			Expect(ValidateAggregate("sum", "")).To(MatchError("aggregate function 'sum' requires a column"))
+		})
+
+		It("Should validate the aliases", func() {
+			Expect(ValidateAlias("max_pages")).NotTo(HaveOccurred())
+			Expect(ValidateAlias(`x" FROM "system_auth"."roles" --`)).To(HaveOccurred())
+			Expect(ValidateAlias("count(*)")).To(HaveOccurred())
+		})
+	})
+
+	Describe("Select with metadata columns", func() {
+		It("Should generate SELECT statement with WRITETIME and TTL", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(ResultMock{}, nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			columns := append(NewSelectColumns("a", "b"),
+				SelectColumn{Name: "b", Function: "WRITETIME", Alias: "writetime(b)"},
+				SelectColumn{Name: "b", Function: "ttl"})
+			_, err := db.Select(&SelectInfo{
+				Keyspace: "ks1",
+				Table:    "tbl1",
+				Columns:  columns,
+				Where:    []types.ConditionItem{{Column: "a", Operator: "=", Value: 1}},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`SELECT "a", "b", WRITETIME("b") AS "writetime(b)", TTL("b") FROM "ks1"."tbl1" WHERE "a" = ?`,
+				mock.Anything, []interface{}{1})
+		})
+
+		It("Should quote the metadata columns and aliases", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ExecuteIter", mock.Anything, mock.Anything, mock.Anything).Return(ResultMock{}, nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			_, err := db.Select(&SelectInfo{
+				Keyspace: "ks1",
+				Table:    "tbl1",
+				Columns:  []SelectColumn{{Name: `b"`, Function: "TTL", Alias: `x" FROM "ks2"."tbl2" --`}},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteIter",
+				`SELECT TTL("b""") AS "x"" FROM ""ks2"".""tbl2"" --" FROM "ks1"."tbl1"`, mock.Anything, []interface{}{})
+		})
+
+		It("Should return an error when the function is not supported", func() {
+			db := &Db{
+				session: &SessionMock{},
+			}
+
+			_, err := db.Select(&SelectInfo{
+				Keyspace: "ks1",
+				Table:    "tbl1",
+				Columns:  []SelectColumn{{Name: "b", Function: "TOKEN"}},
+			}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ValidateGroupBy", func() {
+		table := &gocql.TableMetadata{
+			Name: "tbl1",
+			PartitionKey: []*gocql.ColumnMetadata{
+				{Name: "pk1", Kind: gocql.ColumnPartitionKey}, {Name: "pk2", Kind: gocql.ColumnPartitionKey}},
+			ClusteringColumns: []*gocql.ColumnMetadata{
+				{Name: "ck1", Kind: gocql.ColumnClusteringKey}, {Name: "ck2", Kind: gocql.ColumnClusteringKey}},
+		}
+
+		It("Should allow primary key prefixes", func() {
+			Expect(ValidateGroupBy(table, nil)).To(Succeed())
+			Expect(ValidateGroupBy(table, []string{"pk1", "pk2"})).To(Succeed())
+			Expect(ValidateGroupBy(table, []string{"pk1", "pk2", "ck1"})).To(Succeed())
+			Expect(ValidateGroupBy(table, []string{"pk1", "pk2", "ck1", "ck2"})).To(Succeed())
+		})
+
+		It("Should return an error when the columns are not a primary key prefix", func() {
+			Expect(ValidateGroupBy(table, []string{"pk1"})).NotTo(Succeed())
+			Expect(ValidateGroupBy(table, []string{"pk2", "pk1"})).NotTo(Succeed())
+			Expect(ValidateGroupBy(table, []string{"pk1", "pk2", "ck2"})).NotTo(Succeed())
+			Expect(ValidateGroupBy(table, []string{"pk1", "pk2", "ck1", "ck2", "c"})).NotTo(Succeed())
+		})
+	})
+
+	Describe("SelectIter", func() {
+		It("Should generate the same SELECT statement as Select", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ExecuteRowIterator", mock.Anything, mock.Anything, mock.Anything).
+				Return(NewRowIteratorMock(nil, nil, nil), nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			_, err := db.SelectIter(&SelectInfo{
+				Keyspace: "ks1",
+				Table:    "tbl1",
+				Columns:  NewSelectColumns("a", "b"),
+				Where:    []types.ConditionItem{{Column: "a", Operator: "=", Value: 1}},
+				Options:  &types.QueryOptions{Limit: 10},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ExecuteRowIterator",
+				`SELECT "a", "b" FROM "ks1"."tbl1" WHERE "a" = ? LIMIT ?`, mock.Anything, []interface{}{1, 10})
+		})
+	})
+
+	Describe("SelectTokenRange", func() {
+		items := []struct {
+			description  string
+			partitionKey []string
+			columns      []string
+			query        string
+		}{
+			{"a single partition key", []string{"a"}, nil,
+				`SELECT * FROM "ks1"."tbl1" WHERE token("a") > ? AND token("a") <= ?`},
+			{"a composite partition key", []string{"a", "B"}, nil,
+				`SELECT * FROM "ks1"."tbl1" WHERE token("a", "B") > ? AND token("a", "B") <= ?`},
+			{"select columns", []string{"a"}, []string{"a", "col1"},
+				`SELECT "a", "col1" FROM "ks1"."tbl1" WHERE token("a") > ? AND token("a") <= ?`},
+		}
+
+		for i := 0; i < len(items); i++ {
+			// Capture the item in the closure
+			item := items[i]
+
+			It("Should generate SELECT statement with "+item.description, func() {
+				sessionMock := SessionMock{}
+				sessionMock.On("ExecuteRowIterator", mock.Anything, mock.Anything, mock.Anything).
+					Return(NewRowIteratorMock(nil, nil, nil), nil)
+				db := &Db{
+					session: &sessionMock,
+				}
+
+				partitionKey := make([]*gocql.ColumnMetadata, 0, len(item.partitionKey))
+				for _, name := range item.partitionKey {
+					partitionKey = append(partitionKey, &gocql.ColumnMetadata{Name: name, Kind: gocql.ColumnPartitionKey})
+				}
+
+				_, err := db.SelectTokenRange(&TokenRangeSelectInfo{
+					Keyspace: "ks1",
+					Table:    &gocql.TableMetadata{Name: "tbl1", PartitionKey: partitionKey},
+					Columns:  item.columns,
+					Range:    TokenRange{Start: -10, End: 20},
+				}, nil)
+				Expect(err).NotTo(HaveOccurred())
+				sessionMock.AssertCalled(GinkgoT(), "ExecuteRowIterator", item.query, mock.Anything,
+					[]interface{}{int64(-10), int64(20)})
+			})
+		}
+	})
+
+	Describe("CreateIndex", func() {
+		items := []struct {
+			description string
+			info        CreateIndexInfo
+			query       string
+		}{
+			{
+				"a regular column",
+				CreateIndexInfo{Column: "a"},
+				`CREATE INDEX ON "ks1"."tbl1" ("a")`},
+			{
+				"name and IF NOT EXISTS",
+				CreateIndexInfo{Name: "idx1", Column: "a", IfNotExists: true},
+				`CREATE INDEX IF NOT EXISTS "idx1" ON "ks1"."tbl1" ("a")`},
+			{
+				"a collection target",
+				CreateIndexInfo{Column: "m", Target: "entries"},
+				`CREATE INDEX ON "ks1"."tbl1" (ENTRIES("m"))`},
+			{
+				"a storage-attached index",
+				CreateIndexInfo{Name: "idx1", Column: "a", Class: StorageAttachedIndex},
+				`CREATE CUSTOM INDEX "idx1" ON "ks1"."tbl1" ("a") USING 'StorageAttachedIndex'`},
+		}
+
+		for i := 0; i < len(items); i++ {
+			// Capture the item in the closure
+			item := items[i]
+
+			It("Should generate CREATE INDEX statement with "+item.description, func() {
+				sessionMock := SessionMock{}
+				sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+				db := &Db{
+					session: &sessionMock,
+				}
+
+				info := item.info
+				info.Keyspace = "ks1"
+				info.Table = "tbl1"
+				err := db.CreateIndex(&info, nil)
+				Expect(err).NotTo(HaveOccurred())
+				sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", item.query, mock.Anything)
+			})
+		}
+
+		It("Should return an error when the target is not valid", func() {
+			db := &Db{session: &SessionMock{}}
+			err := db.CreateIndex(&CreateIndexInfo{Keyspace: "ks1", Table: "tbl1", Column: "a", Target: "ALL"}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("DropIndex", func() {
+		It("Should generate DROP INDEX statement", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.DropIndex(&DropIndexInfo{Keyspace: "ks1", Name: "idx1", IfExists: true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", `DROP INDEX IF EXISTS "ks1"."idx1"`, mock.Anything)
+		})
+	})
+
+	Describe("Indexes", func() {
+		It("Should parse the index targets", func() {
+			sessionMock := SessionMock{}
+			sessionMock.AddIndexes([]*IndexMetadata{
+				{Table: "tbl1", Name: "idx2", Column: "Tags", Target: IndexTargetKeys},
+				{Table: "tbl1", Name: "idx1", Column: "a", Class: StorageAttachedIndex},
+				{Table: "tbl2", Name: "idx3", Column: "b"},
+			})
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			indexes, err := db.Indexes("ks1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(indexes).To(Equal(map[string][]*IndexMetadata{
+				"tbl1": {
+					{Table: "tbl1", Name: "idx1", Column: "a", Class: StorageAttachedIndex},
+					{Table: "tbl1", Name: "idx2", Column: "Tags", Target: IndexTargetKeys},
+				},
+				"tbl2": {{Table: "tbl2", Name: "idx3", Column: "b"}},
+			}))
+		})
+	})
+
+	Describe("CreateView", func() {
+		items := []struct {
+			description string
+			info        CreateViewInfo
+			query       string
+		}{
+			{
+				"all columns",
+				CreateViewInfo{PartitionKeys: []string{"b"}, ClusteringKeys: []ColumnOrder{{Column: "a"}}},
+				`CREATE MATERIALIZED VIEW "ks1"."view1" AS SELECT * FROM "ks1"."tbl1" ` +
+					`WHERE "b" IS NOT NULL AND "a" IS NOT NULL PRIMARY KEY (("b"), "a")`},
+			{
+				"selected columns, where clause and clustering order",
+				CreateViewInfo{
+					Columns:        []string{"a", "b", "c"},
+					PartitionKeys:  []string{"b"},
+					ClusteringKeys: []ColumnOrder{{Column: "a", Order: "desc"}},
+					Where: []types.ConditionItem{
+						{Column: "a", Operator: ">", Value: float64(10)},
+						{Column: "b", Operator: "=", Value: "it's"},
+					},
+					IfNotExists: true,
+				},
+				`CREATE MATERIALIZED VIEW IF NOT EXISTS "ks1"."view1" AS SELECT "a", "b", "c" FROM "ks1"."tbl1" ` +
+					`WHERE "b" IS NOT NULL AND "a" IS NOT NULL AND "a" > 10 AND "b" = 'it''s' ` +
+					`PRIMARY KEY (("b"), "a") WITH CLUSTERING ORDER BY ("a" DESC)`},
+		}
+		viewTableMock := func(sessionMock *SessionMock) {
+			sessionMock.AddKeyspace(NewKeyspaceMock("ks1", map[string][]*gocql.ColumnMetadata{
+				"tbl1": {
+					{Name: "b", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+					{Name: "a", Kind: gocql.ColumnClusteringKey, Type: gocql.NewNativeType(0, gocql.TypeInt, "")},
+					{Name: "c", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeInt, "")},
+				},
+			}))
+		}
+
+		for i := 0; i < len(items); i++ {
+			// Capture the item in the closure
+			item := items[i]
+
+			It("Should generate CREATE MATERIALIZED VIEW statement with "+item.description, func() {
+				sessionMock := SessionMock{}
+				viewTableMock(&sessionMock)
+				sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+				db := &Db{
+					session: &sessionMock,
+				}
+
+				info := item.info
+				info.Keyspace = "ks1"
+				info.Name = "view1"
+				info.BaseTable = "tbl1"
+				err := db.CreateView(&info, nil)
+				Expect(err).NotTo(HaveOccurred())
+				sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", item.query, mock.Anything)
+			})
+		}
+
+		It("Should return an error when there are no partition keys", func() {
+			db := &Db{session: &SessionMock{}}
+			err := db.CreateView(&CreateViewInfo{Keyspace: "ks1", Name: "view1", BaseTable: "tbl1"}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should return an error when the restrictions are not valid", func() {
+			sessionMock := SessionMock{}
+			viewTableMock(&sessionMock)
+			db := &Db{session: &sessionMock}
+			for where, message := range map[types.ConditionItem]string{
+				{Column: "c", Operator: ">", Value: float64(10)}: "view restrictions are only supported on " +
+					"primary key columns, 'c' is not",
+				{Column: "d", Operator: "=", Value: float64(10)}: "column 'd' not found in table 'tbl1'",
+				{Column: "a", Operator: "!=", Value: float64(10)}: "operator '!=' is not supported in view " +
+					"restrictions",
+				{Column: "a", Operator: ">", Value: `10 PRIMARY KEY ("b"); DROP TABLE "ks1"."tbl1"`}: "invalid " +
+					`value for column 'a': '10 PRIMARY KEY ("b"); DROP TABLE "ks1"."tbl1"' is not a valid int`,
+			} {
+				err := db.CreateView(&CreateViewInfo{
+					Keyspace:      "ks1",
+					Name:          "view1",
+					BaseTable:     "tbl1",
+					PartitionKeys: []string{"b"},
+					Where:         []types.ConditionItem{where},
+				}, nil)
+				Expect(err).To(MatchError(message))
+			}
+			sessionMock.AssertNotCalled(GinkgoT(), "ChangeSchema", mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("DropView", func() {
+		It("Should generate DROP MATERIALIZED VIEW statement", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.DropView(&DropViewInfo{Keyspace: "ks1", Name: "view1"}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", `DROP MATERIALIZED VIEW "ks1"."view1"`, mock.Anything)
+		})
+	})
+
+	Describe("DescribeViews", func() {
+		It("Should return the view definitions", func() {
+			view := &ViewMetadata{
+				Keyspace:       "ks1",
+				Name:           "view1",
+				BaseTable:      "tbl1",
+				Columns:        []string{"b", "a", "c"},
+				PartitionKeys:  []string{"b"},
+				ClusteringKeys: []ColumnOrder{{Column: "a", Order: "DESC"}},
+				WhereClause:    "b IS NOT NULL AND a IS NOT NULL",
+			}
+			sessionMock := SessionMock{}
+			sessionMock.AddViewsMetadata([]*ViewMetadata{view})
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			views, err := db.DescribeViews("ks1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(views).To(Equal([]*ViewMetadata{view}))
+		})
+	})
+
+	Describe("User-defined types", func() {
+		fields := []*gocql.ColumnMetadata{
+			{Name: "street", Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "zip", Type: gocql.NewNativeType(0, gocql.TypeInt, "")},
+		}
+
+		It("Should generate CREATE TYPE statement", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.CreateType(&CreateTypeInfo{Keyspace: "ks1", Name: "address", Fields: fields, IfNotExists: true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema",
+				`CREATE TYPE IF NOT EXISTS "ks1"."address" ("street" text, "zip" int)`, mock.Anything)
+		})
+
+		It("Should generate an ALTER TYPE statement per field", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.AlterTypeAdd(&AlterTypeAddInfo{Keyspace: "ks1", Name: "address", ToAdd: fields}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", `ALTER TYPE "ks1"."address" ADD "street" text`,
+				mock.Anything)
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", `ALTER TYPE "ks1"."address" ADD "zip" int`,
+				mock.Anything)
+		})
+
+		It("Should generate DROP TYPE statement", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.DropType(&DropTypeInfo{Keyspace: "ks1", Name: "address", IfExists: true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", `DROP TYPE IF EXISTS "ks1"."address"`, mock.Anything)
+		})
+
+		It("Should return an error when there are no fields", func() {
+			db := &Db{session: &SessionMock{}}
+			Expect(db.CreateType(&CreateTypeInfo{Keyspace: "ks1", Name: "address"}, nil)).To(HaveOccurred())
+		})
+	})
+
+	Describe("Table options", func() {
+		ttl := 3600
+		gcGrace := 86400
+		fpChance := 0.01
+		comment := "Books' catalog"
+		options := &TableOptions{
+			DefaultTimeToLive:   &ttl,
+			GcGraceSeconds:      &gcGrace,
+			BloomFilterFpChance: &fpChance,
+			Comment:             &comment,
+			Compaction:          map[string]string{"class": "LeveledCompactionStrategy", "sstable_size_in_mb": "160"},
+			Compression:         map[string]string{"class": "LZ4Compressor"},
+			Caching:             map[string]string{"keys": "ALL", "rows_per_partition": "NONE"},
+		}
+		properties := `default_time_to_live = 3600 AND gc_grace_seconds = 86400 AND bloom_filter_fp_chance = 0.01 ` +
+			`AND comment = 'Books'' catalog' ` +
+			`AND compaction = {'class': 'LeveledCompactionStrategy', 'sstable_size_in_mb': '160'} ` +
+			`AND compression = {'class': 'LZ4Compressor'} AND caching = {'keys': 'ALL', 'rows_per_partition': 'NONE'}`
+
+		It("Should generate CREATE TABLE statement with clustering order and options", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.CreateTable(&CreateTableInfo{
+				Keyspace:       "ks1",
+				Table:          "tbl1",
+				PartitionKeys:  []*gocql.ColumnMetadata{{Name: "a", Type: gocql.NewNativeType(0, gocql.TypeInt, "")}},
+				ClusteringKeys: []*gocql.ColumnMetadata{{Name: "b", Type: gocql.NewNativeType(0, gocql.TypeText, ""), ClusteringOrder: "DESC"}},
+				Options:        options,
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema",
+				`CREATE TABLE "ks1"."tbl1" ("a" int, "b" text, PRIMARY KEY (("a"), "b")) `+
+					`WITH CLUSTERING ORDER BY ("b" DESC) AND `+properties, mock.Anything)
+		})
+
+		It("Should generate ALTER TABLE statement", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.AlterTableOptions(&AlterTableOptionsInfo{Keyspace: "ks1", Table: "tbl1", Options: options}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", `ALTER TABLE "ks1"."tbl1" WITH `+properties,
+				mock.Anything)
+		})
+
+		It("Should return an error when there are no options to alter", func() {
+			db := &Db{session: &SessionMock{}}
+			err := db.AlterTableOptions(&AlterTableOptionsInfo{Keyspace: "ks1", Table: "tbl1", Options: &TableOptions{}}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should read the options from the schema tables", func() {
+			sessionMock := SessionMock{}
+			sessionMock.AddTableOptions(options)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			result, err := db.TableOptions("ks1", "tbl1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(options))
+		})
+	})
+
+	Describe("Keyspaces", func() {
+		durableWrites := false
+		items := []struct {
+			description string
+			info        CreateKeyspaceInfo
+			query       string
+		}{
+			{
+				"data center replicas",
+				CreateKeyspaceInfo{DCReplicas: map[string]int{"dc2": 1, "dc1": 3}},
+				`CREATE KEYSPACE "ks1" WITH REPLICATION = ` +
+					`{ 'class': 'NetworkTopologyStrategy', 'dc1': 3, 'dc2': 1 }`},
+			{
+				"replication factor and durable writes",
+				CreateKeyspaceInfo{ReplicationFactor: 1, DurableWrites: &durableWrites, IfNotExists: true},
+				`CREATE KEYSPACE IF NOT EXISTS "ks1" WITH REPLICATION = ` +
+					`{ 'class': 'SimpleStrategy', 'replication_factor': 1 } AND DURABLE_WRITES = false`},
+		}
+
+		for i := 0; i < len(items); i++ {
+			// Capture the item in the closure
+			item := items[i]
+
+			It("Should generate CREATE KEYSPACE statement with "+item.description, func() {
+				sessionMock := SessionMock{}
+				sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+				db := &Db{
+					session: &sessionMock,
+				}
+
+				info := item.info
+				info.Name = "ks1"
+				err := db.CreateKeyspace(&info, nil)
+				Expect(err).NotTo(HaveOccurred())
+				sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", item.query, mock.Anything)
+			})
+		}
+
+		It("Should return an error when there is no replication", func() {
+			db := &Db{session: &SessionMock{}}
+			Expect(db.CreateKeyspace(&CreateKeyspaceInfo{Name: "ks1"}, nil)).To(HaveOccurred())
+			Expect(db.CreateKeyspace(&CreateKeyspaceInfo{
+				Name:              "ks1",
+				DCReplicas:        map[string]int{"dc1": 3},
+				ReplicationFactor: 3,
+			}, nil)).To(HaveOccurred())
+		})
+
+		It("Should generate ALTER KEYSPACE statements", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			err := db.AlterKeyspace(&AlterKeyspaceInfo{Name: "ks1", DCReplicas: map[string]int{"dc1": 5}}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema",
+				`ALTER KEYSPACE "ks1" WITH REPLICATION = { 'class': 'NetworkTopologyStrategy', 'dc1': 5 }`,
+				mock.Anything)
+
+			err = db.AlterKeyspace(&AlterKeyspaceInfo{Name: "ks1", DurableWrites: &durableWrites}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "ChangeSchema", `ALTER KEYSPACE "ks1" WITH DURABLE_WRITES = false`,
+				mock.Anything)
+
+			Expect(db.AlterKeyspace(&AlterKeyspaceInfo{Name: "ks1"}, nil)).To(HaveOccurred())
+		})
+	})
+
+	Describe("Roles", func() {
+		It("Should generate CREATE ROLE and ALTER ROLE statements", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			password := "it's secret"
+			err := db.CreateRole(&CreateRoleInfo{Name: "role1", Password: &password, Login: true, IfNotExists: true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "Execute",
+				`CREATE ROLE IF NOT EXISTS "role1" WITH PASSWORD = 'it''s secret' AND LOGIN = true AND SUPERUSER = false`,
+				mock.Anything, mock.Anything)
+
+			login := false
+			err = db.AlterRole(&AlterRoleInfo{Name: "role1", Login: &login}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "Execute", `ALTER ROLE "role1" WITH LOGIN = false`,
+				mock.Anything, mock.Anything)
+
+			Expect(db.AlterRole(&AlterRoleInfo{Name: "role1"}, nil)).To(HaveOccurred())
+		})
+
+		It("Should generate GRANT and REVOKE statements", func() {
+			sessionMock := SessionMock{}
+			sessionMock.On("Execute", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			Expect(db.Grant(&PermissionInfo{Role: "role1", Permission: "select", Keyspace: "ks1", Table: "tbl1"},
+				nil)).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "Execute", `GRANT SELECT ON TABLE "ks1"."tbl1" TO "role1"`,
+				mock.Anything, mock.Anything)
+
+			Expect(db.Grant(&PermissionInfo{Role: "role1", Permission: "ALL"}, nil)).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "Execute", `GRANT ALL PERMISSIONS ON ALL KEYSPACES TO "role1"`,
+				mock.Anything, mock.Anything)
+
+			Expect(db.Revoke(&PermissionInfo{Role: "role1", Permission: "MODIFY", Keyspace: "ks1"},
+				nil)).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "Execute", `REVOKE MODIFY ON KEYSPACE "ks1" FROM "role1"`,
+				mock.Anything, mock.Anything)
+
+			Expect(db.Grant(&PermissionInfo{Role: `a"b`, Permission: "SELECT", Keyspace: `k"s`, Table: "tbl1"},
+				nil)).NotTo(HaveOccurred())
+			sessionMock.AssertCalled(GinkgoT(), "Execute", `GRANT SELECT ON TABLE "k""s"."tbl1" TO "a""b"`,
+				mock.Anything, mock.Anything)
+
+			Expect(db.Grant(&PermissionInfo{Role: "role1", Permission: "EXECUTE"}, nil)).To(HaveOccurred())
+			Expect(db.Grant(&PermissionInfo{Role: "role1", Permission: "SELECT", Table: "tbl1"}, nil)).To(HaveOccurred())
+		})
+
+		It("Should list the permissions of a role", func() {
+			role, select1, modify := "role1", "SELECT", "MODIFY"
+			tableResource, keyspaceResource := "<table ks1.tbl1>", "<keyspace ks2>"
+			resultMock := &ResultMock{}
+			resultMock.On("Values").Return([]map[string]interface{}{
+				{"role": &role, "resource": &tableResource, "permission": &select1},
+				{"role": &role, "resource": &keyspaceResource, "permission": &modify},
+			}, nil)
+			sessionMock := SessionMock{}
+			sessionMock.On("ExecuteIter", `LIST ALL PERMISSIONS OF "role1"`, mock.Anything, mock.Anything).
+				Return(resultMock, nil)
+			db := &Db{
+				session: &sessionMock,
+			}
+
+			permissions, err := db.Permissions("role1", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(permissions).To(Equal([]*PermissionMetadata{
+				{Role: "role1", Resource: tableResource, Keyspace: "ks1", Table: "tbl1", Permission: "SELECT"},
+				{Role: "role1", Resource: keyspaceResource, Keyspace: "ks2", Permission: "MODIFY"},
+			}))
+		})
+	})
+
+	Describe("SplitRing", func() {
+		It("Should cover the whole ring with contiguous ranges", func() {
+			for _, splits := range []int{1, 2, 3, 64, 1000} {
+				ranges := SplitRing(splits)
+				Expect(ranges).To(HaveLen(splits))
+				Expect(ranges[0].Start).To(Equal(int64(math.MinInt64)))
+				Expect(ranges[splits-1].End).To(Equal(int64(math.MaxInt64)))
+				for j := 1; j < splits; j++ {
+					Expect(ranges[j].Start).To(Equal(ranges[j-1].End))
+					Expect(ranges[j].End).To(BeNumerically(">", ranges[j].Start))
+				}
+			}
+		})
+
+		It("Should split the ring in two halves", func() {
+			Expect(SplitRing(2)).To(Equal([]TokenRange{
+				{Start: math.MinInt64, End: -1},
+				{Start: -1, End: math.MaxInt64},
+			}))
+		})
+	})
 })
 
 func TestTypeMapping(t *testing.T) {
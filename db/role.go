@@ -0,0 +1,230 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permissions that can be granted on keyspaces and tables
+var Permissions = map[string]bool{
+	"ALL":       true,
+	"CREATE":    true,
+	"ALTER":     true,
+	"DROP":      true,
+	"SELECT":    true,
+	"MODIFY":    true,
+	"AUTHORIZE": true,
+	"DESCRIBE":  true,
+}
+
+type CreateRoleInfo struct {
+	Name string
+	// Password is only set when not nil
+	Password    *string
+	Login       bool
+	Superuser   bool
+	IfNotExists bool
+}
+
+// AlterRoleInfo contains the role options to change, nil values are not changed
+type AlterRoleInfo struct {
+	Name      string
+	Password  *string
+	Login     *bool
+	Superuser *bool
+}
+
+type DropRoleInfo struct {
+	Name     string
+	IfExists bool
+}
+
+// PermissionInfo describes a permission granted to a role on a resource. The resource is all keyspaces when the
+// keyspace is empty, the keyspace when the table is empty or the table otherwise.
+type PermissionInfo struct {
+	Role       string
+	Permission string
+	Keyspace   string
+	Table      string
+}
+
+// RoleMetadata describes an existing role
+type RoleMetadata struct {
+	Name      string
+	Login     bool
+	Superuser bool
+	MemberOf  []string
+}
+
+// PermissionMetadata describes a permission of a role, the keyspace and table are empty for resources that are not
+// data resources
+type PermissionMetadata struct {
+	Role       string
+	Resource   string
+	Keyspace   string
+	Table      string
+	Permission string
+}
+
+func (db *Db) CreateRole(info *CreateRoleInfo, options *QueryOptions) error {
+	roleOptions := make([]string, 0, 3)
+	if info.Password != nil {
+		roleOptions = append(roleOptions, fmt.Sprintf("PASSWORD = %s", cqlString(*info.Password)))
+	}
+	roleOptions = append(roleOptions, fmt.Sprintf("LOGIN = %t", info.Login))
+	roleOptions = append(roleOptions, fmt.Sprintf("SUPERUSER = %t", info.Superuser))
+
+	query := fmt.Sprintf(`CREATE ROLE %s%s WITH %s`,
+		ifNotExistsStr(info.IfNotExists), QuoteIdentifier(info.Name), strings.Join(roleOptions, " AND "))
+	return db.session.Execute(query, options)
+}
+
+func (db *Db) AlterRole(info *AlterRoleInfo, options *QueryOptions) error {
+	roleOptions := make([]string, 0, 3)
+	if info.Password != nil {
+		roleOptions = append(roleOptions, fmt.Sprintf("PASSWORD = %s", cqlString(*info.Password)))
+	}
+	if info.Login != nil {
+		roleOptions = append(roleOptions, fmt.Sprintf("LOGIN = %t", *info.Login))
+	}
+	if info.Superuser != nil {
+		roleOptions = append(roleOptions, fmt.Sprintf("SUPERUSER = %t", *info.Superuser))
+	}
+
+	if len(roleOptions) == 0 {
+		return errors.New("at least one role option must be provided")
+	}
+
+	query := fmt.Sprintf(`ALTER ROLE %s WITH %s`, QuoteIdentifier(info.Name), strings.Join(roleOptions, " AND "))
+	return db.session.Execute(query, options)
+}
+
+func (db *Db) DropRole(info *DropRoleInfo, options *QueryOptions) error {
+	query := fmt.Sprintf(`DROP ROLE %s%s`, ifExistsStr(info.IfExists), QuoteIdentifier(info.Name))
+	return db.session.Execute(query, options)
+}
+
+func (db *Db) Grant(info *PermissionInfo, options *QueryOptions) error {
+	permission, resource, err := permissionResource(info)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`GRANT %s ON %s TO %s`, permission, resource, QuoteIdentifier(info.Role))
+	return db.session.Execute(query, options)
+}
+
+func (db *Db) Revoke(info *PermissionInfo, options *QueryOptions) error {
+	permission, resource, err := permissionResource(info)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`REVOKE %s ON %s FROM %s`, permission, resource, QuoteIdentifier(info.Role))
+	return db.session.Execute(query, options)
+}
+
+// Roles returns the existing roles sorted by name
+func (db *Db) Roles(options *QueryOptions) ([]*RoleMetadata, error) {
+	iter, err := db.session.ExecuteIter(
+		"SELECT role, can_login, is_superuser, member_of FROM system_auth.roles", options)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*RoleMetadata, 0, len(iter.Values()))
+	for _, row := range iter.Values() {
+		role := &RoleMetadata{Name: *row["role"].(*string)}
+		if value, ok := row["can_login"].(*bool); ok && value != nil {
+			role.Login = *value
+		}
+		if value, ok := row["is_superuser"].(*bool); ok && value != nil {
+			role.Superuser = *value
+		}
+		if value, ok := row["member_of"].(*[]string); ok && value != nil {
+			role.MemberOf = *value
+		}
+		roles = append(roles, role)
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+
+	return roles, nil
+}
+
+// Permissions lists the permissions of a role, including the ones inherited from other roles, or the permissions of
+// all roles when the role name is empty
+func (db *Db) Permissions(role string, options *QueryOptions) ([]*PermissionMetadata, error) {
+	query := "LIST ALL PERMISSIONS"
+	if role != "" {
+		query += " OF " + QuoteIdentifier(role)
+	}
+
+	iter, err := db.session.ExecuteIter(query, options)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]*PermissionMetadata, 0, len(iter.Values()))
+	for _, row := range iter.Values() {
+		permission := &PermissionMetadata{}
+		if value, ok := row["role"].(*string); ok && value != nil {
+			permission.Role = *value
+		}
+		if value, ok := row["resource"].(*string); ok && value != nil {
+			permission.Resource = *value
+			permission.Keyspace, permission.Table = parseDataResource(*value)
+		}
+		if value, ok := row["permission"].(*string); ok && value != nil {
+			permission.Permission = *value
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, nil
+}
+
+func permissionResource(info *PermissionInfo) (string, string, error) {
+	permission := strings.ToUpper(info.Permission)
+	if !Permissions[permission] {
+		return "", "", fmt.Errorf("invalid permission '%s'", info.Permission)
+	}
+
+	if permission == "ALL" {
+		permission = "ALL PERMISSIONS"
+	}
+
+	if info.Keyspace == "" {
+		if info.Table != "" {
+			return "", "", errors.New("keyspace must be provided when table is set")
+		}
+		return permission, "ALL KEYSPACES", nil
+	}
+
+	if info.Table == "" {
+		return permission, "KEYSPACE " + QuoteIdentifier(info.Keyspace), nil
+	}
+
+	return permission, "TABLE " + QualifiedName(info.Keyspace, info.Table), nil
+}
+
+// parseDataResource returns the keyspace and table of a data resource as listed by the server,
+// e.g. "<keyspace ks1>" or "<table ks1.tbl1>"
+func parseDataResource(resource string) (keyspace string, table string) {
+	if strings.HasPrefix(resource, "<keyspace ") && strings.HasSuffix(resource, ">") {
+		return resource[len("<keyspace ") : len(resource)-1], ""
+	}
+
+	if strings.HasPrefix(resource, "<table ") && strings.HasSuffix(resource, ">") {
+		name := resource[len("<table ") : len(resource)-1]
+		if index := strings.Index(name, "."); index > 0 {
+			return name[:index], name[index+1:]
+		}
+	}
+
+	return "", ""
+}
@@ -61,6 +61,9 @@ type Session interface {
 	// ExecuteIterSimple executes a statement and returns iterator to the result set
 	ExecuteIter(query string, options *QueryOptions, values ...interface{}) (ResultSet, error)
 
+	// ExecuteRowIterator executes a statement and returns an iterator that scans the rows of the page lazily
+	ExecuteRowIterator(query string, options *QueryOptions, values ...interface{}) (RowIterator, error)
+
 	// ChangeSchema executes a schema change query and waits for schema agreement
 	ChangeSchema(query string, options *QueryOptions) error
 
@@ -73,6 +76,17 @@ type ResultSet interface {
 	Values() []map[string]interface{}
 }
 
+// RowIterator scans the rows of a result page one at a time, avoiding to hold the whole page in memory
+type RowIterator interface {
+	// Next scans the next row, it returns false when there are no more rows or when there was an error
+	Next() bool
+	// Row returns the last row scanned by Next
+	Row() map[string]interface{}
+	PageState() []byte
+	// Close releases the iterator and returns the error that occurred during the execution or scanning, if any
+	Close() error
+}
+
 func (r *goCqlResultIterator) PageState() []byte {
 	return r.pageState
 }
@@ -86,30 +100,75 @@ type goCqlResultIterator struct {
 	values    []map[string]interface{}
 }
 
-func newResultIterator(iter *gocql.Iter) (*goCqlResultIterator, error) {
-	columns := iter.Columns()
-	scanner := iter.Scanner()
-
+func newResultIterator(rows RowIterator) (*goCqlResultIterator, error) {
 	items := make([]map[string]interface{}, 0)
 
-	for scanner.Next() {
-		row, err := mapScan(scanner, columns)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, row)
+	for rows.Next() {
+		items = append(items, rows.Row())
 	}
 
-	if err := iter.Close(); err != nil {
+	if err := rows.Close(); err != nil {
 		return nil, err
 	}
 
 	return &goCqlResultIterator{
-		pageState: iter.PageState(),
+		pageState: rows.PageState(),
 		values:    items,
 	}, nil
 }
 
+type goCqlRowIterator struct {
+	iter    *gocql.Iter
+	scanner gocql.Scanner
+	columns []gocql.ColumnInfo
+	row     map[string]interface{}
+	err     error
+	closed  bool
+}
+
+func newRowIterator(iter *gocql.Iter) *goCqlRowIterator {
+	return &goCqlRowIterator{
+		iter:    iter,
+		columns: iter.Columns(),
+		scanner: iter.Scanner(),
+	}
+}
+
+func (r *goCqlRowIterator) Next() bool {
+	if r.err != nil || r.closed || !r.scanner.Next() {
+		return false
+	}
+
+	row, err := mapScan(r.scanner, r.columns)
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	r.row = row
+	return true
+}
+
+func (r *goCqlRowIterator) Row() map[string]interface{} {
+	return r.row
+}
+
+func (r *goCqlRowIterator) PageState() []byte {
+	return r.iter.PageState()
+}
+
+func (r *goCqlRowIterator) Close() error {
+	if r.closed {
+		return r.err
+	}
+
+	r.closed = true
+	if err := r.iter.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
 type GoCqlSession struct {
 	ref *gocql.Session
 }
@@ -122,6 +181,11 @@ func (db *Db) ExecuteNoResult(query string, options *QueryOptions, values ...int
 	return db.session.Execute(query, options, values)
 }
 
+// ChangeSchema executes a schema change query and waits for schema agreement when the options contain a context
+func (db *Db) ChangeSchema(query string, options *QueryOptions) error {
+	return db.session.ChangeSchema(query, options)
+}
+
 func (session *GoCqlSession) Execute(query string, options *QueryOptions, values ...interface{}) error {
 	_, err := session.ExecuteIter(query, options, values...)
 	return err
@@ -139,6 +203,16 @@ func (session *GoCqlSession) ChangeSchema(query string, options *QueryOptions) e
 }
 
 func (session *GoCqlSession) ExecuteIter(query string, options *QueryOptions, values ...interface{}) (ResultSet, error) {
+	rows, err := session.ExecuteRowIterator(query, options, values...)
+	if err != nil {
+		return nil, err
+	}
+	return newResultIterator(rows)
+}
+
+func (session *GoCqlSession) ExecuteRowIterator(
+	query string, options *QueryOptions, values ...interface{},
+) (RowIterator, error) {
 	q := session.ref.Query(query, values...)
 
 	// Avoid reusing metadata from the prepared statement
@@ -168,7 +242,7 @@ func (session *GoCqlSession) ExecuteIter(query string, options *QueryOptions, va
 			})
 		}
 	}
-	return newResultIterator(q.Iter())
+	return newRowIterator(q.Iter()), nil
 }
 
 func (session *GoCqlSession) KeyspaceMetadata(keyspaceName string) (*gocql.KeyspaceMetadata, error) {
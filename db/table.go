@@ -3,6 +3,9 @@ package db
 import (
 	"fmt"
 	"github.com/gocql/gocql"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type CreateTableInfo struct {
@@ -11,6 +14,7 @@ type CreateTableInfo struct {
 	PartitionKeys  []*gocql.ColumnMetadata
 	ClusteringKeys []*gocql.ColumnMetadata
 	Values         []*gocql.ColumnMetadata
+	Options        *TableOptions
 	IfNotExists    bool
 }
 
@@ -54,21 +58,21 @@ func (db *Db) CreateTable(info *CreateTableInfo, options *QueryOptions) error {
 	clusteringOrder := ""
 
 	for _, c := range info.PartitionKeys {
-		columns += fmt.Sprintf(`"%s" %s, `, c.Name, toTypeString(c.Type))
-		primaryKeys += fmt.Sprintf(`, "%s"`, c.Name)
+		columns += fmt.Sprintf(`%s %s, `, QuoteIdentifier(c.Name), toTypeString(c.Type))
+		primaryKeys += fmt.Sprintf(`, %s`, QuoteIdentifier(c.Name))
 	}
 
 	if info.ClusteringKeys != nil {
 		primaryKeys = fmt.Sprintf("(%s)", primaryKeys[2:])
 
 		for _, c := range info.ClusteringKeys {
-			columns += fmt.Sprintf(`"%s" %s, `, c.Name, toTypeString(c.Type))
-			primaryKeys += fmt.Sprintf(`, "%s"`, c.Name)
+			columns += fmt.Sprintf(`%s %s, `, QuoteIdentifier(c.Name), toTypeString(c.Type))
+			primaryKeys += fmt.Sprintf(`, %s`, QuoteIdentifier(c.Name))
 			order := c.ClusteringOrder
 			if order == "" {
 				order = "ASC"
 			}
-			clusteringOrder += fmt.Sprintf(`, "%s" %s`, c.Name, order)
+			clusteringOrder += fmt.Sprintf(`, %s %s`, QuoteIdentifier(c.Name), order)
 		}
 	} else {
 		primaryKeys = primaryKeys[2:]
@@ -76,15 +80,20 @@ func (db *Db) CreateTable(info *CreateTableInfo, options *QueryOptions) error {
 
 	if info.Values != nil {
 		for _, c := range info.Values {
-			columns += fmt.Sprintf(`"%s" %s, `, c.Name, toTypeString(c.Type))
+			columns += fmt.Sprintf(`%s %s, `, QuoteIdentifier(c.Name), toTypeString(c.Type))
 		}
 	}
 
-	query := fmt.Sprintf(`CREATE TABLE %s"%s"."%s" (%sPRIMARY KEY (%s))`,
-		ifNotExistsStr(info.IfNotExists), info.Keyspace, info.Table, columns, primaryKeys)
+	query := fmt.Sprintf(`CREATE TABLE %s%s (%sPRIMARY KEY (%s))`,
+		ifNotExistsStr(info.IfNotExists), QualifiedName(info.Keyspace, info.Table), columns, primaryKeys)
 
+	properties := tableProperties(info.Options)
 	if clusteringOrder != "" {
-		query += fmt.Sprintf(" WITH CLUSTERING ORDER BY (%s)", clusteringOrder[2:])
+		properties = append([]string{fmt.Sprintf("CLUSTERING ORDER BY (%s)", clusteringOrder[2:])}, properties...)
+	}
+
+	if len(properties) > 0 {
+		query += " WITH " + strings.Join(properties, " AND ")
 	}
 
 	return db.session.ChangeSchema(query, options)
@@ -93,22 +102,158 @@ func (db *Db) CreateTable(info *CreateTableInfo, options *QueryOptions) error {
 func (db *Db) AlterTableAdd(info *AlterTableAddInfo, options *QueryOptions) error {
 	columns := ""
 	for _, c := range info.ToAdd {
-		columns += fmt.Sprintf(`, "%s" %s`, c.Name, toTypeString(c.Type))
+		columns += fmt.Sprintf(`, %s %s`, QuoteIdentifier(c.Name), toTypeString(c.Type))
 	}
-	query := fmt.Sprintf(`ALTER TABLE "%s"."%s" ADD(%s)`, info.Keyspace, info.Table, columns[2:])
+	query := fmt.Sprintf(`ALTER TABLE %s ADD(%s)`, QualifiedName(info.Keyspace, info.Table), columns[2:])
 	return db.session.ChangeSchema(query, options)
 }
 
 func (db *Db) AlterTableDrop(info *AlterTableDropInfo, options *QueryOptions) error {
 	columns := ""
 	for _, column := range info.ToDrop {
-		columns += fmt.Sprintf(`, "%s"`, column)
+		columns += fmt.Sprintf(`, %s`, QuoteIdentifier(column))
 	}
-	query := fmt.Sprintf(`ALTER TABLE "%s"."%s" DROP %s`, info.Keyspace, info.Table, columns[2:])
+	query := fmt.Sprintf(`ALTER TABLE %s DROP %s`, QualifiedName(info.Keyspace, info.Table), columns[2:])
 	return db.session.ChangeSchema(query, options)
 }
 
 func (db *Db) DropTable(info *DropTableInfo, options *QueryOptions) error {
-	query := fmt.Sprintf(`DROP TABLE %s"%s"."%s"`, ifExistsStr(info.IfExists), info.Keyspace, info.Table)
+	query := fmt.Sprintf(`DROP TABLE %s%s`, ifExistsStr(info.IfExists), QualifiedName(info.Keyspace, info.Table))
+	return db.session.ChangeSchema(query, options)
+}
+
+// TableOptions contains the table properties, only the non-nil values are set when creating or altering a table
+type TableOptions struct {
+	DefaultTimeToLive   *int
+	GcGraceSeconds      *int
+	BloomFilterFpChance *float64
+	Comment             *string
+	Compaction          map[string]string
+	Compression         map[string]string
+	Caching             map[string]string
+}
+
+type AlterTableOptionsInfo struct {
+	Keyspace string
+	Table    string
+	Options  *TableOptions
+}
+
+func (db *Db) AlterTableOptions(info *AlterTableOptionsInfo, options *QueryOptions) error {
+	properties := tableProperties(info.Options)
+	if len(properties) == 0 {
+		return fmt.Errorf("at least one table option must be provided")
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %s WITH %s`,
+		QualifiedName(info.Keyspace, info.Table), strings.Join(properties, " AND "))
 	return db.session.ChangeSchema(query, options)
 }
+
+// TableOptions retrieves the properties of the table from system_schema.tables
+func (db *Db) TableOptions(keyspace string, table string) (*TableOptions, error) {
+	iter, err := db.session.ExecuteIter(
+		"SELECT default_time_to_live, gc_grace_seconds, bloom_filter_fp_chance, comment, compaction, compression, "+
+			"caching FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ?", nil, keyspace, table)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := iter.Values()
+	if len(rows) == 0 {
+		return nil, &DbObjectNotFound{"table", table}
+	}
+
+	row := rows[0]
+	result := &TableOptions{}
+	if value, ok := row["default_time_to_live"].(*int); ok {
+		result.DefaultTimeToLive = value
+	}
+	if value, ok := row["gc_grace_seconds"].(*int); ok {
+		result.GcGraceSeconds = value
+	}
+	if value, ok := row["bloom_filter_fp_chance"].(*float64); ok {
+		result.BloomFilterFpChance = value
+	}
+	if value, ok := row["comment"].(*string); ok {
+		result.Comment = value
+	}
+	result.Compaction = stringMapValue(row["compaction"])
+	result.Compression = stringMapValue(row["compression"])
+	result.Caching = stringMapValue(row["caching"])
+
+	return result, nil
+}
+
+func stringMapValue(value interface{}) map[string]string {
+	switch value := value.(type) {
+	case *map[string]string:
+		if value != nil {
+			return *value
+		}
+	case map[string]string:
+		return value
+	}
+	return nil
+}
+
+// tableProperties returns the CQL table properties for the provided options, e.g. "gc_grace_seconds = 3600"
+func tableProperties(options *TableOptions) []string {
+	if options == nil {
+		return nil
+	}
+
+	properties := make([]string, 0)
+	if options.DefaultTimeToLive != nil {
+		properties = append(properties, fmt.Sprintf("default_time_to_live = %d", *options.DefaultTimeToLive))
+	}
+	if options.GcGraceSeconds != nil {
+		properties = append(properties, fmt.Sprintf("gc_grace_seconds = %d", *options.GcGraceSeconds))
+	}
+	if options.BloomFilterFpChance != nil {
+		properties = append(properties, fmt.Sprintf("bloom_filter_fp_chance = %s",
+			strconv.FormatFloat(*options.BloomFilterFpChance, 'f', -1, 64)))
+	}
+	if options.Comment != nil {
+		properties = append(properties, fmt.Sprintf("comment = %s", cqlString(*options.Comment)))
+	}
+	if len(options.Compaction) > 0 {
+		properties = append(properties, fmt.Sprintf("compaction = %s", cqlStringMap(options.Compaction)))
+	}
+	if len(options.Compression) > 0 {
+		properties = append(properties, fmt.Sprintf("compression = %s", cqlStringMap(options.Compression)))
+	}
+	if len(options.Caching) > 0 {
+		properties = append(properties, fmt.Sprintf("caching = %s", cqlStringMap(options.Caching)))
+	}
+	return properties
+}
+
+// QuoteIdentifier returns the name as a quoted CQL identifier, doubling the embedded double quotes
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QualifiedName returns the quoted name of an object in a keyspace, e.g. "ks1"."tbl1"
+func QualifiedName(keyspace string, name string) string {
+	return QuoteIdentifier(keyspace) + "." + QuoteIdentifier(name)
+}
+
+func cqlString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// cqlStringMap returns the map literal with the keys sorted, e.g. {'class': 'LZ4Compressor'}
+func cqlStringMap(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		entries[i] = fmt.Sprintf("%s: %s", cqlString(key), cqlString(values[key]))
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
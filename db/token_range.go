@@ -0,0 +1,98 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gocql/gocql"
+	"math"
+)
+
+const murmur3Partitioner = "org.apache.cassandra.dht.Murmur3Partitioner"
+
+// TokenRange represents a contiguous range of the token ring, the start is exclusive and the end inclusive
+type TokenRange struct {
+	Start int64
+	End   int64
+}
+
+type TokenRangeSelectInfo struct {
+	Keyspace string
+	Table    *gocql.TableMetadata
+	Columns  []string
+	Range    TokenRange
+}
+
+// SplitRing splits the whole Murmur3 token ring into the provided number of ranges of similar size
+func SplitRing(splits int) []TokenRange {
+	if splits < 1 {
+		splits = 1
+	}
+
+	// The minimum token is never assigned to a partition: (min, max] covers the whole ring
+	width := math.MaxUint64 / uint64(splits)
+	ranges := make([]TokenRange, 0, splits)
+	start := int64(math.MinInt64)
+	for i := 0; i < splits; i++ {
+		end := int64(uint64(start) + width)
+		if i == splits-1 {
+			end = math.MaxInt64
+		}
+		ranges = append(ranges, TokenRange{Start: start, End: end})
+		start = end
+	}
+
+	return ranges
+}
+
+// Partitioner retrieves the partitioner class name used by the cluster
+func (db *Db) Partitioner() (string, error) {
+	result, err := db.session.ExecuteIter("SELECT partitioner FROM system.local", nil)
+	if err != nil {
+		return "", err
+	}
+
+	rows := result.Values()
+	if len(rows) == 0 {
+		return "", errors.New("partitioner information not found")
+	}
+
+	value, _ := rows[0]["partitioner"].(*string)
+	if value == nil {
+		return "", errors.New("partitioner value is empty")
+	}
+
+	return *value, nil
+}
+
+// ValidateTokenRangeScan checks that the partitioner used by the cluster supports splitting the ring with SplitRing
+func (db *Db) ValidateTokenRangeScan() error {
+	partitioner, err := db.Partitioner()
+	if err != nil {
+		return err
+	}
+
+	if partitioner != murmur3Partitioner {
+		return fmt.Errorf("token range scans are not supported for partitioner %s", partitioner)
+	}
+
+	return nil
+}
+
+// SelectTokenRange executes a query for the rows in the token range and returns an iterator that scans the rows of the
+// page lazily
+func (db *Db) SelectTokenRange(info *TokenRangeSelectInfo, options *QueryOptions) (RowIterator, error) {
+	partitionKey := ""
+	for _, column := range info.Table.PartitionKey {
+		partitionKey += fmt.Sprintf(`, "%s"`, column.Name)
+	}
+
+	if partitionKey == "" {
+		return nil, errors.New("table partition key is not defined")
+	}
+
+	token := fmt.Sprintf("token(%s)", partitionKey[2:])
+	query := fmt.Sprintf(`SELECT %s FROM "%s"."%s" WHERE %s > ? AND %s <= ?`,
+		buildSelectColumns(info.Columns), info.Keyspace, info.Table.Name, token, token)
+
+	return db.session.ExecuteRowIterator(query, options, info.Range.Start, info.Range.End)
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"fmt"
+	"github.com/gocql/gocql"
+	"sort"
+)
+
+type CreateTypeInfo struct {
+	Keyspace    string
+	Name        string
+	Fields      []*gocql.ColumnMetadata
+	IfNotExists bool
+}
+
+type AlterTypeAddInfo struct {
+	Keyspace string
+	Name     string
+	ToAdd    []*gocql.ColumnMetadata
+}
+
+type DropTypeInfo struct {
+	Keyspace string
+	Name     string
+	IfExists bool
+}
+
+// UserTypeMetadata describes an existing user-defined type
+type UserTypeMetadata struct {
+	Keyspace string
+	Name     string
+	Fields   []*gocql.ColumnMetadata
+}
+
+func (db *Db) CreateType(info *CreateTypeInfo, options *QueryOptions) error {
+	if len(info.Fields) == 0 {
+		return fmt.Errorf("at least one field must be provided")
+	}
+
+	fields := ""
+	for _, field := range info.Fields {
+		fields += fmt.Sprintf(`, %s %s`, QuoteIdentifier(field.Name), toTypeString(field.Type))
+	}
+
+	query := fmt.Sprintf(`CREATE TYPE %s%s (%s)`,
+		ifNotExistsStr(info.IfNotExists), QualifiedName(info.Keyspace, info.Name), fields[2:])
+	return db.session.ChangeSchema(query, options)
+}
+
+// AlterTypeAdd adds the fields to the type, one ALTER TYPE statement is executed per field
+func (db *Db) AlterTypeAdd(info *AlterTypeAddInfo, options *QueryOptions) error {
+	if len(info.ToAdd) == 0 {
+		return fmt.Errorf("at least one field must be provided")
+	}
+
+	for _, field := range info.ToAdd {
+		query := fmt.Sprintf(`ALTER TYPE %s ADD %s %s`,
+			QualifiedName(info.Keyspace, info.Name), QuoteIdentifier(field.Name), toTypeString(field.Type))
+		if err := db.session.ChangeSchema(query, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *Db) DropType(info *DropTypeInfo, options *QueryOptions) error {
+	query := fmt.Sprintf(`DROP TYPE %s%s`, ifExistsStr(info.IfExists), QualifiedName(info.Keyspace, info.Name))
+	return db.session.ChangeSchema(query, options)
+}
+
+// UserTypes returns the user-defined types of the keyspace sorted by name
+func (db *Db) UserTypes(ksName string) ([]*UserTypeMetadata, error) {
+	keyspace, err := db.Keyspace(ksName)
+	if err != nil {
+		return nil, err
+	}
+
+	return UserTypesFromKeyspace(keyspace), nil
+}
+
+// UserTypesFromKeyspace returns the user-defined types in the keyspace metadata sorted by name.
+// The driver retrieves the types from system_schema.types and exposes them as KeyspaceMetadata.Views.
+func UserTypesFromKeyspace(keyspace *gocql.KeyspaceMetadata) []*UserTypeMetadata {
+	result := make([]*UserTypeMetadata, 0, len(keyspace.Views))
+	for _, udt := range keyspace.Views {
+		fields := make([]*gocql.ColumnMetadata, 0, len(udt.FieldNames))
+		for i, name := range udt.FieldNames {
+			if i >= len(udt.FieldTypes) {
+				break
+			}
+			fields = append(fields, &gocql.ColumnMetadata{
+				Keyspace:       keyspace.Name,
+				Table:          udt.Name,
+				Name:           name,
+				Type:           udt.FieldTypes[i],
+				ComponentIndex: i,
+			})
+		}
+
+		result = append(result, &UserTypeMetadata{
+			Keyspace: keyspace.Name,
+			Name:     udt.Name,
+			Fields:   fields,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,330 @@
+package db
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type CreateViewInfo struct {
+	Keyspace  string
+	Name      string
+	BaseTable string
+	// Columns selected from the base table, all columns are selected when empty
+	Columns        []string
+	PartitionKeys  []string
+	ClusteringKeys []ColumnOrder
+	// Where contains additional restrictions on the primary key columns of the base table, using the =, >, >=, < and
+	// <= operators. The values are included as CQL literals, so they are validated against the column types. The
+	// primary key columns of the view are always restricted to be not null.
+	Where       []types.ConditionItem
+	IfNotExists bool
+}
+
+type DropViewInfo struct {
+	Keyspace string
+	Name     string
+	IfExists bool
+}
+
+// ViewMetadata describes an existing materialized view
+type ViewMetadata struct {
+	Keyspace          string
+	Name              string
+	BaseTable         string
+	Columns           []string
+	PartitionKeys     []string
+	ClusteringKeys    []ColumnOrder
+	WhereClause       string
+	IncludeAllColumns bool
+}
+
+func (db *Db) CreateView(info *CreateViewInfo, options *QueryOptions) error {
+	var table *gocql.TableMetadata
+	if len(info.Where) > 0 {
+		// The column types are needed to validate the values of the restrictions
+		var err error
+		if table, err = db.Table(info.Keyspace, info.BaseTable); err != nil {
+			return err
+		}
+	}
+
+	query, err := createViewQuery(info, table)
+	if err != nil {
+		return err
+	}
+	return db.session.ChangeSchema(query, options)
+}
+
+func (db *Db) DropView(info *DropViewInfo, options *QueryOptions) error {
+	query := fmt.Sprintf(`DROP MATERIALIZED VIEW %s%s`,
+		ifExistsStr(info.IfExists), QualifiedName(info.Keyspace, info.Name))
+	return db.session.ChangeSchema(query, options)
+}
+
+// DescribeViews returns the materialized views of the keyspace sorted by name
+func (db *Db) DescribeViews(ksName string) ([]*ViewMetadata, error) {
+	iter, err := db.session.ExecuteIter(
+		"SELECT view_name, base_table_name, where_clause, include_all_columns FROM system_schema.views "+
+			"WHERE keyspace_name = ?", nil, ksName)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]*ViewMetadata, 0, len(iter.Values()))
+	viewsByName := make(map[string]*ViewMetadata, len(iter.Values()))
+	for _, row := range iter.Values() {
+		view := &ViewMetadata{
+			Keyspace:  ksName,
+			Name:      *row["view_name"].(*string),
+			BaseTable: *row["base_table_name"].(*string),
+		}
+		if value, ok := row["where_clause"].(*string); ok && value != nil {
+			view.WhereClause = *value
+		}
+		if value, ok := row["include_all_columns"].(*bool); ok && value != nil {
+			view.IncludeAllColumns = *value
+		}
+		views = append(views, view)
+		viewsByName[view.Name] = view
+	}
+
+	if len(views) == 0 {
+		return views, nil
+	}
+
+	iter, err = db.session.ExecuteIter(
+		"SELECT table_name, column_name, kind, position, clustering_order FROM system_schema.columns "+
+			"WHERE keyspace_name = ?", nil, ksName)
+	if err != nil {
+		return nil, err
+	}
+
+	type viewColumn struct {
+		name     string
+		kind     string
+		position int
+		order    string
+	}
+
+	columns := make(map[string][]viewColumn, len(views))
+	for _, row := range iter.Values() {
+		tableName := *row["table_name"].(*string)
+		if viewsByName[tableName] == nil {
+			continue
+		}
+		column := viewColumn{name: *row["column_name"].(*string)}
+		if value, ok := row["kind"].(*string); ok && value != nil {
+			column.kind = *value
+		}
+		if value, ok := row["position"].(*int); ok && value != nil {
+			column.position = *value
+		}
+		if value, ok := row["clustering_order"].(*string); ok && value != nil {
+			column.order = strings.ToUpper(*value)
+		}
+		columns[tableName] = append(columns[tableName], column)
+	}
+
+	for _, view := range views {
+		viewColumns := columns[view.Name]
+		sort.Slice(viewColumns, func(i, j int) bool {
+			if viewColumns[i].position != viewColumns[j].position {
+				return viewColumns[i].position < viewColumns[j].position
+			}
+			return viewColumns[i].name < viewColumns[j].name
+		})
+
+		view.Columns = make([]string, 0, len(viewColumns))
+		for _, column := range viewColumns {
+			switch column.kind {
+			case "partition_key":
+				view.PartitionKeys = append(view.PartitionKeys, column.name)
+			case "clustering":
+				view.ClusteringKeys = append(view.ClusteringKeys, ColumnOrder{Column: column.name, Order: column.order})
+			}
+		}
+
+		// Primary key columns first, followed by the rest of the columns sorted by name
+		view.Columns = append(view.Columns, view.PartitionKeys...)
+		for _, key := range view.ClusteringKeys {
+			view.Columns = append(view.Columns, key.Column)
+		}
+		regular := make([]string, 0, len(viewColumns))
+		for _, column := range viewColumns {
+			if column.kind != "partition_key" && column.kind != "clustering" {
+				regular = append(regular, column.name)
+			}
+		}
+		sort.Strings(regular)
+		view.Columns = append(view.Columns, regular...)
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+
+	return views, nil
+}
+
+// viewOperators contains the operators supported in the restrictions of a view
+var viewOperators = map[string]bool{"=": true, ">": true, ">=": true, "<": true, "<=": true}
+
+var integerLiteralRegex = regexp.MustCompile(`^-?[0-9]+$`)
+var numericLiteralRegex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// ValidateViewWhere checks that the restrictions of a view are on primary key columns of the base table and that the
+// values are valid for the column types
+func ValidateViewWhere(table *gocql.TableMetadata, where []types.ConditionItem) error {
+	_, err := viewWhere(table, where)
+	return err
+}
+
+// viewWhere generates the restrictions of a view, the values are included as CQL literals as schema change statements
+// can not contain bind markers
+func viewWhere(table *gocql.TableMetadata, where []types.ConditionItem) ([]string, error) {
+	result := make([]string, 0, len(where))
+	for _, item := range where {
+		column, ok := table.Columns[item.Column]
+		if !ok {
+			return nil, fmt.Errorf("column '%s' not found in table '%s'", item.Column, table.Name)
+		}
+		if column.Kind != gocql.ColumnPartitionKey && column.Kind != gocql.ColumnClusteringKey {
+			return nil, fmt.Errorf("view restrictions are only supported on primary key columns, '%s' is not",
+				item.Column)
+		}
+		if !viewOperators[item.Operator] {
+			return nil, fmt.Errorf("operator '%s' is not supported in view restrictions", item.Operator)
+		}
+		literal, err := viewLiteral(item.Value, column.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for column '%s': %s", item.Column, err)
+		}
+		result = append(result, fmt.Sprintf("%s %s %s", QuoteIdentifier(item.Column), item.Operator, literal))
+	}
+	return result, nil
+}
+
+// viewLiteral converts a json or string value into a CQL literal of the provided type
+func viewLiteral(value interface{}, typeInfo gocql.TypeInfo) (string, error) {
+	switch v := value.(type) {
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		value = strconv.FormatBool(v)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+
+	switch typeInfo.Type() {
+	case gocql.TypeAscii, gocql.TypeVarchar, gocql.TypeText, gocql.TypeInet, gocql.TypeDate, gocql.TypeTime:
+		return quoteLiteral(str), nil
+	case gocql.TypeTimestamp:
+		if integerLiteralRegex.MatchString(str) {
+			// Milliseconds since the epoch
+			return str, nil
+		}
+		return quoteLiteral(str), nil
+	case gocql.TypeInt, gocql.TypeBigInt, gocql.TypeSmallInt, gocql.TypeTinyInt, gocql.TypeVarint:
+		if integerLiteralRegex.MatchString(str) {
+			return str, nil
+		}
+	case gocql.TypeFloat, gocql.TypeDouble, gocql.TypeDecimal:
+		if numericLiteralRegex.MatchString(str) {
+			return str, nil
+		}
+	case gocql.TypeBoolean:
+		if str == "true" || str == "false" {
+			return str, nil
+		}
+	case gocql.TypeUUID, gocql.TypeTimeUUID:
+		if uuid, err := gocql.ParseUUID(str); err == nil {
+			return uuid.String(), nil
+		}
+	default:
+		return "", fmt.Errorf("type %s is not supported", typeInfo.Type())
+	}
+
+	return "", fmt.Errorf("'%s' is not a valid %s", str, typeInfo.Type())
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+func createViewQuery(info *CreateViewInfo, table *gocql.TableMetadata) (string, error) {
+	if len(info.PartitionKeys) == 0 {
+		return "", fmt.Errorf("at least one partition key must be provided")
+	}
+
+	var restrictions []string
+	if len(info.Where) > 0 {
+		var err error
+		if restrictions, err = viewWhere(table, info.Where); err != nil {
+			return "", err
+		}
+	}
+
+	selection := "*"
+	if len(info.Columns) > 0 {
+		selection = quotedNames(info.Columns)
+	}
+
+	keyNames := make([]string, 0, len(info.PartitionKeys)+len(info.ClusteringKeys))
+	keyNames = append(keyNames, info.PartitionKeys...)
+
+	clusteringNames := make([]string, 0, len(info.ClusteringKeys))
+	clusteringOrder := ""
+	hasOrder := false
+	for _, key := range info.ClusteringKeys {
+		order := strings.ToUpper(key.Order)
+		switch order {
+		case "":
+			order = "ASC"
+		case "ASC", "DESC":
+			hasOrder = true
+		default:
+			return "", fmt.Errorf("invalid clustering order '%s' for column '%s'", key.Order, key.Column)
+		}
+		clusteringNames = append(clusteringNames, key.Column)
+		clusteringOrder += fmt.Sprintf(`, %s %s`, QuoteIdentifier(key.Column), order)
+	}
+	keyNames = append(keyNames, clusteringNames...)
+
+	where := make([]string, 0, len(keyNames)+len(restrictions))
+	for _, name := range keyNames {
+		where = append(where, fmt.Sprintf(`%s IS NOT NULL`, QuoteIdentifier(name)))
+	}
+	where = append(where, restrictions...)
+
+	primaryKey := fmt.Sprintf("(%s)", quotedNames(info.PartitionKeys))
+	if len(clusteringNames) > 0 {
+		primaryKey += ", " + quotedNames(clusteringNames)
+	}
+
+	query := fmt.Sprintf(`CREATE MATERIALIZED VIEW %s%s AS SELECT %s FROM %s WHERE %s PRIMARY KEY (%s)`,
+		ifNotExistsStr(info.IfNotExists), QualifiedName(info.Keyspace, info.Name), selection,
+		QualifiedName(info.Keyspace, info.BaseTable),
+		strings.Join(where, " AND "), primaryKey)
+
+	if hasOrder {
+		query += fmt.Sprintf(" WITH CLUSTERING ORDER BY (%s)", clusteringOrder[2:])
+	}
+
+	return query, nil
+}
+
+func quotedNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = QuoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
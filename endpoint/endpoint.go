@@ -1,8 +1,10 @@
 package endpoint
 
 import (
+	"github.com/datastax/cassandra-data-apis/audit"
 	"github.com/datastax/cassandra-data-apis/config"
 	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/events"
 	"github.com/datastax/cassandra-data-apis/graphql"
 	"github.com/datastax/cassandra-data-apis/log"
 	"github.com/datastax/cassandra-data-apis/rest"
@@ -20,6 +22,18 @@ type DataEndpointConfig struct {
 	updateInterval    time.Duration
 	naming            config.NamingConventionFn
 	useUserOrRoleAuth bool
+	superuserRole     string
+	relationships     []*config.Relationship
+	relayConnections  bool
+	broker            events.Broker
+	eventSinks        []events.Sink
+	columnPolicies    *config.ColumnPolicies
+	rowPolicies       *config.RowPolicies
+	exposure          *config.Exposure
+	auditWriter       audit.Writer
+	auditKeyspace     string
+	auditTable        string
+	auditKeyspaces    []string
 	logger            log.Logger
 	routerInfo        config.HttpRouterInfo
 }
@@ -40,6 +54,38 @@ func (cfg DataEndpointConfig) UseUserOrRoleAuth() bool {
 	return cfg.useUserOrRoleAuth
 }
 
+func (cfg DataEndpointConfig) SuperuserRole() string {
+	return cfg.superuserRole
+}
+
+func (cfg DataEndpointConfig) Relationships() []*config.Relationship {
+	return cfg.relationships
+}
+
+func (cfg DataEndpointConfig) RelayConnections() bool {
+	return cfg.relayConnections
+}
+
+func (cfg DataEndpointConfig) Broker() events.Broker {
+	return cfg.broker
+}
+
+func (cfg DataEndpointConfig) EventSinks() []events.Sink {
+	return cfg.eventSinks
+}
+
+func (cfg DataEndpointConfig) ColumnPolicies() *config.ColumnPolicies {
+	return cfg.columnPolicies
+}
+
+func (cfg DataEndpointConfig) RowPolicies() *config.RowPolicies {
+	return cfg.rowPolicies
+}
+
+func (cfg DataEndpointConfig) Exposure() *config.Exposure {
+	return cfg.exposure
+}
+
 func (cfg DataEndpointConfig) DbConfig() db.Config {
 	return cfg.dbConfig
 }
@@ -72,6 +118,75 @@ func (cfg *DataEndpointConfig) WithUseUserOrRoleAuth(useUserOrRowAuth bool) *Dat
 	return cfg
 }
 
+// WithSuperuserRole sets the role required to use the role management API
+func (cfg *DataEndpointConfig) WithSuperuserRole(superuserRole string) *DataEndpointConfig {
+	cfg.superuserRole = superuserRole
+	return cfg
+}
+
+// WithRelationships sets the links between tables that are exposed as nested GraphQL fields
+func (cfg *DataEndpointConfig) WithRelationships(relationships []*config.Relationship) *DataEndpointConfig {
+	cfg.relationships = relationships
+	return cfg
+}
+
+// WithRelayConnections sets whether Relay-style connection queries are generated for each table
+func (cfg *DataEndpointConfig) WithRelayConnections(relayConnections bool) *DataEndpointConfig {
+	cfg.relayConnections = relayConnections
+	return cfg
+}
+
+// WithBroker sets the broker used to distribute the changes to the GraphQL subscriptions, allowing multiple instances
+// to be connected
+func (cfg *DataEndpointConfig) WithBroker(broker events.Broker) *DataEndpointConfig {
+	cfg.broker = broker
+	return cfg
+}
+
+// WithEventSinks sets the sinks that receive the change events of the successful mutations
+func (cfg *DataEndpointConfig) WithEventSinks(sinks []events.Sink) *DataEndpointConfig {
+	cfg.eventSinks = sinks
+	return cfg
+}
+
+// WithColumnPolicies sets the policies that restrict the columns of the tables that each role can access
+func (cfg *DataEndpointConfig) WithColumnPolicies(policies *config.ColumnPolicies) *DataEndpointConfig {
+	cfg.columnPolicies = policies
+	return cfg
+}
+
+// WithRowPolicies sets the policies that restrict the partitions of the tables using the claims of the identity, see
+// auth.WithContextClaims()
+func (cfg *DataEndpointConfig) WithRowPolicies(policies *config.RowPolicies) *DataEndpointConfig {
+	cfg.rowPolicies = policies
+	return cfg
+}
+
+// WithExposure sets the tables and columns that are exposed and the data operations allowed on each table
+func (cfg *DataEndpointConfig) WithExposure(exposure *config.Exposure) *DataEndpointConfig {
+	cfg.exposure = exposure
+	return cfg
+}
+
+// WithAuditWriter sets the writer of the audit entries of the mutations and schema changes
+func (cfg *DataEndpointConfig) WithAuditWriter(writer audit.Writer) *DataEndpointConfig {
+	cfg.auditWriter = writer
+	return cfg
+}
+
+// WithAuditTable sets the table where the audit entries are inserted
+func (cfg *DataEndpointConfig) WithAuditTable(keyspace string, table string) *DataEndpointConfig {
+	cfg.auditKeyspace = keyspace
+	cfg.auditTable = table
+	return cfg
+}
+
+// WithAuditKeyspaces limits the audit entries to the operations of the keyspaces, all keyspaces are audited when empty
+func (cfg *DataEndpointConfig) WithAuditKeyspaces(keyspaces []string) *DataEndpointConfig {
+	cfg.auditKeyspaces = keyspaces
+	return cfg
+}
+
 func (cfg *DataEndpointConfig) WithDbConfig(dbConfig db.Config) *DataEndpointConfig {
 	cfg.dbConfig = dbConfig
 	return cfg
@@ -92,6 +207,17 @@ func (cfg DataEndpointConfig) NewEndpoint() (*DataEndpoint, error) {
 }
 
 func (cfg DataEndpointConfig) newEndpointWithDb(dbClient *db.Db) *DataEndpoint {
+	var writers []audit.Writer
+	if cfg.auditWriter != nil {
+		writers = append(writers, cfg.auditWriter)
+	}
+	if cfg.auditTable != "" {
+		writers = append(writers, dbClient.NewAuditTableWriter(cfg.auditKeyspace, cfg.auditTable))
+	}
+	if len(writers) > 0 {
+		dbClient = dbClient.WithAudit(audit.ForKeyspaces(audit.MultiWriter(writers...), cfg.auditKeyspaces), cfg.logger)
+	}
+
 	return &DataEndpoint{
 		graphQLRouteGen: graphql.NewRouteGenerator(dbClient, cfg),
 		restRouteGen:    rest.NewRouteGenerator(dbClient, cfg),
@@ -116,6 +242,7 @@ func NewEndpointConfigWithLogger(logger log.Logger, hosts ...string) *DataEndpoi
 		dbHosts:        hosts,
 		updateInterval: DefaultSchemaUpdateDuration,
 		naming:         config.NewDefaultNaming,
+		broker:         events.NewLocalBroker(),
 		logger:         logger,
 		routerInfo:     config.DefaultRouterInfo(),
 	}
@@ -137,6 +264,12 @@ func (e *DataEndpoint) RoutesSchemaManagementKeyspaceGraphQL(pattern string, ksN
 	return e.graphQLRouteGen.RoutesSchemaManagement(pattern, ksName, ops)
 }
 
+// RoutesAdminGraphQL gets the routes of the role and permission management schema, it requires the RoleManagement
+// operation and the requests are only allowed for the configured superuser role.
+func (e *DataEndpoint) RoutesAdminGraphQL(pattern string, ops config.SchemaOperations) ([]types.Route, error) {
+	return e.graphQLRouteGen.RoutesAdmin(pattern, ops)
+}
+
 // Keyspaces gets a slice of keyspace names that are considered by the endpoint when used in multi-keyspace mode.
 func (e *DataEndpoint) Keyspaces() ([]string, error) {
 	return e.graphQLRouteGen.Keyspaces()
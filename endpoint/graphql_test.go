@@ -2,9 +2,11 @@ package endpoint
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/datastax/cassandra-data-apis/audit"
 	"github.com/datastax/cassandra-data-apis/auth"
 	"github.com/datastax/cassandra-data-apis/config"
 	"github.com/datastax/cassandra-data-apis/db"
@@ -17,10 +19,13 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/websocket"
 	"net/http"
 	"net/http/httptest"
 	"path"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -79,6 +84,1329 @@ func TestDataEndpoint_Query(t *testing.T) {
 	assert.Equal(t, expected, resp)
 }
 
+func TestDataEndpoint_Aggregate(t *testing.T) {
+	session, routes := createRoutes(t, createConfig(t), "/graphql", "store")
+
+	title := "book1"
+	count := "2"
+	maxPages := 42
+	resultMock := &db.ResultMock{}
+	resultMock.
+		On("PageState").Return([]byte{}).
+		On("Values").Return([]map[string]interface{}{
+		map[string]interface{}{"title": &title, "count": &count, "max(pages)": &maxPages},
+	}, nil)
+
+	session.
+		On("ExecuteIter",
+			`SELECT "title", COUNT(*) AS "count", MAX("pages") AS "max(pages)" FROM "store"."books" `+
+				`WHERE "title" = ? GROUP BY "title"`,
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	body := graphql.RequestBody{
+		Query: `query {
+  books(value:{title:"abc"}) {
+    aggregate(groupBy:[title]) {
+      title
+      count
+      max { pages }
+    }
+  }
+}`,
+	}
+
+	expected := schemas.ResponseBody{
+		Data: map[string]interface{}{
+			"books": map[string]interface{}{
+				"aggregate": []interface{}{
+					map[string]interface{}{
+						"title": title,
+						"count": count,
+						"max": map[string]interface{}{
+							"pages": float64(maxPages),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buffer, err := executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Equal(t, expected, resp)
+	// The values query is not executed when only the aggregates are selected
+	session.AssertNotCalled(t, "ExecuteIter", `SELECT * FROM "store"."books" WHERE "title" = ?`,
+		mock.Anything, mock.Anything)
+}
+
+func TestDataEndpoint_QueryMetadata(t *testing.T) {
+	session, routes := createRoutes(t, createConfig(t), "/graphql", "store")
+
+	title := "book1"
+	writetime := "1589932350000000"
+	ttl := 3600
+	resultMock := &db.ResultMock{}
+	resultMock.
+		On("PageState").Return([]byte{}).
+		On("Values").Return([]map[string]interface{}{
+		map[string]interface{}{"title": &title, "writetime(pages)": &writetime, "ttl(pages)": &ttl},
+	}, nil)
+
+	session.
+		On("ExecuteIter",
+			`SELECT "title", "first_name", "last_name", "pages", TTL("pages") AS "ttl(pages)", `+
+				`WRITETIME("pages") AS "writetime(pages)" FROM "store"."books" WHERE "title" = ?`,
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	body := graphql.RequestBody{
+		Query: `query {
+  books(value:{title:"abc"}) {
+    values {
+      title
+      pagesWritetime
+      pagesTtl
+    }
+  }
+}`,
+	}
+
+	expected := schemas.ResponseBody{
+		Data: map[string]interface{}{
+			"books": map[string]interface{}{
+				"values": []interface{}{
+					map[string]interface{}{
+						"title":          title,
+						"pagesWritetime": writetime,
+						"pagesTtl":       float64(ttl),
+					},
+				},
+			},
+		},
+	}
+
+	buffer, err := executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Equal(t, expected, resp)
+}
+
+func TestDataEndpoint_MutationTimestamp(t *testing.T) {
+	session, routes := createRoutes(t, createConfig(t), "/graphql", "store")
+
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{}, nil)
+
+	session.
+		On("ExecuteIter",
+			`INSERT INTO "store"."books" ("title") VALUES (?) USING TTL ? AND TIMESTAMP ?`,
+			mock.Anything, []interface{}{"book1", 60, int64(1589932350000000)}).
+		Return(resultMock, nil)
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  insertBooks(value:{title:"book1"}, options:{ttl:60, timestamp:"1589932350000000"}) {
+    applied
+  }
+}`,
+	}
+
+	expected := schemas.ResponseBody{
+		Data: map[string]interface{}{
+			"insertBooks": map[string]interface{}{
+				"applied": true,
+			},
+		},
+	}
+
+	buffer, err := executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Equal(t, expected, resp)
+}
+
+func TestDataEndpoint_MutationTimestampWithConditions(t *testing.T) {
+	_, routes := createRoutes(t, createConfig(t), "/graphql", "store")
+
+	for _, query := range []string{
+		`mutation { insertBooks(value:{title:"book1"}, ifNotExists:true, options:{timestamp:"1"}) { applied } }`,
+		`mutation { updateBooks(value:{title:"book1", pages:1}, ifExists:true, options:{timestamp:"1"}) { applied } }`,
+		`mutation { deleteBooks(value:{title:"book1"}, ifCondition:{pages:{eq:1}}, options:{timestamp:"1"}) { applied } }`,
+	} {
+		buffer, err := executePost(routes, "/graphql", graphql.RequestBody{Query: query}, nil)
+		assert.NoError(t, err, "error executing query")
+
+		var resp schemas.ResponseBody
+		assert.NoError(t, json.NewDecoder(buffer).Decode(&resp))
+		assert.Len(t, resp.Errors, 1, query)
+		assert.Equal(t, "a timestamp can not be used along with conditions", resp.Errors[0].Message)
+	}
+}
+
+func TestDataEndpoint_FilterIndexedCollection(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	sessionMock.AddKeyspace(db.NewKeyspaceMock(
+		"store", map[string][]*gocql.ColumnMetadata{
+			"articles": {
+				{Name: "id", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeInt, "")},
+				{Name: "tags", Kind: gocql.ColumnRegular, Type: gocql.CollectionType{
+					NativeType: gocql.NewNativeType(0, gocql.TypeSet, ""),
+					Elem:       gocql.NewNativeType(0, gocql.TypeText, ""),
+				}},
+				{Name: "ratings", Kind: gocql.ColumnRegular, Type: gocql.CollectionType{
+					NativeType: gocql.NewNativeType(0, gocql.TypeMap, ""),
+					Key:        gocql.NewNativeType(0, gocql.TypeText, ""),
+					Elem:       gocql.NewNativeType(0, gocql.TypeInt, ""),
+				}},
+			},
+		}))
+	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes([]*db.IndexMetadata{
+		{Table: "articles", Name: "articles_tags_idx", Column: "tags", Target: db.IndexTargetValues},
+		{Table: "articles", Name: "articles_ratings_idx", Column: "ratings", Target: db.IndexTargetEntries},
+	})
+
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err, "error getting routes for keyspace")
+
+	id := 1
+	resultMock := &db.ResultMock{}
+	resultMock.
+		On("PageState").Return([]byte{}).
+		On("Values").Return([]map[string]interface{}{{"id": &id}}, nil)
+
+	sessionMock.
+		On("ExecuteIter", `SELECT * FROM "store"."articles" WHERE "tags" CONTAINS ?`,
+			mock.Anything, []interface{}{"cql"}).
+		Return(resultMock, nil).
+		On("ExecuteIter", `SELECT * FROM "store"."articles" WHERE "ratings"[?] = ?`,
+			mock.Anything, []interface{}{"stars", 5}).
+		Return(resultMock, nil)
+
+	for _, filter := range []string{`{tags:{contains:"cql"}}`, `{ratings:{containsEntry:{key:"stars", value:5}}}`} {
+		body := graphql.RequestBody{
+			Query: fmt.Sprintf(`query { articlesFilter(filter:%s) { values { id } } }`, filter),
+		}
+
+		buffer, err := executePost(routes, "/graphql", body, nil)
+		assert.NoError(t, err, "error executing query")
+
+		var resp schemas.ResponseBody
+		err = json.NewDecoder(buffer).Decode(&resp)
+		assert.NoError(t, err, "error decoding response")
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, map[string]interface{}{
+			"articlesFilter": map[string]interface{}{
+				"values": []interface{}{map[string]interface{}{"id": float64(id)}},
+			},
+		}, resp.Data)
+	}
+}
+
+func TestDataEndpoint_Relationships(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("store", map[string][]*gocql.ColumnMetadata{
+		"books": db.BooksColumnsMock,
+		"reviews_by_book": {
+			{Name: "book_title", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "reviewer", Kind: gocql.ColumnClusteringKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "stars", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeInt, "")},
+		},
+		"authors": {
+			{Name: "first_name", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "last_name", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "country", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}))
+	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes(nil)
+
+	relationships, err := config.ParseRelationships([]string{
+		"store.books.title -> reviews_by_book.book_title as reviews",
+		"store.books.first_name+last_name -> authors.first_name+last_name one-to-one as author",
+		"store.books.pages -> reviews_by_book.stars",
+	})
+	assert.NoError(t, err)
+
+	cfg := createConfig(t).WithRelationships(relationships)
+	endpoint := cfg.newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err, "error getting routes for keyspace")
+
+	title, firstName, lastName, country := "book1", "Ann", "Smith", "UK"
+	reviewer, stars := "reviewer1", 5
+	booksResult := &db.ResultMock{}
+	booksResult.
+		On("PageState").Return([]byte{}).
+		On("Values").Return([]map[string]interface{}{
+		{"title": &title, "first_name": &firstName, "last_name": &lastName},
+	}, nil)
+
+	reviewsResult := &db.ResultMock{}
+	reviewsResult.
+		On("PageState").Return([]byte{1}).
+		On("Values").Return([]map[string]interface{}{{"reviewer": &reviewer, "stars": &stars}}, nil)
+
+	authorsResult := &db.ResultMock{}
+	authorsResult.On("Values").Return([]map[string]interface{}{{"country": &country}}, nil)
+
+	sessionMock.
+		On("ExecuteIter", `SELECT * FROM "store"."books" WHERE "title" = ?`, mock.Anything, mock.Anything).
+		Return(booksResult, nil).
+		On("ExecuteIter", `SELECT * FROM "store"."reviews_by_book" WHERE "book_title" = ?`,
+			mock.Anything, []interface{}{&title}).
+		Return(reviewsResult, nil).
+		On("ExecuteIter", `SELECT * FROM "store"."authors" WHERE "first_name" = ? AND "last_name" = ? LIMIT ?`,
+			mock.Anything, []interface{}{&firstName, &lastName, 1}).
+		Return(authorsResult, nil)
+
+	body := graphql.RequestBody{
+		Query: `query {
+  books(value:{title:"book1"}) {
+    values {
+      title
+      reviews(options:{pageSize:1}) { values { reviewer stars } pageState }
+      author { country }
+    }
+  }
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"books": map[string]interface{}{
+			"values": []interface{}{map[string]interface{}{
+				"title": title,
+				"reviews": map[string]interface{}{
+					"values":    []interface{}{map[string]interface{}{"reviewer": reviewer, "stars": float64(stars)}},
+					"pageState": "AQ==",
+				},
+				"author": map[string]interface{}{"country": country},
+			}},
+		},
+	}, resp.Data)
+
+	// The relationship that is not a primary key lookup is ignored
+	body = graphql.RequestBody{
+		Query: `query { books(value:{title:"book1"}) { values { reviewsByBook { pageState } } } }`,
+	}
+
+	buffer, err = executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, `Cannot query field "reviewsByBook"`)
+}
+
+func TestDataEndpoint_BatchedLookups(t *testing.T) {
+	session, routes := createRoutes(t, createConfig(t), "/graphql", "store")
+
+	title := "book1"
+	resultMock := &db.ResultMock{}
+	resultMock.
+		On("PageState").Return([]byte{}).
+		On("Values").Return([]map[string]interface{}{{"title": &title}}, nil)
+
+	query := `SELECT * FROM "store"."books" WHERE "title" = ?`
+	session.
+		On("ExecuteIter", query, mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	body := graphql.RequestBody{
+		Query: `query {
+  a: books(value:{title:"a"}) { values { title } }
+  b: books(value:{title:"b"}) { values { title } }
+  c: books(value:{title:"a"}) { values { title } }
+  d: books(value:{title:"a"}, options:{pageSize:5}) { values { title } }
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	for _, alias := range []string{"a", "b", "c", "d"} {
+		assert.Equal(t, map[string]interface{}{
+			"values": []interface{}{map[string]interface{}{"title": title}},
+		}, resp.Data[alias])
+	}
+
+	// Identical lookups are executed once per request
+	lookups := func() [][]interface{} {
+		var values [][]interface{}
+		for _, call := range session.Calls {
+			if call.Method == "ExecuteIter" && call.Arguments[0] == query {
+				values = append(values, call.Arguments[2].([]interface{}))
+			}
+		}
+		return values
+	}
+	assert.ElementsMatch(t, [][]interface{}{{"a"}, {"b"}, {"a"}}, lookups())
+
+	// The loader is scoped to a request
+	_, err = executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+	assert.Len(t, lookups(), 6)
+}
+
+func TestDataEndpoint_RelayConnection(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("store", map[string][]*gocql.ColumnMetadata{
+		"reviews_by_book": {
+			{Name: "book_title", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(4, gocql.TypeText, "")},
+			{Name: "reviewer", Kind: gocql.ColumnClusteringKey, Type: gocql.NewNativeType(4, gocql.TypeText, "")},
+			{Name: "stars", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(4, gocql.TypeInt, "")},
+		},
+	}))
+	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes(nil)
+
+	cfg := createConfig(t).WithRelayConnections(true)
+	endpoint := cfg.newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err, "error getting routes for keyspace")
+
+	rows := func(values ...[2]string) *db.ResultMock {
+		result := &db.ResultMock{}
+		items := make([]map[string]interface{}, 0, len(values))
+		for _, value := range values {
+			title, reviewer := value[0], value[1]
+			items = append(items, map[string]interface{}{"book_title": &title, "reviewer": &reviewer})
+		}
+		result.On("Values").Return(items, nil)
+		return result
+	}
+
+	// Cursors contain the length-prefixed primary key values
+	cursor := func(title, reviewer string) string {
+		value := append(append([]byte{byte(len(title))}, title...), byte(len(reviewer)))
+		return base64.StdEncoding.EncodeToString(append(value, reviewer...))
+	}
+
+	sessionMock.
+		On("ExecuteIter", `SELECT * FROM "store"."reviews_by_book" WHERE "book_title" = ? LIMIT ?`,
+			mock.Anything, []interface{}{"b1", 2}).
+		Return(rows([2]string{"b1", "r1"}, [2]string{"b1", "r2"}), nil).
+		On("ExecuteIter", `SELECT * FROM "store"."reviews_by_book" WHERE "book_title" = ? AND ("reviewer") > (?) LIMIT ?`,
+			mock.Anything, mock.Anything).
+		Return(rows([2]string{"b1", "r2"}), nil).
+		On("ExecuteIter", `SELECT * FROM "store"."reviews_by_book" WHERE token("book_title") > token(?) LIMIT ?`,
+			mock.Anything, mock.Anything).
+		Return(rows([2]string{"b2", "r1"}), nil)
+
+	query := func(args string) schemas.ResponseBody {
+		body := graphql.RequestBody{
+			Query: fmt.Sprintf(`query {
+  reviewsByBookConnection(%s) {
+    edges { cursor node { bookTitle reviewer } }
+    pageInfo { hasNextPage endCursor }
+  }
+}`, args),
+		}
+
+		buffer, err := executePost(routes, "/graphql", body, nil)
+		assert.NoError(t, err, "error executing query")
+
+		var resp schemas.ResponseBody
+		err = json.NewDecoder(buffer).Decode(&resp)
+		assert.NoError(t, err, "error decoding response")
+		assert.Empty(t, resp.Errors)
+		return resp
+	}
+
+	edge := func(title, reviewer string) interface{} {
+		return map[string]interface{}{
+			"cursor": cursor(title, reviewer),
+			"node":   map[string]interface{}{"bookTitle": title, "reviewer": reviewer},
+		}
+	}
+
+	// An additional row is retrieved to determine whether there's a next page
+	resp := query(`value:{bookTitle:"b1"}, first:1`)
+	assert.Equal(t, map[string]interface{}{
+		"edges":    []interface{}{edge("b1", "r1")},
+		"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": cursor("b1", "r1")},
+	}, resp.Data["reviewsByBookConnection"])
+
+	// The following rows of a restricted partition are retrieved using the clustering key position
+	resp = query(fmt.Sprintf(`value:{bookTitle:"b1"}, first:1, after:"%s"`, cursor("b1", "r1")))
+	assert.Equal(t, map[string]interface{}{
+		"edges":    []interface{}{edge("b1", "r2")},
+		"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": cursor("b1", "r2")},
+	}, resp.Data["reviewsByBookConnection"])
+
+	// When the partition is not restricted, the following partitions are retrieved in token order
+	resp = query(fmt.Sprintf(`first:3, after:"%s"`, cursor("b1", "r1")))
+	assert.Equal(t, map[string]interface{}{
+		"edges":    []interface{}{edge("b1", "r2"), edge("b2", "r1")},
+		"pageInfo": map[string]interface{}{"hasNextPage": false, "endCursor": cursor("b2", "r1")},
+	}, resp.Data["reviewsByBookConnection"])
+	sessionMock.AssertCalled(t, "ExecuteIter",
+		`SELECT * FROM "store"."reviews_by_book" WHERE "book_title" = ? AND ("reviewer") > (?) LIMIT ?`,
+		mock.Anything, mock.MatchedBy(func(values []interface{}) bool {
+			return len(values) == 3 && fmt.Sprint(values[0]) == fmt.Sprint([]byte("b1")) && values[2] == 4
+		}))
+	sessionMock.AssertCalled(t, "ExecuteIter",
+		`SELECT * FROM "store"."reviews_by_book" WHERE token("book_title") > token(?) LIMIT ?`,
+		mock.Anything, mock.MatchedBy(func(values []interface{}) bool {
+			return len(values) == 2 && values[1] == 3
+		}))
+
+	// Invalid cursors are rejected
+	body := graphql.RequestBody{Query: `query { reviewsByBookConnection(after:"abc") { pageInfo { hasNextPage } } }`}
+	buffer, err := executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "invalid cursor")
+}
+
+func TestDataEndpoint_Subscription(t *testing.T) {
+	session, routes := createRoutes(t, createConfig(t), "/graphql", "store")
+
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{}, nil)
+	session.
+		On("ExecuteIter", mock.MatchedBy(func(query string) bool {
+			return strings.HasPrefix(query, "INSERT") || strings.HasPrefix(query, "DELETE")
+		}), mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	server := httptest.NewServer(routes[getIndex].Handler)
+	defer server.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/graphql", "graphql-ws", server.URL)
+	assert.NoError(t, err, "error connecting")
+	defer ws.Close()
+
+	type message struct {
+		ID      string                 `json:"id,omitempty"`
+		Type    string                 `json:"type"`
+		Payload map[string]interface{} `json:"payload,omitempty"`
+	}
+
+	receive := func() message {
+		var m message
+		assert.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+		assert.NoError(t, websocket.JSON.Receive(ws, &m), "error receiving message")
+		return m
+	}
+
+	assert.NoError(t, websocket.JSON.Send(ws, message{Type: "connection_init"}))
+	assert.Equal(t, "connection_ack", receive().Type)
+
+	assert.NoError(t, websocket.JSON.Send(ws, message{ID: "1", Type: "start", Payload: map[string]interface{}{
+		"query": `subscription { books(value:{title:"b1"}) { operation value { title pages } } }`,
+	}}))
+
+	// Queries are executed once, the messages are processed in order so the subscription is started afterwards
+	assert.NoError(t, websocket.JSON.Send(ws, message{ID: "2", Type: "start", Payload: map[string]interface{}{
+		"query": `{ __typename }`,
+	}}))
+	assert.Equal(t, message{ID: "2", Type: "data", Payload: map[string]interface{}{
+		"data": map[string]interface{}{"__typename": "Query"},
+	}}, receive())
+	assert.Equal(t, message{ID: "2", Type: "complete"}, receive())
+
+	for _, mutation := range []string{
+		`mutation { insertBooks(value:{title:"b2", pages:1}) { applied } }`,
+		`mutation { insertBooks(value:{title:"b1", pages:2}) { applied } }`,
+		`mutation { deleteBooks(value:{title:"b1"}) { applied } }`,
+	} {
+		_, err := executePost(routes, "/graphql", graphql.RequestBody{Query: mutation}, nil)
+		assert.NoError(t, err, "error executing mutation")
+	}
+
+	// Changes of other partitions are filtered out
+	assert.Equal(t, message{ID: "1", Type: "data", Payload: map[string]interface{}{
+		"data": map[string]interface{}{"books": map[string]interface{}{
+			"operation": "INSERT",
+			"value":     map[string]interface{}{"title": "b1", "pages": float64(2)},
+		}},
+	}}, receive())
+	assert.Equal(t, message{ID: "1", Type: "data", Payload: map[string]interface{}{
+		"data": map[string]interface{}{"books": map[string]interface{}{
+			"operation": "DELETE",
+			"value":     map[string]interface{}{"title": "b1", "pages": nil},
+		}},
+	}}, receive())
+
+	assert.NoError(t, websocket.JSON.Send(ws, message{ID: "1", Type: "stop"}))
+	assert.Equal(t, message{ID: "1", Type: "complete"}, receive())
+}
+
+func TestSchemaManagement_Index(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesSchemaManagementGraphQL("/graphql-schema", config.IndexCreate)
+	assert.NoError(t, err, "error getting schema management routes")
+
+	sessionMock.
+		On("ChangeSchema",
+			`CREATE CUSTOM INDEX "books_pages_idx" ON "store"."books" ("pages") USING 'StorageAttachedIndex'`,
+			mock.Anything).
+		Return(nil)
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  createIndex(keyspaceName:"store", tableName:"books", columnName:"pages", indexName:"books_pages_idx",
+              indexType:"StorageAttachedIndex")
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{"createIndex": true}, resp.Data)
+
+	body = graphql.RequestBody{
+		Query: `mutation { dropIndex(keyspaceName:"store", indexName:"books_pages_idx") }`,
+	}
+
+	buffer, err = executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.NotEmpty(t, resp.Errors, "expected dropIndex to be unsupported")
+}
+
+func TestSchemaManagement_Views(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	sessionMock.AddViewsMetadata([]*db.ViewMetadata{{
+		Name:           "books_by_pages",
+		BaseTable:      "books",
+		Columns:        []string{"pages", "title"},
+		PartitionKeys:  []string{"pages"},
+		ClusteringKeys: []db.ColumnOrder{{Column: "title", Order: "ASC"}},
+		WhereClause:    "pages IS NOT NULL AND title IS NOT NULL",
+	}})
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesSchemaManagementGraphQL("/graphql-schema", config.ViewCreate)
+	assert.NoError(t, err, "error getting schema management routes")
+
+	sessionMock.
+		On("ChangeSchema", `CREATE MATERIALIZED VIEW "store"."books_by_pages" AS SELECT "pages", "title" `+
+			`FROM "store"."books" WHERE "pages" IS NOT NULL AND "title" IS NOT NULL AND "title" = 'it''s' `+
+			`PRIMARY KEY (("pages"), "title")`,
+			mock.Anything).
+		Return(nil)
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  createView(keyspaceName:"store", viewName:"books_by_pages", tableName:"books", columns:["pages", "title"],
+             partitionKeys:["pages"], clusteringKeys:[{name:"title"}],
+             where:[{column:"title", operator:"eq", value:"it's"}])
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{"createView": true}, resp.Data)
+
+	body = graphql.RequestBody{
+		Query: `query { keyspace(name:"store") { table(name:"books") { views { name partitionKeys clusteringKeys { name order } } } } }`,
+	}
+
+	buffer, err = executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"keyspace": map[string]interface{}{
+			"table": map[string]interface{}{
+				"views": []interface{}{
+					map[string]interface{}{
+						"name":           "books_by_pages",
+						"partitionKeys":  []interface{}{"pages"},
+						"clusteringKeys": []interface{}{map[string]interface{}{"name": "title", "order": "ASC"}},
+					},
+				},
+			},
+		},
+	}, resp.Data)
+}
+
+func TestSchemaManagement_Types(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesSchemaManagementGraphQL("/graphql-schema", config.TypeCreate|config.TypeAlterAdd)
+	assert.NoError(t, err, "error getting schema management routes")
+
+	sessionMock.
+		On("ChangeSchema", `CREATE TYPE "store"."address" ("street" text, "zip" int)`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `ALTER TYPE "store"."address" ADD "city" text`, mock.Anything).
+		Return(nil)
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  createType(keyspaceName:"store", typeName:"address",
+             fields:[{name:"street", type:{basic:TEXT}}, {name:"zip", type:{basic:INT}}])
+  alterTypeAdd(keyspaceName:"store", typeName:"address", toAdd:[{name:"city", type:{basic:TEXT}}])
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{"createType": true, "alterTypeAdd": true}, resp.Data)
+
+	keyspace, _ := sessionMock.KeyspaceMetadata("store")
+	keyspace.Views = map[string]*gocql.ViewMetadata{
+		"address": {
+			Keyspace:   "store",
+			Name:       "address",
+			FieldNames: []string{"street"},
+			FieldTypes: []gocql.TypeInfo{gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}
+
+	body = graphql.RequestBody{
+		Query: `query { keyspace(name:"store") { types { name fields { name type { basic } } } } }`,
+	}
+
+	buffer, err = executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"keyspace": map[string]interface{}{
+			"types": []interface{}{
+				map[string]interface{}{
+					"name": "address",
+					"fields": []interface{}{
+						map[string]interface{}{"name": "street", "type": map[string]interface{}{"basic": "TEXT"}},
+					},
+				},
+			},
+		},
+	}, resp.Data)
+}
+
+func TestSchemaManagement_TableOptions(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesSchemaManagementGraphQL("/graphql-schema",
+		config.TableCreate|config.TableAlterOptions)
+	assert.NoError(t, err, "error getting schema management routes")
+
+	sessionMock.
+		On("ChangeSchema", `CREATE TABLE "store"."authors" ("name" text, PRIMARY KEY ("name")) `+
+			`WITH default_time_to_live = 3600 AND caching = {'keys': 'ALL', 'rows_per_partition': '10'}`,
+			mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `ALTER TABLE "store"."authors" WITH bloom_filter_fp_chance = 0.1`, mock.Anything).
+		Return(nil)
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  createTable(keyspaceName:"store", tableName:"authors", partitionKeys:[{name:"name", type:{basic:TEXT}}],
+              options:{defaultTimeToLive:3600, caching:[{key:"rows_per_partition", value:"10"}, {key:"keys", value:"ALL"}]})
+  alterTableOptions(keyspaceName:"store", tableName:"authors", options:{bloomFilterFpChance:0.1})
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{"createTable": true, "alterTableOptions": true}, resp.Data)
+
+	gcGrace := 864000
+	sessionMock.AddTableOptions(&db.TableOptions{
+		GcGraceSeconds: &gcGrace,
+		Compaction:     map[string]string{"max_threshold": "32", "class": "SizeTieredCompactionStrategy"},
+	})
+
+	body = graphql.RequestBody{
+		Query: `query { keyspace(name:"store") { table(name:"books") {
+  options { defaultTimeToLive gcGraceSeconds compaction { key value } } } } }`,
+	}
+
+	buffer, err = executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"keyspace": map[string]interface{}{
+			"table": map[string]interface{}{
+				"options": map[string]interface{}{
+					"defaultTimeToLive": nil,
+					"gcGraceSeconds":    float64(864000),
+					"compaction": []interface{}{
+						map[string]interface{}{"key": "class", "value": "SizeTieredCompactionStrategy"},
+						map[string]interface{}{"key": "max_threshold", "value": "32"},
+					},
+				},
+			},
+		},
+	}, resp.Data)
+}
+
+func TestSchemaManagement_Keyspaces(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesSchemaManagementGraphQL("/graphql-schema",
+		config.KeyspaceCreate|config.KeyspaceAlter)
+	assert.NoError(t, err, "error getting schema management routes")
+
+	sessionMock.
+		On("ChangeSchema", `CREATE KEYSPACE "dev" WITH REPLICATION = `+
+			`{ 'class': 'SimpleStrategy', 'replication_factor': 1 } AND DURABLE_WRITES = false`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `ALTER KEYSPACE "store" WITH REPLICATION = `+
+			`{ 'class': 'NetworkTopologyStrategy', 'dc1': 3, 'dc2': 2 }`, mock.Anything).
+		Return(nil)
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  createKeyspace(name:"dev", replicas:1, durableWrites:false)
+  alterKeyspace(name:"store", dcs:[{name:"dc2", replicas:2}, {name:"dc1", replicas:3}])
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{"createKeyspace": true, "alterKeyspace": true}, resp.Data)
+
+	keyspace, _ := sessionMock.KeyspaceMetadata("store")
+	keyspace.StrategyClass = "org.apache.cassandra.locator.SimpleStrategy"
+	keyspace.StrategyOptions = map[string]interface{}{"replication_factor": "2"}
+	keyspace.DurableWrites = true
+
+	body = graphql.RequestBody{
+		Query: `query { keyspace(name:"store") { replicas durableWrites } }`,
+	}
+
+	buffer, err = executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"keyspace": map[string]interface{}{"replicas": float64(2), "durableWrites": true},
+	}, resp.Data)
+}
+
+const videosSDL = `
+type Video @cql_table(name: "videos") {
+  videoId: Uuid! @cql_column(partitionKey: true)
+  addedDate: Timestamp! @cql_column(clusteringOrder: DESC)
+  title: String @cql_index
+  tags: [String]
+  status: VideoStatus
+  location: Location
+}
+
+type Location {
+  city: String
+}
+
+enum VideoStatus { DRAFT, PUBLISHED }
+
+input VideoInput {
+  videoId: Uuid!
+  addedDate: Timestamp!
+  title: String
+  status: VideoStatus
+}
+
+type Query {
+  videos(videoId: Uuid!): [Video]
+}
+
+type Mutation {
+  insertVideo(value: VideoInput!): Video @cql_insert
+  deleteVideo(videoId: Uuid!, addedDate: Timestamp!): Boolean @cql_delete(type: "Video")
+}
+`
+
+func TestSchemaManagement_DeploySchema(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesSchemaManagementGraphQL("/graphql-schema", config.SchemaMigration)
+	assert.NoError(t, err, "error getting schema management routes")
+
+	sessionMock.
+		On("KeyspaceMetadata", "dev").
+		Return((*gocql.KeyspaceMetadata)(nil), errors.New("keyspace does not exist")).
+		On("ChangeSchema", mock.Anything, mock.Anything).
+		Return(nil).
+		On("ExecuteIter", `INSERT INTO "dev"."data_api_graphql_schema" ("key", "schema", "updated") VALUES (?, ?, ?)`,
+			mock.Anything, mock.Anything).
+		Return(&db.ResultMock{}, nil)
+
+	expectedQueries := []interface{}{
+		`CREATE KEYSPACE "dev" WITH REPLICATION = { 'class': 'SimpleStrategy', 'replication_factor': 1 }`,
+		`CREATE TYPE "dev"."location" ("city" text)`,
+		`CREATE TABLE "dev"."videos" ("video_id" uuid, "added_date" timestamp, "title" text, "tags" list<text>, ` +
+			`"status" text, "location" frozen<location>, PRIMARY KEY (("video_id"), "added_date")) ` +
+			`WITH CLUSTERING ORDER BY ("added_date" DESC)`,
+		`CREATE TABLE "dev"."data_api_graphql_schema" ("key" text, "schema" text, "updated" timestamp, ` +
+			`PRIMARY KEY ("key"))`,
+		`CREATE INDEX "videos_title_idx" ON "dev"."videos" ("title")`,
+	}
+
+	// A JSON string is a valid GraphQL string literal
+	schema, err := json.Marshal(videosSDL)
+	assert.NoError(t, err)
+
+	for _, dryRun := range []bool{true, false} {
+		body := graphql.RequestBody{
+			Query: fmt.Sprintf(`mutation {
+  deploySchema(keyspaceName:"dev", schema:%s, replicas:1, dryRun:%t) {
+    statements { query }
+    destructive
+    applied
+  }
+}`, schema, dryRun),
+		}
+
+		buffer, err := executePost(routes, "/graphql-schema", body, nil)
+		assert.NoError(t, err, "error executing mutation")
+
+		var resp schemas.ResponseBody
+		err = json.NewDecoder(buffer).Decode(&resp)
+		assert.NoError(t, err, "error decoding response")
+		assert.Empty(t, resp.Errors)
+
+		deployment := resp.Data["deploySchema"].(map[string]interface{})
+		queries := make([]interface{}, 0)
+		for _, statement := range deployment["statements"].([]interface{}) {
+			queries = append(queries, statement.(map[string]interface{})["query"])
+		}
+		assert.Equal(t, expectedQueries, queries)
+		assert.Equal(t, false, deployment["destructive"])
+
+		if dryRun {
+			assert.Nil(t, deployment["applied"])
+			sessionMock.AssertNotCalled(t, "ChangeSchema", mock.Anything, mock.Anything)
+		} else {
+			assert.Equal(t, float64(len(expectedQueries)), deployment["applied"])
+			for _, query := range expectedQueries {
+				sessionMock.AssertCalled(t, "ChangeSchema", query, mock.Anything)
+			}
+		}
+	}
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  deploySchema(keyspaceName:"dev", schema:"type Query { videos: [Video] }", replicas:1) { applied }
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql-schema", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "invalid schema")
+}
+
+func TestSchemaManagement_DeploySchemaValidation(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("dev", map[string][]*gocql.ColumnMetadata{
+		"users": {
+			{Name: "id", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeUUID, "")},
+		},
+	}))
+	// Tables created by a previous deployment can be changed
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("media", map[string][]*gocql.ColumnMetadata{
+		"videos": {
+			{Name: "video_id", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeUUID, "")},
+			{Name: "added_date", Kind: gocql.ColumnClusteringKey, Type: gocql.NewNativeType(0, gocql.TypeTimestamp, ""),
+				ClusteringOrder: "desc"},
+		},
+		"data_api_graphql_schema": {
+			{Name: "key", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}))
+	sessionMock.AddIndexes(nil)
+	source := videosSDL
+	schemaResult := &db.ResultMock{}
+	schemaResult.On("Values").Return([]map[string]interface{}{{"schema": &source}}, nil)
+	sessionMock.
+		On("KeyspaceMetadata", "new_ks").
+		Return((*gocql.KeyspaceMetadata)(nil), errors.New("keyspace does not exist")).
+		On("ExecuteIter", `SELECT "schema" FROM "media"."data_api_graphql_schema" WHERE "key" = ?`,
+			mock.Anything, []interface{}{"schema"}).
+		Return(schemaResult, nil)
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesSchemaManagementGraphQL("/graphql-schema", config.SchemaMigration)
+	assert.NoError(t, err, "error getting schema management routes")
+
+	items := []struct {
+		keyspace string
+		query    string
+		expected string
+	}{
+		{"dev", "videos(videoId: Uuid!): [Video]",
+			"keyspace 'dev' contains table 'users' that was not created by a schema deployment"},
+		{"new_ks", "videos(status: VideoStatus): [Video]",
+			"the arguments must contain all the partition key columns or a single indexed column"},
+		{"new_ks", "videos(videoId: Uuid): [Video]", "the argument of partition key column 'video_id' must be non null"},
+		{"new_ks", "videos(videoId: Uuid!, title: String): [Video]", "column 'title' is not part of the primary key"},
+		{"new_ks", "videos(value: VideoInput!): Video", "column 'status' is not part of the primary key"},
+		{"new_ks", "videos(videoId: Uuid!, addedDate: Timestamp!): Video", ""},
+		{"new_ks", "videos(title: String!): [Video]", ""},
+		{"media", "videos(videoId: Uuid!): [Video]", ""},
+	}
+
+	for _, item := range items {
+		sdl := strings.Replace(videosSDL, "videos(videoId: Uuid!): [Video]", item.query, 1)
+		schema, err := json.Marshal(sdl)
+		assert.NoError(t, err)
+
+		body := graphql.RequestBody{
+			Query: fmt.Sprintf(`mutation {
+  deploySchema(keyspaceName:"%s", schema:%s, replicas:1, dryRun:true) { applied }
+}`, item.keyspace, schema),
+		}
+
+		buffer, err := executePost(routes, "/graphql-schema", body, nil)
+		assert.NoError(t, err, "error executing mutation")
+
+		var resp schemas.ResponseBody
+		err = json.NewDecoder(buffer).Decode(&resp)
+		assert.NoError(t, err, "error decoding response")
+		if item.expected == "" {
+			assert.Empty(t, resp.Errors)
+			continue
+		}
+
+		assert.Len(t, resp.Errors, 1)
+		if len(resp.Errors) > 0 {
+			assert.Contains(t, resp.Errors[0].Message, item.expected)
+		}
+	}
+}
+
+func TestDataEndpoint_DeployedSchema(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("media", map[string][]*gocql.ColumnMetadata{
+		"videos": {
+			{Name: "video_id", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeUUID, "")},
+			{Name: "added_date", Kind: gocql.ColumnClusteringKey, Type: gocql.NewNativeType(0, gocql.TypeTimestamp, "")},
+			{Name: "title", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "status", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+		"data_api_graphql_schema": {
+			{Name: "key", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "schema", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}))
+	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes(nil)
+
+	source := videosSDL
+	schemaResult := &db.ResultMock{}
+	schemaResult.On("Values").Return([]map[string]interface{}{{"schema": &source}}, nil)
+
+	title := "Data modeling"
+	status := "PUBLISHED"
+	videosResult := &db.ResultMock{}
+	videosResult.On("Values").Return([]map[string]interface{}{{"title": &title, "status": &status}}, nil)
+
+	sessionMock.
+		On("ExecuteIter", `SELECT "schema" FROM "media"."data_api_graphql_schema" WHERE "key" = ?`,
+			mock.Anything, []interface{}{"schema"}).
+		Return(schemaResult, nil).
+		On("ExecuteIter", `SELECT * FROM "media"."videos" WHERE "video_id" = ?`, mock.Anything, mock.Anything).
+		Return(videosResult, nil).
+		On("ExecuteIter", mock.MatchedBy(func(query string) bool {
+			return query == `INSERT INTO "media"."videos" ("video_id", "added_date", "title", "status") VALUES (?, ?, ?, ?)` ||
+				query == `DELETE FROM "media"."videos" WHERE "video_id" = ? AND "added_date" = ?`
+		}), mock.Anything, mock.Anything).
+		Return(&db.ResultMock{}, nil)
+
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "media")
+	assert.NoError(t, err, "error getting routes for keyspace")
+
+	body := graphql.RequestBody{
+		Query: `query {
+  videos(videoId:"f3b4b5a0-3b0a-11eb-b378-0242ac130002") { title status }
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing query")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"videos": []interface{}{map[string]interface{}{"title": title, "status": status}},
+	}, resp.Data)
+
+	body = graphql.RequestBody{
+		Query: `mutation {
+  insertVideo(value:{videoId:"f3b4b5a0-3b0a-11eb-b378-0242ac130002", addedDate:"2020-03-10T10:00:00Z",
+    title:"Data modeling", status:DRAFT}) { title status }
+  deleteVideo(videoId:"f3b4b5a0-3b0a-11eb-b378-0242ac130002", addedDate:"2020-03-10T10:00:00Z")
+}`,
+	}
+
+	buffer, err = executePost(routes, "/graphql", body, nil)
+	assert.NoError(t, err, "error executing mutation")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"insertVideo": map[string]interface{}{"title": title, "status": "DRAFT"},
+		"deleteVideo": true,
+	}, resp.Data)
+}
+
+func TestDataEndpoint_DeployedSchemaExposure(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("media", map[string][]*gocql.ColumnMetadata{
+		"videos": {
+			{Name: "video_id", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeUUID, "")},
+			{Name: "added_date", Kind: gocql.ColumnClusteringKey, Type: gocql.NewNativeType(0, gocql.TypeTimestamp, "")},
+			{Name: "title", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "status", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+		"data_api_graphql_schema": {
+			{Name: "key", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+			{Name: "schema", Kind: gocql.ColumnRegular, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}))
+	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes(nil)
+
+	source := videosSDL
+	schemaResult := &db.ResultMock{}
+	schemaResult.On("Values").Return([]map[string]interface{}{{"schema": &source}}, nil)
+
+	title := "Data modeling"
+	videosResult := &db.ResultMock{}
+	videosResult.On("Values").Return([]map[string]interface{}{{"title": &title}}, nil)
+
+	sessionMock.
+		On("ExecuteIter", `SELECT "schema" FROM "media"."data_api_graphql_schema" WHERE "key" = ?`,
+			mock.Anything, []interface{}{"schema"}).
+		Return(schemaResult, nil).
+		On("ExecuteIter", `SELECT * FROM "media"."videos" WHERE "video_id" = ?`, mock.Anything, mock.Anything).
+		Return(videosResult, nil)
+
+	exposure := config.NewExposure()
+	assert.NoError(t, exposure.Add(&config.KeyspaceExposure{
+		Keyspace:       "media",
+		ExcludeColumns: map[string][]string{"videos": {"status"}},
+		Operations:     map[string]config.DataOperations{"videos": config.DataRead},
+	}))
+	endpoint := createConfig(t).
+		WithExposure(exposure).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "media")
+	assert.NoError(t, err, "error getting routes for keyspace")
+
+	execute := func(query string) schemas.ResponseBody {
+		buffer, err := executePost(routes, "/graphql", graphql.RequestBody{Query: query}, nil)
+		assert.NoError(t, err, "error executing query")
+
+		var resp schemas.ResponseBody
+		err = json.NewDecoder(buffer).Decode(&resp)
+		assert.NoError(t, err, "error decoding response")
+		return resp
+	}
+
+	resp := execute(`query { videos(videoId:"f3b4b5a0-3b0a-11eb-b378-0242ac130002") { title } }`)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"videos": []interface{}{map[string]interface{}{"title": title}},
+	}, resp.Data)
+
+	// The excluded column is not part of the type
+	resp = execute(`query { videos(videoId:"f3b4b5a0-3b0a-11eb-b378-0242ac130002") { title status } }`)
+	assert.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, `Cannot query field "status"`)
+
+	// The table only allows reads
+	resp = execute(`mutation {
+  deleteVideo(videoId:"f3b4b5a0-3b0a-11eb-b378-0242ac130002", addedDate:"2020-03-10T10:00:00Z")
+}`)
+	assert.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "Schema is not configured for mutations")
+}
+
+func TestAdminSchema_RolesAudit(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	writer := &auditWriter{}
+	endpoint := createConfig(t).
+		WithSuperuserRole("admin").
+		WithAuditWriter(writer).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesAdminGraphQL("/graphql-admin", config.RoleManagement)
+	assert.NoError(t, err, "error getting admin routes")
+	routes = withAuth(t, routes, map[string]string{"token1": "admin"})
+
+	sessionMock.
+		On("Execute", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil).Once().
+		On("Execute", mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.New(`unable to execute 'ALTER ROLE "reader" WITH PASSWORD = 'it''s secret2''`))
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  createRole(name:"reader", password:"it's secret1", login:true)
+  alterRole(name:"reader", password:"it's secret2")
+}`,
+	}
+
+	_, err = executePost(routes, "/graphql-admin", body, http.Header{"X-Cassandra-Token": []string{"token1"}})
+	assert.NoError(t, err, "error executing mutation")
+
+	assert.Len(t, writer.entries, 2)
+	for _, entry := range writer.entries {
+		assert.NotContains(t, entry.Query, "secret")
+		assert.NotContains(t, entry.Error, "secret")
+	}
+	assert.Equal(t, `CREATE ROLE "reader" WITH PASSWORD = '*****' AND LOGIN = true AND SUPERUSER = false`,
+		writer.entries[0].Query)
+	assert.Equal(t, `ALTER ROLE "reader" WITH PASSWORD = '*****'`, writer.entries[1].Query)
+	assert.Equal(t, audit.Failure, writer.entries[1].Result)
+}
+
+func TestAdminSchema_Roles(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	endpoint := createConfig(t).WithSuperuserRole("admin").newEndpointWithDb(db.NewDbWithSession(sessionMock))
+	routes, err := endpoint.RoutesAdminGraphQL("/graphql-admin", config.RoleManagement)
+	assert.NoError(t, err, "error getting admin routes")
+
+	roleName, login, superuser := "reader", true, false
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{
+		{"role": &roleName, "can_login": &login, "is_superuser": &superuser},
+	}, nil)
+
+	sessionMock.
+		On("ExecuteIter", "SELECT role, can_login, is_superuser, member_of FROM system_auth.roles",
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil).
+		On("Execute", `CREATE ROLE "reader" WITH PASSWORD = 'secret' AND LOGIN = true AND SUPERUSER = false`,
+			mock.Anything, mock.Anything).
+		Return(nil).
+		On("Execute", `GRANT SELECT ON KEYSPACE "store" TO "reader"`, mock.Anything, mock.Anything).
+		Return(nil)
+
+	routes = withAuth(t, routes, map[string]string{"token1": "admin", "token2": "user1"})
+
+	body := graphql.RequestBody{
+		Query: `mutation {
+  createRole(name:"reader", password:"secret", login:true)
+  grant(role:"reader", permission:SELECT, keyspaceName:"store")
+}`,
+	}
+
+	buffer, err := executePost(routes, "/graphql-admin", body, http.Header{"X-Cassandra-Token": []string{"token1"}})
+	assert.NoError(t, err, "error executing mutation")
+
+	var resp schemas.ResponseBody
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{"createRole": true, "grant": true}, resp.Data)
+
+	body = graphql.RequestBody{Query: `query { roles { name login superuser } }`}
+
+	buffer, err = executePost(routes, "/graphql-admin", body, http.Header{"X-Cassandra-Token": []string{"token1"}})
+	assert.NoError(t, err, "error executing query")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"roles": []interface{}{
+			map[string]interface{}{"name": "reader", "login": true, "superuser": false},
+		},
+	}, resp.Data)
+
+	// Only the superuser role is allowed
+	buffer, err = executePost(routes, "/graphql-admin", body, http.Header{"X-Cassandra-Token": []string{"token2"}})
+	assert.NoError(t, err, "error executing query")
+
+	resp = schemas.ResponseBody{}
+	err = json.NewDecoder(buffer).Decode(&resp)
+	assert.NoError(t, err, "error decoding response")
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "role management is only allowed for the superuser role", resp.Errors[0].Message)
+
+	_, err = endpoint.RoutesAdminGraphQL("/graphql-admin", config.TableCreate)
+	assert.Error(t, err)
+}
+
 func TestDataEndpoint_Auth(t *testing.T) {
 	session, routes := createRoutes(t,
 		createConfig(t).WithUseUserOrRoleAuth(true),
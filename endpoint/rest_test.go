@@ -0,0 +1,1318 @@
+package endpoint
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/datastax/cassandra-data-apis/audit"
+	"github.com/datastax/cassandra-data-apis/auth"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/events"
+	"github.com/datastax/cassandra-data-apis/graphql"
+	"github.com/datastax/cassandra-data-apis/internal/testutil/schemas"
+	e "github.com/datastax/cassandra-data-apis/rest/endpoint/v1"
+	m "github.com/datastax/cassandra-data-apis/rest/models"
+	"github.com/gocql/gocql"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const booksQueryPath = "/rest/v1/keyspaces/store/tables/books/rows/query"
+const booksQueryBody = `{"filters":[{"columnName":"title","operator":"eq","value":["abc"]}]}`
+const booksSelectQuery = `SELECT * FROM "store"."books" WHERE "title" = ?`
+
+func TestRestQuery_Stream(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	session.
+		On("ExecuteRowIterator", booksSelectQuery, mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock([]map[string]interface{}{
+			bookRow("book1", 42),
+			bookRow("book2", 10),
+		}, []byte("next"), nil), nil)
+
+	w := executeRest(handler, http.MethodPost, booksQueryPath, booksQueryBody, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		`{"rows":[{"pages":42,"title":"book1"},{"pages":10,"title":"book2"}],"pageState":"bmV4dA==","_count":2}`,
+		w.Body.String())
+}
+
+func TestRestQuery_StreamNDJSON(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	session.
+		On("ExecuteRowIterator", booksSelectQuery, mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock([]map[string]interface{}{
+			bookRow("book1", 42),
+			bookRow("book2", 10),
+		}, []byte("next"), nil), nil)
+
+	w := executeRest(handler, http.MethodPost, booksQueryPath, booksQueryBody,
+		http.Header{"Accept": []string{"application/x-ndjson"}})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, "bmV4dA==", w.Header().Get("X-Page-State"))
+	assert.Equal(t, "{\"pages\":42,\"title\":\"book1\"}\n{\"pages\":10,\"title\":\"book2\"}\n", w.Body.String())
+}
+
+func TestRestQuery_Empty(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	session.
+		On("ExecuteRowIterator", booksSelectQuery, mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock(nil, nil, nil), nil)
+
+	w := executeRest(handler, http.MethodPost, booksQueryPath, booksQueryBody, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{}", w.Body.String())
+}
+
+func TestRestQuery_Error(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	session.
+		On("ExecuteRowIterator", booksSelectQuery, mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock(nil, nil, errors.New("read timeout")), nil)
+
+	w := executeRest(handler, http.MethodPost, booksQueryPath, booksQueryBody, nil)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestRestQuery_ErrorAfterFirstRow(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	for i := 0; i < 2; i++ {
+		session.
+			On("ExecuteRowIterator", booksSelectQuery, mock.Anything, mock.Anything).
+			Return(db.NewRowIteratorMock([]map[string]interface{}{bookRow("book1", 42)}, nil,
+				errors.New("read timeout")), nil).
+			Once()
+	}
+
+	// The status code was already sent, the JSON body is truncated and the NDJSON body ends with an error line
+	w := executeRest(handler, http.MethodPost, booksQueryPath, booksQueryBody, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"rows":[{"pages":42,"title":"book1"}`, w.Body.String())
+	assert.False(t, json.Valid(w.Body.Bytes()))
+
+	w = executeRest(handler, http.MethodPost, booksQueryPath, booksQueryBody,
+		http.Header{"Accept": []string{"application/x-ndjson"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"pages\":42,\"title\":\"book1\"}\n{\"_error\":\"unable to read the rows\"}\n", w.Body.String())
+}
+
+func TestRestQuery_Aggregates(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	count := "2"
+	maxPages := 42
+	session.
+		On("ExecuteRowIterator",
+			`SELECT "title", COUNT(*) AS "count(*)", MAX("pages") AS "maxPages" FROM "store"."books" `+
+				`WHERE "title" = ? GROUP BY "title"`,
+			mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock([]map[string]interface{}{
+			{"title": strPtr("book1"), "count(*)": &count, "maxPages": &maxPages},
+		}, nil, nil), nil)
+
+	body := `{"filters":[{"columnName":"title","operator":"eq","value":["abc"]}],` +
+		`"aggregates":[{"function":"count"},{"function":"max","columnName":"pages","alias":"maxPages"}],` +
+		`"groupBy":["title"]}`
+	w := executeRest(handler, http.MethodPost, booksQueryPath, body, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		`{"rows":[{"count(*)":"2","maxPages":42,"title":"book1"}],"_count":1}`,
+		w.Body.String())
+}
+
+func TestRestQuery_AggregatesInvalidGroupBy(t *testing.T) {
+	_, handler := createRestHandler(t)
+
+	body := `{"filters":[],"aggregates":[{"function":"count"}],"groupBy":["pages"]}`
+	w := executeRest(handler, http.MethodPost, booksQueryPath, body, nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "group by")
+}
+
+func TestRestQuery_AggregatesInvalidFunction(t *testing.T) {
+	_, handler := createRestHandler(t)
+
+	body := `{"filters":[],"aggregates":[{"function":"median","columnName":"pages"}]}`
+	w := executeRest(handler, http.MethodPost, booksQueryPath, body, nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "aggregate function 'median' not supported")
+}
+
+func TestRestQuery_AggregatesInvalidIdentifiers(t *testing.T) {
+	_, handler := createRestHandler(t)
+
+	items := []struct {
+		body     string
+		expected string
+	}{
+		{`{"filters":[],"aggregates":[{"function":"max","columnName":"secret"}]}`,
+			"aggregate column 'secret' not found in table"},
+		{`{"filters":[],"aggregates":[{"function":"max","columnName":"pages",` +
+			`"alias":"x\" FROM \"system_auth\".\"roles\" --"}]}`, "is not valid"},
+	}
+
+	for _, item := range items {
+		w := executeRest(handler, http.MethodPost, booksQueryPath, item.body, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), item.expected)
+	}
+}
+
+func TestRestQuery_MetadataColumns(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	writetime := "1589932350000000"
+	session.
+		On("ExecuteRowIterator",
+			`SELECT "title", "first_name", "last_name", "pages", WRITETIME("pages") AS "writetime(pages)" `+
+				`FROM "store"."books" WHERE "title" = ?`,
+			mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock([]map[string]interface{}{
+			{"title": strPtr("book1"), "pages": nil, "first_name": nil, "last_name": nil, "writetime(pages)": &writetime},
+		}, nil, nil), nil)
+
+	body := `{"filters":[{"columnName":"title","operator":"eq","value":["abc"]}],` +
+		`"metadataColumns":[{"columnName":"pages","function":"writetime"}]}`
+	w := executeRest(handler, http.MethodPost, booksQueryPath, body, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t,
+		`{"rows":[{"first_name":null,"last_name":null,"pages":null,"title":"book1",`+
+			`"writetime(pages)":"1589932350000000"}],"_count":1}`,
+		w.Body.String())
+}
+
+func TestRestQuery_MetadataColumnsPrimaryKey(t *testing.T) {
+	_, handler := createRestHandler(t)
+
+	body := `{"filters":[],"metadataColumns":[{"columnName":"title","function":"ttl"}]}`
+	w := executeRest(handler, http.MethodPost, booksQueryPath, body, nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRestQuery_MetadataColumnsWithoutCellMetadata(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("store", map[string][]*gocql.ColumnMetadata{
+		"books": append(append([]*gocql.ColumnMetadata{}, db.BooksColumnsMock...),
+			&gocql.ColumnMetadata{
+				Name: "tags",
+				Kind: gocql.ColumnRegular,
+				Type: gocql.CollectionType{
+					NativeType: gocql.NewNativeType(0, gocql.TypeSet, ""),
+					Elem:       gocql.NewNativeType(0, gocql.TypeText, ""),
+				},
+			},
+			&gocql.ColumnMetadata{
+				Name: "likes",
+				Kind: gocql.ColumnRegular,
+				Type: gocql.NewNativeType(0, gocql.TypeCounter, ""),
+			}),
+	}))
+	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes(nil)
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+
+	for _, column := range []string{"tags", "likes"} {
+		body := `{"filters":[],"metadataColumns":[{"columnName":"` + column + `","function":"writetime"}]}`
+		w := executeRest(router, http.MethodPost, booksQueryPath, body, nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, column)
+		assert.Contains(t, w.Body.String(), "'"+column+"' is not valid")
+	}
+
+	for _, column := range []string{`pages\" FROM \"system_auth\".\"roles\" --`, "unknown"} {
+		body := `{"filters":[],"metadataColumns":[{"columnName":"` + column + `","function":"writetime"}]}`
+		w := executeRest(router, http.MethodPost, booksQueryPath, body, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, column)
+	}
+
+	body := `{"filters":[],"metadataColumns":[{"columnName":"pages","function":"writetime",` +
+		`"alias":"x\" FROM \"system_auth\".\"roles\" --"}]}`
+	w := executeRest(router, http.MethodPost, booksQueryPath, body, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "is not valid")
+
+	sessionMock.AssertNotCalled(t, "ExecuteRowIterator", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestDeleteRow_Timestamp(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	session.
+		On("ExecuteIter", `DELETE FROM "store"."books" USING TIMESTAMP ? WHERE "title" = ?`,
+			mock.Anything, []interface{}{int64(1589932350000000), "abc"}).
+		Return(&db.ResultMock{}, nil)
+
+	w := executeRest(handler, http.MethodDelete,
+		"/rest/v1/keyspaces/store/tables/books/rows/abc?timestamp=1589932350000000", "", nil)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/rows/abc?timestamp=now", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRestWrite_TimestampWithConditions(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	w := executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows",
+		`{"columns":[{"name":"title","value":"book1"}],"timestamp":1589932350000000,"ifNotExists":true}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "a timestamp can not be used along with conditions")
+
+	w = executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store/tables/books/rows/book1",
+		`{"changeset":[{"column":"pages","value":10}],"timestamp":1589932350000000,"ifExists":true}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "a timestamp can not be used along with conditions")
+
+	w = executeRest(handler, http.MethodDelete,
+		"/rest/v1/keyspaces/store/tables/books/rows/book1?timestamp=1589932350000000&ifExists=true", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "a timestamp can not be used along with conditions")
+
+	session.AssertNotCalled(t, "ExecuteIter", mock.MatchedBy(func(query string) bool {
+		return !strings.HasPrefix(query, "SELECT")
+	}), mock.Anything, mock.Anything)
+}
+
+func TestRestAddRow_IfNotExists(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	applied := false
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{
+		{"[applied]": &applied, "title": strPtr("book1"), "pages": intPtr(42)},
+	}, nil)
+
+	session.
+		On("ExecuteIter", `INSERT INTO "store"."books" ("title", "pages") VALUES (?, ?) IF NOT EXISTS USING TTL ?`,
+			matchOptions(db.NewQueryOptions().
+				WithConsistency(gocql.All).
+				WithSerialConsistency(gocql.LocalSerial).
+				WithPageSize(config.DefaultPageSize)),
+			[]interface{}{"book1", 10, 60}).
+		Return(resultMock, nil)
+
+	body := `{"columns":[{"name":"title","value":"book1"},{"name":"pages","value":10}],"ttl":60,"ifNotExists":true}`
+	w := executeRest(handler, http.MethodPost,
+		"/rest/v1/keyspaces/store/tables/books/rows?consistency=ALL&serialConsistency=LOCAL_SERIAL", body, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"applied":false,"value":{"title":"book1","pages":42}}`, w.Body.String())
+}
+
+func TestRestUpdateRow_IfConditions(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	applied := true
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{{"[applied]": &applied}}, nil)
+
+	session.
+		On("ExecuteIter", `UPDATE "store"."books" SET "pages" = ? WHERE "title" = ? IF "pages" IN ?`,
+			mock.Anything, []interface{}{10, "book1", []interface{}{41, 42}}).
+		Return(resultMock, nil)
+
+	body := `{"changeset":[{"column":"pages","value":10}],` +
+		`"ifConditions":[{"columnName":"pages","operator":"in","value":[41,42]}]}`
+	w := executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store/tables/books/rows/book1", body, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"rowsModified":1,"applied":true}`, w.Body.String())
+}
+
+func TestRestDeleteRow_IfExists(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	applied := false
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{{"[applied]": &applied}}, nil)
+
+	session.
+		On("ExecuteIter", `DELETE FROM "store"."books" WHERE "title" = ? IF EXISTS`,
+			mock.Anything, []interface{}{"book1"}).
+		Return(resultMock, nil)
+
+	w := executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/rows/book1?ifExists=true",
+		"", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"success":true,"applied":false}`, w.Body.String())
+
+	w = executeRest(handler, http.MethodDelete,
+		"/rest/v1/keyspaces/store/tables/books/rows/book1?consistency=MANY", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMutationEvents(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	sink := &recordingSink{}
+	endpoint := createConfig(t).
+		WithEventSinks([]events.Sink{sink}).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+	graphQLRoutes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err)
+
+	applied := false
+	notAppliedResult := &db.ResultMock{}
+	notAppliedResult.On("Values").Return([]map[string]interface{}{{"[applied]": &applied}}, nil)
+	emptyResult := &db.ResultMock{}
+	emptyResult.On("Values").Return([]map[string]interface{}{}, nil)
+	sessionMock.
+		On("ExecuteIter", `INSERT INTO "store"."books" ("title", "pages") VALUES (?, ?)`,
+			mock.Anything, mock.Anything).
+		Return(&db.ResultMock{}, nil).
+		On("ExecuteIter", `DELETE FROM "store"."books" WHERE "title" = ? IF EXISTS`,
+			mock.Anything, mock.Anything).
+		Return(notAppliedResult, nil).
+		On("ExecuteIter", `DELETE FROM "store"."books" WHERE "title" = ?`, mock.Anything, mock.Anything).
+		Return(emptyResult, nil)
+
+	w := executeRest(router, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows",
+		`{"columns":[{"name":"title","value":"book1"},{"name":"pages","value":10}]}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Mutations that are not applied don't produce events
+	w = executeRest(router, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/rows/book1?ifExists=true",
+		"", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	_, err = executePost(graphQLRoutes, "/graphql",
+		graphql.RequestBody{Query: `mutation { deleteBooks(value:{title:"book2"}) { applied } }`}, nil)
+	assert.NoError(t, err)
+
+	assert.Len(t, sink.events, 2)
+	assert.Equal(t, events.Insert, sink.events[0].Operation)
+	assert.Equal(t, map[string]interface{}{"title": "book1"}, sink.events[0].PrimaryKey)
+	assert.Equal(t, map[string]interface{}{"pages": 10}, sink.events[0].Values)
+	assert.Equal(t, events.Delete, sink.events[1].Operation)
+	assert.Equal(t, "books", sink.events[1].Table)
+	assert.Equal(t, map[string]interface{}{"title": "book2"}, sink.events[1].PrimaryKey)
+}
+
+func TestAudit(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	writer := &auditWriter{}
+	endpoint := createConfig(t).
+		WithAuditWriter(writer).
+		WithAuditKeyspaces([]string{"store"}).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+	graphQLRoutes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err)
+
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{}, nil)
+	sessionMock.
+		On("ExecuteIter", `INSERT INTO "store"."books" ("title", "pages") VALUES (?, ?)`,
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil).
+		On("ExecuteRowIterator", booksSelectQuery, mock.Anything, mock.Anything).
+		Return(db.NewRowIteratorMock(nil, nil, nil), nil).
+		On("ExecuteIter", `DELETE FROM "store"."books" WHERE "title" = ?`, mock.Anything, mock.Anything).
+		Return(resultMock, errors.New("delete failed"))
+
+	w := executeRest(router, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows",
+		`{"columns":[{"name":"title","value":"book1"},{"name":"pages","value":10}]}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Reads are not audited
+	w = executeRest(router, http.MethodPost, booksQueryPath, booksQueryBody, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	_, err = executePost(graphQLRoutes, "/graphql",
+		graphql.RequestBody{Query: `mutation { deleteBooks(value:{title:"book2"}) { applied } }`}, nil)
+	assert.NoError(t, err)
+
+	assert.Len(t, writer.entries, 2)
+	assert.Equal(t, audit.REST, writer.entries[0].Api)
+	assert.Equal(t, "POST /rest/v1/keyspaces/:keyspaceName/tables/:tableName/rows", writer.entries[0].Operation)
+	assert.Equal(t, "store", writer.entries[0].Keyspace)
+	assert.Equal(t, "books", writer.entries[0].Table)
+	assert.Equal(t, `INSERT INTO "store"."books" ("title", "pages") VALUES (?, ?)`, writer.entries[0].Query)
+	assert.Equal(t, audit.Success, writer.entries[0].Result)
+
+	assert.Equal(t, audit.GraphQL, writer.entries[1].Api)
+	assert.Equal(t, "deleteBooks", writer.entries[1].Operation)
+	assert.Equal(t, "books", writer.entries[1].Table)
+	assert.Equal(t, audit.Failure, writer.entries[1].Result)
+	assert.Equal(t, "delete failed", writer.entries[1].Error)
+}
+
+func TestColumnPolicies(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	policies := config.NewColumnPolicies()
+	assert.NoError(t, policies.Add(&config.ColumnPolicy{
+		Keyspace: "store",
+		Table:    "books",
+		Role:     config.AnyRole,
+		Deny:     []string{"last_name"},
+		Mask:     map[string]string{"first_name": "***"},
+	}))
+	endpoint := createConfig(t).
+		WithColumnPolicies(policies).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+	graphQLRoutes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err)
+
+	row := bookRow("book1", 10)
+	row["first_name"] = strPtr("Jane")
+	row["last_name"] = strPtr("Doe")
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{row}, nil)
+	sessionMock.
+		On("ExecuteIter", `SELECT * FROM "store"."books" WHERE "title" = ?`, mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	w := executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book1", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"_count":1,"rows":[{"title":"book1","pages":10,"first_name":"***"}]}`, w.Body.String())
+
+	w = executeRest(router, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows",
+		`{"columns":[{"name":"title","value":"book2"},{"name":"first_name","value":"John"}]}`, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	response, err := executePost(graphQLRoutes, "/graphql",
+		graphql.RequestBody{Query: `mutation { insertBooks(value:{title:"book2", lastName:"Doe"}) { applied } }`}, nil)
+	assert.NoError(t, err)
+	var body schemas.ResponseBody
+	assert.NoError(t, json.NewDecoder(response).Decode(&body))
+	assert.Len(t, body.Errors, 1)
+	assert.Equal(t, "access denied to column 'last_name' of table 'books'", body.Errors[0].Message)
+
+	for _, call := range sessionMock.Calls {
+		if query, ok := call.Arguments.Get(0).(string); ok {
+			assert.False(t, strings.HasPrefix(query, "INSERT"), "unexpected insert executed")
+		}
+	}
+}
+
+func TestRowPolicies(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	policies := config.NewRowPolicies()
+	assert.NoError(t, policies.Add(&config.RowPolicy{
+		Keyspace: "store",
+		Table:    "books",
+		Claims:   map[string]string{"title": "sub"},
+	}))
+	endpoint := createConfig(t).
+		WithRowPolicies(policies).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+	handler := withClaims(router, map[string]interface{}{"sub": "book1"})
+	graphQLRoutes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err)
+
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{bookRow("book1", 10)}, nil)
+	resultMock.On("PageState").Return([]byte{})
+	sessionMock.
+		On("ExecuteIter", booksSelectQuery, mock.Anything, []interface{}{"book1"}).
+		Return(resultMock, nil).
+		On("ExecuteIter", `INSERT INTO "store"."books" ("title", "pages") VALUES (?, ?)`,
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	w := executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book1", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book2", "", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// The filter doesn't match the claim
+	w = executeRest(handler, http.MethodPost, booksQueryPath, booksQueryBody, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows",
+		`{"columns":[{"name":"title","value":"book1"},{"name":"pages","value":10}]}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows",
+		`{"columns":[{"name":"title","value":"book2"},{"name":"pages","value":10}]}`, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Without claims
+	w = executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book1", "", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// The partition key condition is added to the GraphQL query
+	for i, route := range graphQLRoutes {
+		graphQLRoutes[i].Handler = withClaims(route.Handler, map[string]interface{}{"sub": "book1"})
+	}
+	response, err := executePost(graphQLRoutes, "/graphql",
+		graphql.RequestBody{Query: `query { books { values { title pages } } }`}, nil)
+	assert.NoError(t, err)
+	var body schemas.ResponseBody
+	assert.NoError(t, json.NewDecoder(response).Decode(&body))
+	assert.Empty(t, body.Errors)
+	assert.Equal(t, map[string]interface{}{"books": map[string]interface{}{
+		"values": []interface{}{map[string]interface{}{"title": "book1", "pages": float64(10)}},
+	}}, body.Data)
+}
+
+func TestRowPolicies_JWT(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	policies := config.NewRowPolicies()
+	assert.NoError(t, policies.Add(&config.RowPolicy{
+		Keyspace: "store",
+		Table:    "books",
+		Claims:   map[string]string{"title": "sub"},
+	}))
+	endpoint := createConfig(t).
+		WithRowPolicies(policies).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+	secret := []byte("secret")
+	handler := auth.NewHS256Validator(secret, "").Handler(router)
+
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{bookRow("book1", 10)}, nil)
+	resultMock.On("PageState").Return([]byte{})
+	sessionMock.
+		On("ExecuteIter", booksSelectQuery, mock.Anything, []interface{}{"book1"}).
+		Return(resultMock, nil)
+
+	bearer := func(secret []byte, claims string) http.Header {
+		signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(claims))
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		token := signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+		return http.Header{"Authorization": []string{"Bearer " + token}}
+	}
+
+	w := executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book1", "",
+		bearer(secret, `{"sub":"book1"}`))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book2", "",
+		bearer(secret, `{"sub":"book1"}`))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Invalid signature
+	w = executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book1", "",
+		bearer([]byte("other"), `{"sub":"book1"}`))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Without a token
+	w = executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book1", "", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestExposure(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	exposure := config.NewExposure()
+	assert.NoError(t, exposure.Add(&config.KeyspaceExposure{
+		Keyspace:       "store",
+		ExcludeTables:  []string{"authors"},
+		ExcludeColumns: map[string][]string{"books": {"last_name"}},
+		Operations:     map[string]config.DataOperations{"books": config.DataRead | config.DataInsert},
+	}))
+	endpoint := createConfig(t).
+		WithExposure(exposure).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+	graphQLRoutes, err := endpoint.RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err)
+
+	row := bookRow("book1", 10)
+	row["last_name"] = strPtr("Doe")
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{row}, nil)
+	sessionMock.
+		On("ExecuteIter", booksSelectQuery, mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	w := executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/rows/book1", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"_count":1,"rows":[{"title":"book1","pages":10}]}`, w.Body.String())
+
+	w = executeRest(router, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/rows/book1", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = executeRest(router, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows",
+		`{"columns":[{"name":"title","value":"book2"},{"name":"last_name","value":"Doe"}]}`, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/authors/rows/author1", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	for query, expectedError := range map[string]string{
+		`mutation { deleteBooks(value:{title:"book1"}) { applied } }`: `Cannot query field "deleteBooks"`,
+		`query { books { values { title lastName } } }`:               `Cannot query field "lastName"`,
+	} {
+		response, err := executePost(graphQLRoutes, "/graphql", graphql.RequestBody{Query: query}, nil)
+		assert.NoError(t, err)
+		var body schemas.ResponseBody
+		assert.NoError(t, json.NewDecoder(response).Decode(&body))
+		assert.Len(t, body.Errors, 1)
+		assert.Contains(t, body.Errors[0].Message, expectedError)
+	}
+
+	// The schema is valid when no mutations are allowed
+	readOnly := config.NewExposure()
+	assert.NoError(t, readOnly.Add(&config.KeyspaceExposure{
+		Keyspace:   "store",
+		Operations: map[string]config.DataOperations{"books": config.DataRead},
+	}))
+	_, err = createConfig(t).
+		WithExposure(readOnly).
+		newEndpointWithDb(db.NewDbWithSession(db.NewSessionMock().Default())).
+		RoutesKeyspaceGraphQL("/graphql", "store")
+	assert.NoError(t, err)
+}
+
+func TestExposure_Schema(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("store", map[string][]*gocql.ColumnMetadata{
+		"books": db.BooksColumnsMock,
+		"authors": {
+			{Name: "name", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}))
+	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes([]*db.IndexMetadata{
+		{Name: "books_pages_idx", Table: "books", Column: "pages"},
+		{Name: "books_last_name_idx", Table: "books", Column: "last_name"},
+	})
+	sessionMock.AddViewsMetadata(nil)
+	sessionMock.AddTableOptions(&db.TableOptions{})
+
+	books, authors := "books", "authors"
+	tablesResult := &db.ResultMock{}
+	tablesResult.On("Values").Return([]map[string]interface{}{{"table_name": &books}, {"table_name": &authors}}, nil)
+	tableResult := &db.ResultMock{}
+	tableResult.On("Values").Return([]map[string]interface{}{{"table_name": &books}}, nil)
+	sessionMock.
+		On("ExecuteIter", "SELECT table_name FROM system_schema.tables WHERE keyspace_name = ?",
+			mock.Anything, mock.Anything).
+		Return(tablesResult, nil).
+		On("ExecuteIter", "SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ?",
+			mock.Anything, mock.Anything).
+		Return(tableResult, nil)
+
+	exposure := config.NewExposure()
+	assert.NoError(t, exposure.Add(&config.KeyspaceExposure{
+		Keyspace:       "store",
+		ExcludeTables:  []string{"authors"},
+		ExcludeColumns: map[string][]string{"books": {"last_name"}},
+	}))
+	endpoint := createConfig(t).
+		WithExposure(exposure).
+		newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+
+	w := executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `["books"]`, w.Body.String())
+
+	for _, path := range []string{"", "/columns", "/columns/name", "/indexes"} {
+		w = executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/authors"+path, "", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+
+	w = executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/columns", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "last_name")
+	assert.Contains(t, w.Body.String(), "first_name")
+
+	w = executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/columns/last_name", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/books", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "last_name")
+
+	w = executeRest(router, http.MethodGet, "/rest/v1/keyspaces/store/tables/books/indexes", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"name":"books_pages_idx","columnName":"pages"}]`, w.Body.String())
+}
+
+func TestRestIndexes(t *testing.T) {
+	session := db.NewSessionMock()
+	session.SetSchemaVersion("a78bc282-aff7-4c2a-8f23-4ce3584adbb0")
+	session.AddKeyspace(db.NewKeyspaceMock("store", map[string][]*gocql.ColumnMetadata{
+		"books": db.BooksColumnsMock,
+		"authors": {
+			{Name: "name", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}))
+	session.AddViews(nil)
+	session.AddIndexes([]*db.IndexMetadata{
+		{Name: "books_pages_idx", Table: "books", Column: "pages"},
+		{Name: "authors_name_idx", Table: "authors", Column: "name"},
+	})
+	handler := createRestHandlerWithSession(t, session)
+
+	session.
+		On("ChangeSchema", `CREATE INDEX IF NOT EXISTS ON "store"."books" ("pages")`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `DROP INDEX "store"."books_pages_idx"`, mock.Anything).
+		Return(nil)
+
+	w := executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/indexes",
+		`{"columnName":"pages","ifNotExists":true}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/indexes",
+		`{"columnName":"pages","target":"all"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/indexes/books_pages_idx",
+		"", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// The index must belong to the table in the path
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/indexes/authors_name_idx",
+		"", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/indexes/unknown_idx",
+		"", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = executeRest(handler, http.MethodDelete,
+		"/rest/v1/keyspaces/store/tables/books/indexes/unknown_idx?ifExists=true", "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	session.AssertNumberOfCalls(t, "ChangeSchema", 2)
+}
+
+func TestRestGetTable_Views(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	tableName := "books"
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{{"table_name": &tableName}}, nil)
+	session.
+		On("ExecuteIter", "SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ?",
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+	session.AddViewsMetadata([]*db.ViewMetadata{{
+		Name:           "books_by_pages",
+		BaseTable:      "books",
+		Columns:        []string{"pages", "title"},
+		PartitionKeys:  []string{"pages"},
+		ClusteringKeys: []db.ColumnOrder{{Column: "title", Order: "ASC"}},
+		WhereClause:    "pages IS NOT NULL AND title IS NOT NULL",
+	}})
+	session.AddTableOptions(&db.TableOptions{})
+
+	w := executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books", "", nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var table m.Table
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &table))
+	assert.Equal(t, []m.View{{
+		Name:        "books_by_pages",
+		ColumnNames: []string{"pages", "title"},
+		PrimaryKey:  &m.PrimaryKey{PartitionKey: []string{"pages"}, ClusteringKey: []string{"title"}},
+		ClusteringExpression: []m.ClusteringExpression{
+			{Column: strPtr("title"), Order: strPtr("asc")},
+		},
+		WhereClause: "pages IS NOT NULL AND title IS NOT NULL",
+	}}, table.Views)
+}
+
+func TestRestTableOptions(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	tableName := "books"
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{{"table_name": &tableName}}, nil)
+	session.
+		On("ExecuteIter", "SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ?",
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil).
+		On("ChangeSchema", `CREATE TABLE "store"."authors" ("name" text, PRIMARY KEY ("name")) `+
+			`WITH default_time_to_live = 3600 AND compaction = {'class': 'LeveledCompactionStrategy'}`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `ALTER TABLE "store"."books" WITH gc_grace_seconds = 3600 AND comment = 'Catalog'`,
+			mock.Anything).
+		Return(nil)
+
+	w := executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables",
+		`{"name":"authors","columnDefinitions":[{"name":"name","typeDefinition":"text"}],`+
+			`"primaryKey":{"partitionKey":["name"]},`+
+			`"tableOptions":{"defaultTimeToLive":3600,"compaction":{"class":"LeveledCompactionStrategy"}}}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store/tables/books",
+		`{"tableOptions":{"gcGraceSeconds":3600,"comment":"Catalog"}}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store/tables/books",
+		`{"tableOptions":{"bloomFilterFpChance":2}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store/tables/books", `{"tableOptions":{}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	ttl := 60
+	comment := "Catalog"
+	session.AddViewsMetadata(nil)
+	session.AddTableOptions(&db.TableOptions{
+		DefaultTimeToLive: &ttl,
+		Comment:           &comment,
+		Compression:       map[string]string{"class": "LZ4Compressor"},
+	})
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/tables/books", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var table m.Table
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &table))
+	assert.Equal(t, int32(60), *table.TableOptions.DefaultTimeToLive)
+	assert.Equal(t, "Catalog", *table.TableOptions.Comment)
+	assert.Nil(t, table.TableOptions.GcGraceSeconds)
+	assert.Equal(t, map[string]string{"class": "LZ4Compressor"}, table.TableOptions.Compression)
+}
+
+func TestRestKeyspaces(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	keyspace, _ := session.KeyspaceMetadata("store")
+	keyspace.StrategyClass = "org.apache.cassandra.locator.NetworkTopologyStrategy"
+	keyspace.StrategyOptions = map[string]interface{}{"dc2": "1", "dc1": "3"}
+	keyspace.DurableWrites = true
+	keyspace.Views = map[string]*gocql.ViewMetadata{
+		"address": {
+			Keyspace:   "store",
+			Name:       "address",
+			FieldNames: []string{"street"},
+			FieldTypes: []gocql.TypeInfo{gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}
+
+	tableName := "books"
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{{"table_name": &tableName}}, nil)
+	session.
+		On("ExecuteIter", "SELECT table_name FROM system_schema.tables WHERE keyspace_name = ?",
+			mock.Anything, mock.Anything).
+		Return(resultMock, nil).
+		On("KeyspaceMetadata", "other").
+		Return((*gocql.KeyspaceMetadata)(nil), errors.New("keyspace does not exist"))
+
+	w := executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name":"store","replicationStrategy":"NetworkTopologyStrategy",`+
+		`"dataCenters":[{"name":"dc1","replicas":3},{"name":"dc2","replicas":1}],"durableWrites":true,`+
+		`"tables":["books"],`+
+		`"types":[{"name":"address","keyspace":"store","fieldDefinitions":[{"name":"street","typeDefinition":"text"}]}]}`,
+		w.Body.String())
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/other", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	session.
+		On("ChangeSchema", `CREATE KEYSPACE IF NOT EXISTS "dev" WITH REPLICATION = `+
+			`{ 'class': 'SimpleStrategy', 'replication_factor': 1 }`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `DROP KEYSPACE IF EXISTS "dev"`, mock.Anything).
+		Return(nil)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces",
+		`{"name":"dev","replicationFactor":1,"ifNotExists":true}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces", `{"name":"dev"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/dev?ifExists=true", "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRestUpdateKeyspace(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	session.
+		On("ChangeSchema", `ALTER KEYSPACE "store" WITH REPLICATION = `+
+			`{ 'class': 'SimpleStrategy', 'replication_factor': 3 } AND DURABLE_WRITES = true`, mock.Anything).
+		Return(nil)
+
+	w := executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store",
+		`{"replicationFactor":3,"durableWrites":true}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store",
+		`{"replicationFactor":3,"dataCenters":[{"name":"dc1","replicas":3}]}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = executeRest(handler, http.MethodPut, "/rest/v1/keyspaces/store", `{}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRestViews(t *testing.T) {
+	session, handler := createRestHandler(t)
+	session.AddViewsMetadata([]*db.ViewMetadata{
+		{Name: "books_by_pages", BaseTable: "books", PartitionKeys: []string{"pages"}},
+		{Name: "authors_by_name", BaseTable: "authors", PartitionKeys: []string{"name"}},
+	})
+
+	session.
+		On("ChangeSchema", `CREATE MATERIALIZED VIEW "store"."books_by_pages" AS SELECT * FROM "store"."books" `+
+			`WHERE "pages" IS NOT NULL AND "title" IS NOT NULL PRIMARY KEY (("pages"), "title") `+
+			`WITH CLUSTERING ORDER BY ("title" DESC)`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `CREATE MATERIALIZED VIEW "store"."books_by_pages" AS SELECT * FROM "store"."books" `+
+			`WHERE "pages" IS NOT NULL AND "title" IS NOT NULL AND "title" = 'it''s' PRIMARY KEY (("pages"), "title")`,
+			mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `DROP MATERIALIZED VIEW IF EXISTS "store"."books_by_pages"`, mock.Anything).
+		Return(nil)
+
+	w := executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/views",
+		`{"name":"books_by_pages","primaryKey":{"partitionKey":["pages"],"clusteringKey":["title"]},`+
+			`"clusteringExpression":[{"column":"title","order":"desc"}]}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/views",
+		`{"name":"books_by_pages","primaryKey":{"partitionKey":["pages"],"clusteringKey":["title"]},`+
+			`"where":[{"columnName":"title","operator":"eq","value":"it's"}]}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodDelete,
+		"/rest/v1/keyspaces/store/tables/books/views/books_by_pages?ifExists=true", "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// The view must belong to the table in the path
+	w = executeRest(handler, http.MethodDelete,
+		"/rest/v1/keyspaces/store/tables/books/views/authors_by_name?ifExists=true", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/store/tables/books/views/unknown", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// The restrictions are on the primary key columns, the values are validated against the column types
+	for _, where := range []string{
+		`[{"columnName":"first_name","operator":"eq","value":"a"}]`,
+		`[{"columnName":"title","operator":"notEq","value":"a"}]`,
+		`[{"columnName":"title","operator":"eq","value":{"a":1}}]`,
+	} {
+		w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/views",
+			`{"name":"books_by_pages","primaryKey":{"partitionKey":["pages"],"clusteringKey":["title"]},`+
+				`"where":`+where+`}`, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, where)
+	}
+	session.AssertNumberOfCalls(t, "ChangeSchema", 3)
+}
+
+func TestRestUserTypes(t *testing.T) {
+	session, handler := createRestHandler(t)
+
+	keyspace, _ := session.KeyspaceMetadata("store")
+	keyspace.Views = map[string]*gocql.ViewMetadata{
+		"address": {
+			Keyspace:   "store",
+			Name:       "address",
+			FieldNames: []string{"street", "zip"},
+			FieldTypes: []gocql.TypeInfo{
+				gocql.NewNativeType(0, gocql.TypeText, ""),
+				gocql.NewNativeType(0, gocql.TypeInt, ""),
+			},
+		},
+	}
+
+	w := executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/types", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"name":"address","keyspace":"store","fieldDefinitions":[`+
+		`{"name":"street","typeDefinition":"text"},{"name":"zip","typeDefinition":"int"}]}]`, w.Body.String())
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/keyspaces/store/types/phone", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	session.
+		On("ChangeSchema", `CREATE TYPE "store"."phone" ("number" text)`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `ALTER TYPE "store"."phone" ADD "country" int`, mock.Anything).
+		Return(nil).
+		On("ChangeSchema", `DROP TYPE "store"."phone"`, mock.Anything).
+		Return(nil)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/types",
+		`{"name":"phone","fieldDefinitions":[{"name":"number","typeDefinition":"text"}]}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/types/phone/fields",
+		`{"name":"country","typeDefinition":"int"}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/keyspaces/store/types/phone", "", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRestRoles(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	endpoint := createConfig(t).WithSuperuserRole("admin").newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+	handler := &authHandler{t, router, map[string]string{"token1": "admin", "token2": "user1"}}
+	adminHeader := http.Header{"X-Cassandra-Token": []string{"token1"}}
+
+	roleName, login, superuser := "reader", true, false
+	resource, permission := "<table store.books>", "SELECT"
+	rolesMock := &db.ResultMock{}
+	rolesMock.On("Values").Return([]map[string]interface{}{
+		{"role": &roleName, "can_login": &login, "is_superuser": &superuser},
+	}, nil)
+	permissionsMock := &db.ResultMock{}
+	permissionsMock.On("Values").Return([]map[string]interface{}{
+		{"role": &roleName, "resource": &resource, "permission": &permission},
+	}, nil)
+
+	sessionMock.
+		On("ExecuteIter", "SELECT role, can_login, is_superuser, member_of FROM system_auth.roles",
+			mock.Anything, mock.Anything).
+		Return(rolesMock, nil).
+		On("ExecuteIter", `LIST ALL PERMISSIONS OF "reader"`, mock.Anything, mock.Anything).
+		Return(permissionsMock, nil).
+		On("Execute", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	w := executeRest(handler, http.MethodGet, "/rest/v1/roles/reader", "", adminHeader)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name":"reader","login":true,"superuser":false}`, w.Body.String())
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/roles/writer", "", adminHeader)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/roles/reader/permissions", "", adminHeader)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"role":"reader","resource":"<table store.books>","keyspace":"store","table":"books",`+
+		`"permission":"SELECT"}]`, w.Body.String())
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/roles",
+		`{"name":"writer","password":"secret","login":true}`, adminHeader)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	sessionMock.AssertCalled(t, "Execute",
+		`CREATE ROLE "writer" WITH PASSWORD = 'secret' AND LOGIN = true AND SUPERUSER = false`,
+		mock.Anything, mock.Anything)
+
+	w = executeRest(handler, http.MethodPut, "/rest/v1/roles/writer", `{"superuser":true}`, adminHeader)
+	assert.Equal(t, http.StatusOK, w.Code)
+	sessionMock.AssertCalled(t, "Execute", `ALTER ROLE "writer" WITH SUPERUSER = true`, mock.Anything, mock.Anything)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/roles/writer/permissions",
+		`{"permission":"modify","keyspace":"store"}`, adminHeader)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	sessionMock.AssertCalled(t, "Execute", `GRANT MODIFY ON KEYSPACE "store" TO "writer"`,
+		mock.Anything, mock.Anything)
+
+	w = executeRest(handler, http.MethodDelete,
+		"/rest/v1/roles/writer/permissions/modify?keyspace=store&table=books", "", adminHeader)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	sessionMock.AssertCalled(t, "Execute", `REVOKE MODIFY ON TABLE "store"."books" FROM "writer"`,
+		mock.Anything, mock.Anything)
+
+	w = executeRest(handler, http.MethodDelete, "/rest/v1/roles/writer?ifExists=true", "", adminHeader)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	sessionMock.AssertCalled(t, "Execute", `DROP ROLE IF EXISTS "writer"`, mock.Anything, mock.Anything)
+
+	w = executeRest(handler, http.MethodGet, "/rest/v1/roles", "",
+		http.Header{"X-Cassandra-Token": []string{"token2"}})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRestMigrations(t *testing.T) {
+	session, handler := createRestHandler(t)
+	session.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+
+	body := `{"keyspaces":[{"name":"store","tables":[{"name":"books","columnDefinitions":[` +
+		`{"name":"title","typeDefinition":"text"},{"name":"pages","typeDefinition":"int"},` +
+		`{"name":"year","typeDefinition":"int"}],"primaryKey":{"partitionKey":["title"]}}]}]}`
+
+	w := executeRest(handler, http.MethodPost, "/rest/v1/migrations/plan", body, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"destructive":true,"statements":[`+
+		`{"query":"ALTER TABLE \"store\".\"books\" DROP \"first_name\"","destructive":true},`+
+		`{"query":"ALTER TABLE \"store\".\"books\" DROP \"last_name\"","destructive":true},`+
+		`{"query":"ALTER TABLE \"store\".\"books\" ADD (\"year\" int)","destructive":false}]}`,
+		w.Body.String())
+	session.AssertNotCalled(t, "ChangeSchema", mock.Anything, mock.Anything)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/migrations/apply", body, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	session.AssertNotCalled(t, "ChangeSchema", mock.Anything, mock.Anything)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/migrations/apply?allowDestructive=true", body, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	session.AssertCalled(t, "ChangeSchema", `ALTER TABLE "store"."books" ADD ("year" int)`, mock.Anything)
+	session.AssertCalled(t, "ChangeSchema", `ALTER TABLE "store"."books" DROP "last_name"`, mock.Anything)
+
+	w = executeRest(handler, http.MethodPost, "/rest/v1/migrations/plan",
+		`{"keyspaces":[{"name":"system","replicationFactor":1}]}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRestImport(t *testing.T) {
+	session, router := createRestHandler(t)
+	handler := e.RowsActions(router)
+	resultMock := &db.ResultMock{}
+	resultMock.On("Values").Return([]map[string]interface{}{}, nil)
+	session.
+		On("ExecuteIter", `INSERT INTO "store"."books" ("pages", "title") VALUES (?, ?)`, mock.Anything, mock.Anything).
+		Return(resultMock, nil)
+
+	w := executeRest(handler, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows:import",
+		`{"title":"book1","pages":10}`+"\n"+`{"title":"book2","pages":"a"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response m.RowsImportResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Inserted)
+	assert.Equal(t, 1, response.Failed)
+	assert.Equal(t, 2, response.Errors[0].Line)
+
+	// The counts of the rows read before a body failure are returned along with the error
+	r := httptest.NewRequest(http.MethodPost, "http://"+host+"/rest/v1/keyspaces/store/tables/books/rows:import",
+		io.MultiReader(strings.NewReader(`{"title":"book1","pages":10}`+"\n"), &failingReader{}))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	response = m.RowsImportResponse{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Inserted)
+	assert.Equal(t, 0, response.Failed)
+	assert.Contains(t, response.Error, "unable to read import body")
+
+	// Without rewriting the path, the router can't match it
+	w = executeRest(router, http.MethodPost, "/rest/v1/keyspaces/store/tables/books/rows:import", "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func createRestHandler(t *testing.T) (*db.SessionMock, http.Handler) {
+	sessionMock := db.NewSessionMock().Default()
+	return sessionMock, createRestHandlerWithSession(t, sessionMock)
+}
+
+func createRestHandlerWithSession(t *testing.T, sessionMock *db.SessionMock) http.Handler {
+	endpoint := createConfig(t).newEndpointWithDb(db.NewDbWithSession(sessionMock))
+
+	router := httprouter.New()
+	for _, route := range endpoint.RoutesRest("/rest", config.AllSchemaOperations, "") {
+		router.Handler(route.Method, route.Pattern, route.Handler)
+	}
+
+	return router
+}
+
+// matchOptions matches the query options ignoring the request context
+func matchOptions(expected *db.QueryOptions) interface{} {
+	return mock.MatchedBy(func(options *db.QueryOptions) bool {
+		actual := *options
+		actual.Context = nil
+		return reflect.DeepEqual(expected, &actual)
+	})
+}
+
+type failingReader struct{}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+type auditWriter struct {
+	entries []*audit.Entry
+}
+
+func (w *auditWriter) Write(entry *audit.Entry) error {
+	w.entries = append(w.entries, entry)
+	return nil
+}
+
+type recordingSink struct {
+	events []*events.Event
+}
+
+func (s *recordingSink) Send(event *events.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func executeRest(handler http.Handler, method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "http://"+host+target, strings.NewReader(body))
+	if header != nil {
+		r.Header = header
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+// withClaims adds the identity claims to the context of the requests
+func withClaims(handler http.Handler, claims map[string]interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(auth.WithContextClaims(r.Context(), claims)))
+	})
+}
+
+func bookRow(title string, pages int) map[string]interface{} {
+	return map[string]interface{}{"title": &title, "pages": &pages}
+}
+
+func strPtr(value string) *string {
+	return &value
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"github.com/gocql/gocql"
+	"sync"
+	"time"
+)
+
+// subscriptionBufferSize is the amount of events that are buffered for each subscriber of the local broker
+const subscriptionBufferSize = 64
+
+// Operation is the kind of change written through the API
+type Operation string
+
+const (
+	Insert Operation = "insert"
+	Update Operation = "update"
+	Delete Operation = "delete"
+)
+
+// Event is a change written through the API
+type Event struct {
+	Keyspace  string    `json:"keyspace"`
+	Table     string    `json:"table"`
+	Operation Operation `json:"operation"`
+	// PrimaryKey contains the primary key values provided in the mutation by CQL column name
+	PrimaryKey map[string]interface{} `json:"primaryKey"`
+	// Values contains the rest of the values provided in the mutation by CQL column name
+	Values     map[string]interface{} `json:"values"`
+	UserOrRole string                 `json:"userOrRole,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// NewEvent creates an event for the mutation of the provided columns, separating the primary key values
+func NewEvent(
+	table *gocql.TableMetadata,
+	operation Operation,
+	columns []string,
+	values []interface{},
+	userOrRole string,
+) *Event {
+	event := &Event{
+		Keyspace:   table.Keyspace,
+		Table:      table.Name,
+		Operation:  operation,
+		PrimaryKey: make(map[string]interface{}),
+		Values:     make(map[string]interface{}),
+		UserOrRole: userOrRole,
+		Timestamp:  time.Now().UTC(),
+	}
+
+	for i, name := range columns {
+		column := table.Columns[name]
+		if column != nil && (column.Kind == gocql.ColumnPartitionKey || column.Kind == gocql.ColumnClusteringKey) {
+			event.PrimaryKey[name] = values[i]
+		} else {
+			event.Values[name] = values[i]
+		}
+	}
+	return event
+}
+
+// Value returns the value of the column, whether it's part of the primary key or not
+func (e *Event) Value(column string) (interface{}, bool) {
+	if value, ok := e.PrimaryKey[column]; ok {
+		return value, true
+	}
+	value, ok := e.Values[column]
+	return value, ok
+}
+
+// Broker distributes the events to the subscribers of a keyspace, implementations can connect multiple instances
+// of the server
+type Broker interface {
+	Publish(event *Event)
+	Subscribe(keyspace string) Subscription
+}
+
+// Subscription is a stream of events, the channel is closed after the subscription is closed
+type Subscription interface {
+	Events() <-chan *Event
+	Close()
+}
+
+// LocalBroker is a Broker that distributes the events of a single process, events are discarded for subscribers
+// that are not able to keep up
+type LocalBroker struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[*localSubscription]bool
+}
+
+type localSubscription struct {
+	broker   *LocalBroker
+	keyspace string
+	events   chan *Event
+	once     sync.Once
+}
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{subscribers: make(map[string]map[*localSubscription]bool)}
+}
+
+func (b *LocalBroker) Publish(event *Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for subscription := range b.subscribers[event.Keyspace] {
+		select {
+		case subscription.events <- event:
+		default:
+		}
+	}
+}
+
+func (b *LocalBroker) Subscribe(keyspace string) Subscription {
+	subscription := &localSubscription{
+		broker:   b,
+		keyspace: keyspace,
+		events:   make(chan *Event, subscriptionBufferSize),
+	}
+
+	b.mutex.Lock()
+	if b.subscribers[keyspace] == nil {
+		b.subscribers[keyspace] = make(map[*localSubscription]bool)
+	}
+	b.subscribers[keyspace][subscription] = true
+	b.mutex.Unlock()
+	return subscription
+}
+
+func (s *localSubscription) Events() <-chan *Event {
+	return s.events
+}
+
+func (s *localSubscription) Close() {
+	s.once.Do(func() {
+		s.broker.mutex.Lock()
+		delete(s.broker.subscribers[s.keyspace], s)
+		if len(s.broker.subscribers[s.keyspace]) == 0 {
+			delete(s.broker.subscribers, s.keyspace)
+		}
+		s.broker.mutex.Unlock()
+		close(s.events)
+	})
+}
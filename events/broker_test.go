@@ -0,0 +1,36 @@
+package events
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLocalBroker(t *testing.T) {
+	broker := NewLocalBroker()
+	subscription := broker.Subscribe("ks1")
+	other := broker.Subscribe("ks2")
+
+	event := &Event{Keyspace: "ks1", Table: "tbl1", Operation: Insert, Values: map[string]interface{}{"a": 1}}
+	broker.Publish(event)
+	assert.Equal(t, event, <-subscription.Events())
+	assert.Len(t, other.Events(), 0)
+
+	subscription.Close()
+	subscription.Close()
+	_, ok := <-subscription.Events()
+	assert.False(t, ok)
+
+	// Publishing after closing is a no-op
+	broker.Publish(event)
+	other.Close()
+	assert.Empty(t, broker.subscribers)
+}
+
+func TestLocalBrokerDiscardsEvents(t *testing.T) {
+	broker := NewLocalBroker()
+	subscription := broker.Subscribe("ks1")
+	for i := 0; i < subscriptionBufferSize+10; i++ {
+		broker.Publish(&Event{Keyspace: "ks1", Table: "tbl1", Operation: Delete})
+	}
+	assert.Len(t, subscription.Events(), subscriptionBufferSize)
+}
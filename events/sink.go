@@ -0,0 +1,238 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/log"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	// SignatureHeader contains the HMAC-SHA256 signature of the webhook request body, using the hex encoding and
+	// prefixed with "sha256="
+	SignatureHeader = "X-Data-API-Signature"
+
+	DefaultWebhookMaxRetries = 3
+	webhookQueueSize         = 1024
+	webhookTimeout           = 10 * time.Second
+	webhookInitialBackoff    = 500 * time.Millisecond
+)
+
+// ErrWebhookQueueFull is returned when the event can not be queued as the webhook is not keeping up with the mutations
+var ErrWebhookQueueFull = errors.New("webhook queue is full, the event was discarded")
+
+// Sink receives the events of the successful mutations, implementations must not block for long periods as events
+// are sent from the request goroutine
+type Sink interface {
+	Send(event *Event) error
+}
+
+type sinkBroker struct {
+	Broker
+	sinks  []Sink
+	logger log.Logger
+}
+
+// WithSinks returns a broker that also sends the published events to the sinks, the broker can be nil when the events
+// are only sent to the sinks
+func WithSinks(broker Broker, sinks []Sink, logger log.Logger) Broker {
+	if len(sinks) == 0 {
+		return broker
+	}
+	return &sinkBroker{Broker: broker, sinks: sinks, logger: logger}
+}
+
+func (b *sinkBroker) Publish(event *Event) {
+	if b.Broker != nil {
+		b.Broker.Publish(event)
+	}
+
+	for _, sink := range b.sinks {
+		if err := sink.Send(event); err != nil {
+			b.logger.Error("unable to send event to sink",
+				"keyspace", event.Keyspace,
+				"table", event.Table,
+				"error", err)
+		}
+	}
+}
+
+// WebhookSink posts the events as JSON to a url, in order and from a background goroutine. Failed requests are
+// retried with exponential backoff.
+type WebhookSink struct {
+	url        string
+	secret     []byte
+	maxRetries int
+	backoff    time.Duration
+	client     *http.Client
+	queue      chan []byte
+	logger     log.Logger
+}
+
+// NewWebhookSink creates a webhook sink, requests are signed when the secret is not empty
+func NewWebhookSink(url string, secret string, maxRetries int, logger log.Logger) *WebhookSink {
+	sink := &WebhookSink{
+		url:        url,
+		secret:     []byte(secret),
+		maxRetries: maxRetries,
+		backoff:    webhookInitialBackoff,
+		client:     &http.Client{Timeout: webhookTimeout},
+		queue:      make(chan []byte, webhookQueueSize),
+		logger:     logger,
+	}
+	go sink.run()
+	return sink
+}
+
+// Send enqueues the event, the event is discarded and an error is returned when the queue is full
+func (s *WebhookSink) Send(event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case s.queue <- body:
+		return nil
+	default:
+		return ErrWebhookQueueFull
+	}
+}
+
+func (s *WebhookSink) run() {
+	for body := range s.queue {
+		backoff := s.backoff
+		for attempt := 0; ; attempt++ {
+			err := s.post(body)
+			if err == nil {
+				break
+			}
+
+			if attempt >= s.maxRetries {
+				s.logger.Error("unable to deliver event to webhook", "url", s.url, "attempts", attempt+1, "error", err)
+				break
+			}
+
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
+
+func (s *WebhookSink) post(body []byte) error {
+	request, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	if len(s.secret) > 0 {
+		request.Header.Set(SignatureHeader, Signature(s.secret, body))
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+	return nil
+}
+
+// Signature returns the value of the signature header for the body
+func Signature(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// FileSink appends the events to a file as newline-delimited JSON
+type FileSink struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Send(event *Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// MarshalJSON encodes the event, maps with non-string keys are encoded as objects using the string representation of
+// the keys
+func (e *Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	encoded := event(*e)
+	encoded.PrimaryKey = jsonValues(e.PrimaryKey)
+	encoded.Values = jsonValues(e.Values)
+	return json.Marshal(encoded)
+}
+
+func jsonValues(values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		result[key] = jsonValue(value)
+	}
+	return result
+}
+
+func jsonValue(value interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		if rv.Elem().Kind() == reflect.Map || rv.Elem().Kind() == reflect.Slice {
+			return jsonValue(rv.Elem().Interface())
+		}
+	case reflect.Map:
+		result := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			result[fmt.Sprint(iter.Key().Interface())] = jsonValue(iter.Value().Interface())
+		}
+		return result
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Blobs are encoded using base64
+			return value
+		}
+		result := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result = append(result, jsonValue(rv.Index(i).Interface()))
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/datastax/cassandra-data-apis/log"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var tableMock = &gocql.TableMetadata{
+	Keyspace: "ks1",
+	Name:     "tbl1",
+	Columns: map[string]*gocql.ColumnMetadata{
+		"id":    {Name: "id", Kind: gocql.ColumnPartitionKey},
+		"value": {Name: "value", Kind: gocql.ColumnRegular},
+		"tags":  {Name: "tags", Kind: gocql.ColumnRegular},
+	},
+}
+
+func TestNewEvent(t *testing.T) {
+	event := NewEvent(tableMock, Update, []string{"value", "id"}, []interface{}{"a", 1}, "user1")
+	assert.Equal(t, map[string]interface{}{"id": 1}, event.PrimaryKey)
+	assert.Equal(t, map[string]interface{}{"value": "a"}, event.Values)
+	assert.Equal(t, "user1", event.UserOrRole)
+	assert.False(t, event.Timestamp.IsZero())
+
+	value, ok := event.Value("id")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+}
+
+func TestWebhookSink(t *testing.T) {
+	var requests int32
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, Signature([]byte("secret1"), body), r.Header.Get(SignatureHeader))
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// Fail the first attempt
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bodies <- body
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, "secret1", 2, log.NewZapLogger(zap.NewNop()))
+	sink.backoff = time.Millisecond
+	event := NewEvent(tableMock, Insert, []string{"id", "value"}, []interface{}{1, "a"}, "")
+	assert.NoError(t, sink.Send(event))
+
+	select {
+	case body := <-bodies:
+		var decoded map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &decoded))
+		assert.Equal(t, "insert", decoded["operation"])
+		assert.Equal(t, map[string]interface{}{"id": float64(1)}, decoded["primaryKey"])
+		assert.Equal(t, map[string]interface{}{"value": "a"}, decoded["values"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestWebhookSinkQueueFull(t *testing.T) {
+	// The queue is not consumed
+	sink := &WebhookSink{queue: make(chan []byte, 1)}
+	event := NewEvent(tableMock, Insert, []string{"id", "value"}, []interface{}{1, "a"}, "")
+	assert.NoError(t, sink.Send(event))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sink.Send(event)
+	}()
+
+	select {
+	case err := <-done:
+		assert.Equal(t, ErrWebhookQueueFull, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("send blocked on a full queue")
+	}
+	assert.Len(t, sink.queue, 1)
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "events.ndjson")
+	sink, err := NewFileSink(path)
+	assert.NoError(t, err)
+
+	broker := WithSinks(nil, []Sink{sink}, log.NewZapLogger(zap.NewNop()))
+	broker.Publish(NewEvent(tableMock, Insert, []string{"id", "tags"},
+		[]interface{}{1, map[interface{}]interface{}{2: "b"}}, ""))
+	broker.Publish(NewEvent(tableMock, Delete, []string{"id"}, []interface{}{1}, ""))
+	assert.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+
+	assert.Len(t, lines, 2)
+	assert.Equal(t, map[string]interface{}{"tags": map[string]interface{}{"2": "b"}}, lines[0]["values"])
+	assert.Equal(t, "delete", lines[1]["operation"])
+}
@@ -0,0 +1,33 @@
+package graphql
+
+import (
+	"context"
+	"github.com/datastax/cassandra-data-apis/audit"
+	"github.com/graphql-go/graphql"
+)
+
+// auditContext returns the context used to execute the statements of a mutation, marking them as part of the GraphQL
+// operation
+func auditContext(params graphql.ResolveParams, keyspace string, table string) context.Context {
+	return audit.WithOperation(params.Context, &audit.Operation{
+		Api:      audit.GraphQL,
+		Name:     params.Info.FieldName,
+		Keyspace: keyspace,
+		Table:    table,
+	})
+}
+
+// auditedFields wraps the resolvers of the schema and admin mutations to execute their statements with an audit
+// context, the keyspace and table are read from the arguments
+func auditedFields(fields graphql.Fields) graphql.Fields {
+	for _, field := range fields {
+		resolve := field.Resolve
+		field.Resolve = func(params graphql.ResolveParams) (interface{}, error) {
+			keyspace, _ := params.Args["keyspaceName"].(string)
+			table, _ := params.Args["tableName"].(string)
+			params.Context = auditContext(params, keyspace, table)
+			return resolve(params)
+		}
+	}
+	return fields
+}
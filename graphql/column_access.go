@@ -0,0 +1,24 @@
+package graphql
+
+import (
+	"github.com/datastax/cassandra-data-apis/auth"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"github.com/graphql-go/graphql"
+)
+
+// columnAccess returns the access of the request user or role to the columns of the table, nil when it's not
+// restricted
+func (sg *SchemaGenerator) columnAccess(params graphql.ResolveParams, table *gocql.TableMetadata) *config.ColumnAccess {
+	return sg.columnPolicies.Access(table, auth.ContextUserOrRole(params.Context))
+}
+
+// conditionColumns returns the columns used in the conditions
+func conditionColumns(conditions []types.ConditionItem) []string {
+	columns := make([]string, 0, len(conditions))
+	for _, item := range conditions {
+		columns = append(columns, item.Column)
+	}
+	return columns
+}
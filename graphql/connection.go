@@ -0,0 +1,385 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"github.com/graphql-go/graphql"
+	"strings"
+)
+
+// cursorProtoVersion is the native protocol version used to serialize the primary key values contained in cursors
+const cursorProtoVersion = 4
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PageInfo",
+	Description: "Information about the page of a connection.",
+	Fields: graphql.Fields{
+		"hasNextPage":     {Type: graphql.NewNonNull(graphql.Boolean)},
+		"hasPreviousPage": {Type: graphql.NewNonNull(graphql.Boolean)},
+		"startCursor":     {Type: graphql.String},
+		"endCursor":       {Type: graphql.String},
+	},
+})
+
+// cursorValue is a serialized primary key value that is sent to the server as is
+type cursorValue []byte
+
+func (v cursorValue) MarshalCQL(_ gocql.TypeInfo) ([]byte, error) {
+	return v, nil
+}
+
+// buildConnectionTypes builds the Relay connection types of the tables that can be paged by primary key position
+func (s *KeyspaceGraphQLSchema) buildConnectionTypes(keyspace *gocql.KeyspaceMetadata) {
+	s.connectionTypes = make(map[string]*graphql.Object, len(keyspace.Tables))
+	if !s.schemaGen.relayConnections {
+		return
+	}
+
+	for _, table := range keyspace.Tables {
+		if s.ignoredTables[table.Name] || !hasUniformClusteringOrder(table) {
+			continue
+		}
+
+		edgeType := graphql.NewObject(graphql.ObjectConfig{
+			Description: fmt.Sprintf("An edge of the '%s' table connection.", table.Name),
+			Name:        s.naming.ToGraphQLTypeUnique(table.Name, "Edge"),
+			Fields: graphql.Fields{
+				"cursor": {Type: graphql.NewNonNull(graphql.String)},
+				"node":   {Type: graphql.NewNonNull(s.tableValueTypes[table.Name])},
+			},
+		})
+
+		s.connectionTypes[table.Name] = graphql.NewObject(graphql.ObjectConfig{
+			Description: fmt.Sprintf("Relay connection type for the '%s' table.", table.Name),
+			Name:        s.naming.ToGraphQLTypeUnique(table.Name, "Connection"),
+			Fields: graphql.Fields{
+				"edges":    {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType)))},
+				"pageInfo": {Type: graphql.NewNonNull(pageInfoType)},
+			},
+		})
+	}
+}
+
+// hasUniformClusteringOrder determines whether all the clustering columns are sorted in the same direction, the
+// position of a row can only be expressed as a single tuple relation in that case
+func hasUniformClusteringOrder(table *gocql.TableMetadata) bool {
+	for _, column := range table.ClusteringColumns {
+		if column.Order != table.ClusteringColumns[0].Order {
+			return false
+		}
+	}
+	return true
+}
+
+func (sg *SchemaGenerator) buildConnectionField(
+	ksSchema *KeyspaceGraphQLSchema,
+	table *gocql.TableMetadata,
+) *graphql.Field {
+	return &graphql.Field{
+		Description: fmt.Sprintf("Retrieves data from '%s' table using the equality operator, ", table.Name) +
+			"as a Relay connection in primary key order.\n" +
+			"Use the cursor of an edge as the 'after' argument to obtain the following rows.",
+		Type: ksSchema.connectionTypes[table.Name],
+		Args: graphql.FieldConfigArgument{
+			"value": {Type: ksSchema.tableScalarInputTypes[table.Name]},
+			"first": {Type: graphql.Int, DefaultValue: config.DefaultPageSize},
+			"after": {Type: graphql.String},
+		},
+		Resolve: sg.connectionFieldResolver(table, ksSchema),
+	}
+}
+
+func (sg *SchemaGenerator) connectionFieldResolver(
+	table *gocql.TableMetadata,
+	ksSchema *KeyspaceGraphQLSchema,
+) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		userOrRole, err := sg.checkUserOrRoleAuth(params)
+		if err != nil {
+			return nil, err
+		}
+
+		first, _ := params.Args["first"].(int)
+		if first <= 0 {
+			return nil, errors.New("first must be a positive number")
+		}
+
+		var whereClause []types.ConditionItem
+		if value, ok := params.Args["value"].(map[string]interface{}); ok {
+			whereClause = make([]types.ConditionItem, 0, len(value))
+			for key, value := range value {
+				whereClause = append(whereClause, types.ConditionItem{
+					Column:   ksSchema.naming.ToCQLColumn(table.Name, key),
+					Operator: "=",
+					Value:    adaptParameterValue(value),
+				})
+			}
+		}
+
+		// Cursors contain the primary key values
+		access := sg.columnAccess(params, table)
+		columns := conditionColumns(whereClause)
+		for _, column := range primaryKeyColumns(table) {
+			columns = append(columns, column.Name)
+		}
+		if err := access.CheckUnrestricted(columns...); err != nil {
+			return nil, err
+		}
+
+		rowAccess, err := sg.rowAccess(params, table)
+		if err != nil {
+			return nil, err
+		}
+		if whereClause, err = rowAccess.Restrict(whereClause); err != nil {
+			return nil, err
+		}
+
+		var position []cursorValue
+		if after, ok := params.Args["after"].(string); ok && after != "" {
+			if position, err = decodeCursor(table, after); err != nil {
+				return nil, err
+			}
+		}
+
+		info := afterPosition(table, whereClause, position, first+1)
+		nextPartitions := position != nil && !isPartitionRestricted(table, whereClause)
+		if info == nil {
+			if !nextPartitions {
+				return connectionResult(table, ksSchema, nil, first, access)
+			}
+			info, nextPartitions = afterPartition(table, whereClause, position, first+1), false
+		}
+
+		options := db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context)
+		load := sg.selectLoad(params.Context, info, options)
+
+		return func() (interface{}, error) {
+			result, err := load()
+			if err != nil {
+				return nil, err
+			}
+
+			rows := result.Values()
+			if nextPartitions && len(rows) <= first {
+				// Continue with the following partitions in token order
+				result, err = sg.dbClient.Select(
+					afterPartition(table, whereClause, position, first+1-len(rows)), options)
+				if err != nil {
+					return nil, err
+				}
+				rows = append(rows, result.Values()...)
+			}
+
+			return connectionResult(table, ksSchema, rows, first, access)
+		}, nil
+	}
+}
+
+// afterPosition builds the query for the rows following the position within its partition, or the first rows
+// matching the condition when there's no position. It returns nil when there are no more rows in the partition.
+func afterPosition(
+	table *gocql.TableMetadata,
+	whereClause []types.ConditionItem,
+	position []cursorValue,
+	limit int,
+) *db.SelectInfo {
+	info := &db.SelectInfo{
+		Keyspace: table.Keyspace,
+		Table:    table.Name,
+		Where:    whereClause,
+		Options:  &types.QueryOptions{Limit: limit},
+	}
+
+	if position == nil {
+		return info
+	}
+
+	restricted := restrictedColumns(whereClause)
+	condition := append([]types.ConditionItem{}, whereClause...)
+	for i, column := range table.PartitionKey {
+		if !restricted[column.Name] {
+			condition = append(condition, types.ConditionItem{Column: column.Name, Operator: "=", Value: position[i]})
+		}
+	}
+
+	// Clustering columns restricted by the equality operator are a prefix of the clustering key
+	offset := 0
+	for offset < len(table.ClusteringColumns) && restricted[table.ClusteringColumns[offset].Name] {
+		offset++
+	}
+
+	if offset == len(table.ClusteringColumns) {
+		// There's a single row per partition
+		return nil
+	}
+
+	tuple := types.ColumnTuple{}
+	for i, column := range table.ClusteringColumns[offset:] {
+		tuple.Columns = append(tuple.Columns, column.Name)
+		tuple.Values = append(tuple.Values, position[len(table.PartitionKey)+offset+i])
+	}
+
+	operator := ">"
+	if table.ClusteringColumns[0].Order == gocql.DESC {
+		operator = "<"
+	}
+
+	info.Where = append(condition, types.ConditionItem{
+		Column:   strings.Join(tuple.Columns, ", "),
+		Operator: operator,
+		Value:    tuple,
+	})
+	return info
+}
+
+// afterPartition builds the query for the rows of the partitions following the position in token order
+func afterPartition(
+	table *gocql.TableMetadata,
+	whereClause []types.ConditionItem,
+	position []cursorValue,
+	limit int,
+) *db.SelectInfo {
+	tuple := types.ColumnTuple{Token: true}
+	for i, column := range table.PartitionKey {
+		tuple.Columns = append(tuple.Columns, column.Name)
+		tuple.Values = append(tuple.Values, position[i])
+	}
+
+	return &db.SelectInfo{
+		Keyspace: table.Keyspace,
+		Table:    table.Name,
+		Where: append(append([]types.ConditionItem{}, whereClause...), types.ConditionItem{
+			Column:   strings.Join(tuple.Columns, ", "),
+			Operator: ">",
+			Value:    tuple,
+		}),
+		Options: &types.QueryOptions{Limit: limit},
+	}
+}
+
+func restrictedColumns(whereClause []types.ConditionItem) map[string]bool {
+	restricted := make(map[string]bool, len(whereClause))
+	for _, item := range whereClause {
+		restricted[item.Column] = true
+	}
+	return restricted
+}
+
+func isPartitionRestricted(table *gocql.TableMetadata, whereClause []types.ConditionItem) bool {
+	restricted := restrictedColumns(whereClause)
+	for _, column := range table.PartitionKey {
+		if !restricted[column.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+func connectionResult(
+	table *gocql.TableMetadata,
+	ksSchema *KeyspaceGraphQLSchema,
+	rows []map[string]interface{},
+	first int,
+	access *config.ColumnAccess,
+) (map[string]interface{}, error) {
+	hasNextPage := len(rows) > first
+	if hasNextPage {
+		rows = rows[:first]
+	}
+
+	nodes := ksSchema.adaptResult(table.Name, access.ApplyToRows(rows))
+	edges := make([]map[string]interface{}, 0, len(rows))
+	for i, row := range rows {
+		cursor, err := encodeCursor(table, row)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, map[string]interface{}{"cursor": cursor, "node": nodes[i]})
+	}
+
+	pageInfo := map[string]interface{}{"hasNextPage": hasNextPage, "hasPreviousPage": false}
+	if len(edges) > 0 {
+		pageInfo["startCursor"] = edges[0]["cursor"]
+		pageInfo["endCursor"] = edges[len(edges)-1]["cursor"]
+	}
+
+	return map[string]interface{}{"edges": edges, "pageInfo": pageInfo}, nil
+}
+
+// encodeCursor serializes the primary key values of the row, the cursor is independent of the server and the driver
+// paging state so it remains valid across restarts and topology changes
+func encodeCursor(table *gocql.TableMetadata, row map[string]interface{}) (string, error) {
+	var buffer []byte
+	length := make([]byte, binary.MaxVarintLen64)
+	for _, column := range primaryKeyColumns(table) {
+		value, err := gocql.Marshal(withProtoVersion(column.Type), row[column.Name])
+		if err != nil {
+			return "", err
+		}
+		n := binary.PutUvarint(length, uint64(len(value)))
+		buffer = append(append(buffer, length[:n]...), value...)
+	}
+	return base64.StdEncoding.EncodeToString(buffer), nil
+}
+
+func decodeCursor(table *gocql.TableMetadata, cursor string) ([]cursorValue, error) {
+	buffer, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	columns := primaryKeyColumns(table)
+	values := make([]cursorValue, 0, len(columns))
+	for range columns {
+		length, n := binary.Uvarint(buffer)
+		if n <= 0 || uint64(len(buffer)-n) < length {
+			return nil, errInvalidCursor
+		}
+		values = append(values, buffer[n:n+int(length)])
+		buffer = buffer[n+int(length):]
+	}
+
+	if len(buffer) > 0 {
+		return nil, errInvalidCursor
+	}
+	return values, nil
+}
+
+func primaryKeyColumns(table *gocql.TableMetadata) []*gocql.ColumnMetadata {
+	return append(append([]*gocql.ColumnMetadata{}, table.PartitionKey...), table.ClusteringColumns...)
+}
+
+// withProtoVersion returns a copy of the type information containing the protocol version, as the schema metadata
+// types don't include it
+func withProtoVersion(info gocql.TypeInfo) gocql.TypeInfo {
+	native := gocql.NewNativeType(cursorProtoVersion, info.Type(), info.Custom())
+	switch t := info.(type) {
+	case gocql.CollectionType:
+		collection := gocql.CollectionType{NativeType: native, Elem: withProtoVersion(t.Elem)}
+		if t.Key != nil {
+			collection.Key = withProtoVersion(t.Key)
+		}
+		return collection
+	case gocql.TupleTypeInfo:
+		tuple := gocql.TupleTypeInfo{NativeType: native}
+		for _, elem := range t.Elems {
+			tuple.Elems = append(tuple.Elems, withProtoVersion(elem))
+		}
+		return tuple
+	case gocql.UDTTypeInfo:
+		udt := gocql.UDTTypeInfo{NativeType: native, KeySpace: t.KeySpace, Name: t.Name}
+		for _, field := range t.Elements {
+			udt.Elements = append(udt.Elements, gocql.UDTField{Name: field.Name, Type: withProtoVersion(field.Type)})
+		}
+		return udt
+	}
+	return native
+}
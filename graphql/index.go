@@ -0,0 +1,60 @@
+package graphql
+
+import (
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/graphql-go/graphql"
+)
+
+var indexTargetEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "IndexTarget",
+	Description: "The part of a collection column to index.",
+	Values: graphql.EnumValueConfigMap{
+		"KEYS":    {Value: db.IndexTargetKeys, Description: "Index the keys of a map."},
+		"VALUES":  {Value: db.IndexTargetValues, Description: "Index the values of a list, set or map."},
+		"ENTRIES": {Value: db.IndexTargetEntries, Description: "Index the entries of a map."},
+		"FULL":    {Value: db.IndexTargetFull, Description: "Index the whole value of a frozen collection."},
+	},
+})
+
+func (sg *SchemaGenerator) createIndex(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &db.CreateIndexInfo{
+		Keyspace:    args["keyspaceName"].(string),
+		Table:       args["tableName"].(string),
+		Column:      args["columnName"].(string),
+		IfNotExists: getBoolArg(args, "ifNotExists"),
+	}
+
+	if value, ok := args["indexName"].(string); ok {
+		info.Name = value
+	}
+	if value, ok := args["target"].(string); ok {
+		info.Target = value
+	}
+	if value, ok := args["indexType"].(string); ok {
+		info.Class = value
+	}
+
+	err = sg.dbClient.CreateIndex(info, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
+
+func (sg *SchemaGenerator) dropIndex(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sg.dbClient.DropIndex(&db.DropIndexInfo{
+		Keyspace: args["keyspaceName"].(string),
+		Name:     args["indexName"].(string),
+		IfExists: getBoolArg(args, "ifExists"),
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
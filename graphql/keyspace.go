@@ -16,9 +16,13 @@ type dataCenterValue struct {
 }
 
 type ksValue struct {
-	Name     string            `json:"name"`
-	DCs      []dataCenterValue `json:"dcs"`
-	keyspace *gocql.KeyspaceMetadata
+	Name          string            `json:"name"`
+	DCs           []dataCenterValue `json:"dcs"`
+	Replicas      *int              `json:"replicas"`
+	DurableWrites bool              `json:"durableWrites"`
+	keyspace      *gocql.KeyspaceMetadata
+	dbClient      *db.Db
+	views         *keyspaceViews
 }
 
 var dataCenterType = graphql.NewObject(graphql.ObjectConfig{
@@ -54,6 +58,13 @@ var keyspaceType = graphql.NewObject(graphql.ObjectConfig{
 		"dcs": &graphql.Field{
 			Type: graphql.NewList(dataCenterType),
 		},
+		"replicas": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The replication factor of keyspaces using SimpleStrategy.",
+		},
+		"durableWrites": &graphql.Field{
+			Type: graphql.Boolean,
+		},
 		"table": &graphql.Field{
 			Type: tableType,
 			Args: graphql.FieldConfigArgument{
@@ -63,14 +74,26 @@ var keyspaceType = graphql.NewObject(graphql.ObjectConfig{
 			},
 			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
 				parent := p.Source.(ksValue)
-				return getTables(parent.keyspace, p.Args)
+				return getTables(parent, p.Args)
 			},
 		},
 		"tables": &graphql.Field{
 			Type: graphql.NewList(tableType),
 			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
 				parent := p.Source.(ksValue)
-				return getTables(parent.keyspace, p.Args)
+				return getTables(parent, p.Args)
+			},
+		},
+		"types": &graphql.Field{
+			Type: graphql.NewList(userType),
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return getUserTypes(p.Source.(ksValue).keyspace), nil
+			},
+		},
+		"views": &graphql.Field{
+			Type: graphql.NewList(viewType),
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return p.Source.(ksValue).views.values("")
 			},
 		},
 	},
@@ -86,7 +109,16 @@ func (sg *SchemaGenerator) BuildKeyspaceSchema(singleKeyspace string, ops config
 
 func (sg *SchemaGenerator) buildKeyspaceValue(keyspace *gocql.KeyspaceMetadata) ksValue {
 	dcs := make([]dataCenterValue, 0)
-	if strings.Contains(keyspace.StrategyClass, "NetworkTopologyStrategy") {
+	var replicas *int
+	if strings.Contains(keyspace.StrategyClass, "SimpleStrategy") {
+		if count, err := strconv.Atoi(fmt.Sprint(keyspace.StrategyOptions["replication_factor"])); err == nil {
+			replicas = &count
+		} else {
+			sg.logger.Error("invalid replication factor for keyspace",
+				"replication_factor", keyspace.StrategyOptions["replication_factor"],
+				"keyspace", keyspace.Name)
+		}
+	} else if strings.Contains(keyspace.StrategyClass, "NetworkTopologyStrategy") {
 		for dc, replicas := range keyspace.StrategyOptions {
 			count, err := strconv.Atoi(replicas.(string))
 			if err != nil {
@@ -102,9 +134,13 @@ func (sg *SchemaGenerator) buildKeyspaceValue(keyspace *gocql.KeyspaceMetadata)
 		}
 	}
 	return ksValue{
-		keyspace.Name,
-		dcs,
-		keyspace,
+		Name:          keyspace.Name,
+		DCs:           dcs,
+		Replicas:      replicas,
+		DurableWrites: keyspace.DurableWrites,
+		keyspace:      keyspace,
+		dbClient:      sg.dbClient,
+		views:         newKeyspaceViews(sg.dbClient, keyspace.Name),
 	}
 }
 
@@ -179,7 +215,15 @@ func (sg *SchemaGenerator) buildKeyspaceMutation(singleKeyspace string, ops conf
 					Type: graphql.NewNonNull(graphql.String),
 				},
 				"dcs": &graphql.ArgumentConfig{
-					Type: graphql.NewNonNull(graphql.NewList(dataCenterInput)),
+					Type:        graphql.NewList(dataCenterInput),
+					Description: "The replicas per data center using NetworkTopologyStrategy.",
+				},
+				"replicas": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The replication factor using SimpleStrategy, used when no data centers are provided.",
+				},
+				"durableWrites": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
 				},
 				"ifNotExists": &graphql.ArgumentConfig{
 					Type: graphql.Boolean,
@@ -188,25 +232,57 @@ func (sg *SchemaGenerator) buildKeyspaceMutation(singleKeyspace string, ops conf
 			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
 				args := params.Args
 				ksName := args["name"].(string)
-				dcs := args["dcs"].([]interface{})
 
-				dcReplicas := make(map[string]int)
-				for _, dc := range dcs {
-					dcReplica := dc.(map[string]interface{})
-					dcReplicas[dcReplica["name"].(string)] = dcReplica["replicas"].(int)
+				userOrRole, err := sg.checkUserOrRoleAuth(params)
+				if err != nil {
+					return nil, err
 				}
 
+				err = sg.dbClient.CreateKeyspace(&db.CreateKeyspaceInfo{
+					Name:              ksName,
+					DCReplicas:        getDCReplicasArg(args),
+					ReplicationFactor: getIntArg(args, "replicas"),
+					DurableWrites:     getBoolPtrArg(args, "durableWrites"),
+					IfNotExists:       getBoolArg(args, "ifNotExists"),
+				}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+				return err == nil, err
+			},
+		}
+	}
+
+	if ops.IsSupported(config.KeyspaceAlter) && singleKeyspace == "" {
+		fields["alterKeyspace"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"name": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"dcs": &graphql.ArgumentConfig{
+					Type: graphql.NewList(dataCenterInput),
+				},
+				"replicas": &graphql.ArgumentConfig{
+					Type: graphql.Int,
+				},
+				"durableWrites": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				args := params.Args
+				ksName := args["name"].(string)
+
 				userOrRole, err := sg.checkUserOrRoleAuth(params)
 				if err != nil {
 					return nil, err
 				}
 
-				err = sg.dbClient.CreateKeyspace(&db.CreateKeyspaceInfo{
-					Name:        ksName,
-					DCReplicas:  dcReplicas,
-					IfNotExists: getBoolArg(args, "ifNotExists"),
+				err = sg.dbClient.AlterKeyspace(&db.AlterKeyspaceInfo{
+					Name:              ksName,
+					DCReplicas:        getDCReplicasArg(args),
+					ReplicationFactor: getIntArg(args, "replicas"),
+					DurableWrites:     getBoolPtrArg(args, "durableWrites"),
 				}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
-				return err != nil, err
+				return err == nil, err
 			},
 		}
 	}
@@ -260,6 +336,9 @@ func (sg *SchemaGenerator) buildKeyspaceMutation(singleKeyspace string, ops conf
 				"values": &graphql.ArgumentConfig{
 					Type: graphql.NewList(columnInput),
 				},
+				"options": &graphql.ArgumentConfig{
+					Type: tableOptionsInput,
+				},
 				"ifNotExists": &graphql.ArgumentConfig{
 					Type: graphql.Boolean,
 				},
@@ -310,6 +389,26 @@ func (sg *SchemaGenerator) buildKeyspaceMutation(singleKeyspace string, ops conf
 		}
 	}
 
+	if ops.IsSupported(config.TableAlterOptions) {
+		fields["alterTableOptions"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"tableName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"options": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(tableOptionsInput),
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.alterTableOptions)
+			},
+		}
+	}
+
 	if ops.IsSupported(config.TableDrop) {
 		fields["dropTable"] = &graphql.Field{
 			Type: graphql.Boolean,
@@ -330,16 +429,224 @@ func (sg *SchemaGenerator) buildKeyspaceMutation(singleKeyspace string, ops conf
 		}
 	}
 
+	if ops.IsSupported(config.IndexCreate) {
+		fields["createIndex"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"tableName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"columnName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"indexName": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+				"target": &graphql.ArgumentConfig{
+					Type: indexTargetEnum,
+				},
+				"indexType": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The custom index class, e.g. StorageAttachedIndex. A regular secondary index when not set.",
+				},
+				"ifNotExists": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.createIndex)
+			},
+		}
+	}
+
+	if ops.IsSupported(config.IndexDrop) {
+		fields["dropIndex"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"indexName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"ifExists": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.dropIndex)
+			},
+		}
+	}
+
+	if ops.IsSupported(config.ViewCreate) {
+		fields["createView"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"viewName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"tableName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"columns": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The columns of the base table to include in the view, all columns when not set.",
+				},
+				"partitionKeys": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				},
+				"clusteringKeys": &graphql.ArgumentConfig{
+					Type: graphql.NewList(graphql.NewNonNull(viewClusteringKeyInput)),
+				},
+				"where": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(viewConditionInput)),
+					Description: "Additional restrictions on the primary key columns of the base table.",
+				},
+				"ifNotExists": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.createView)
+			},
+		}
+	}
+
+	if ops.IsSupported(config.ViewDrop) {
+		fields["dropView"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"viewName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"ifExists": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.dropView)
+			},
+		}
+	}
+
+	if ops.IsSupported(config.TypeCreate) {
+		fields["createType"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"typeName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"fields": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(columnInput)),
+				},
+				"ifNotExists": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.createType)
+			},
+		}
+	}
+
+	if ops.IsSupported(config.TypeAlterAdd) {
+		fields["alterTypeAdd"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"typeName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"toAdd": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.NewList(columnInput)),
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.alterTypeAdd)
+			},
+		}
+	}
+
+	if ops.IsSupported(config.TypeDrop) {
+		fields["dropType"] = &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"typeName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"ifExists": &graphql.ArgumentConfig{
+					Type: graphql.Boolean,
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.dropType)
+			},
+		}
+	}
+
+	if ops.IsSupported(config.SchemaMigration) {
+		fields["deploySchema"] = &graphql.Field{
+			Type: schemaDeploymentType,
+			Description: "Creates or alters the keyspace, types, tables and indexes from a GraphQL schema annotated " +
+				"with cql directives and serves the schema on the keyspace endpoint.",
+			Args: graphql.FieldConfigArgument{
+				"keyspaceName": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"schema": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"dcs": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(dataCenterInput),
+					Description: "The replicas per data center, required when the keyspace does not exist.",
+				},
+				"replicas": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The replication factor using SimpleStrategy, used when no data centers are provided.",
+				},
+				"allowDestructive": &graphql.ArgumentConfig{
+					Type:        graphql.Boolean,
+					Description: "Allows dropping the tables, types, columns and indexes that are not in the schema.",
+				},
+				"dryRun": &graphql.ArgumentConfig{
+					Type:        graphql.Boolean,
+					Description: "Returns the schema changes without applying them.",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (i interface{}, err error) {
+				return sg.checkKeyspace(singleKeyspace, p, sg.deploySchema)
+			},
+		}
+	}
+
 	return graphql.NewObject(graphql.ObjectConfig{
 		Name:   "Mutation",
-		Fields: fields,
+		Fields: auditedFields(fields),
 	})
 }
 
 func (sg *SchemaGenerator) checkKeyspace(singleKeyspace string, p graphql.ResolveParams,
 	op func(params graphql.ResolveParams) (i interface{}, err error)) (i interface{}, err error) {
 	ksName := p.Args["keyspaceName"].(string)
-	if  sg.isKeyspaceExcludedOrNotSingle(ksName, singleKeyspace){
+	if sg.isKeyspaceExcludedOrNotSingle(ksName, singleKeyspace) {
 		return nil, fmt.Errorf("keyspace does not exist '%s'", ksName)
 	}
 	return op(p)
@@ -355,3 +662,31 @@ func getBoolArg(args map[string]interface{}, name string) bool {
 	}
 	return false
 }
+
+func getBoolPtrArg(args map[string]interface{}, name string) *bool {
+	if value, ok := args[name].(bool); ok {
+		return &value
+	}
+	return nil
+}
+
+func getIntArg(args map[string]interface{}, name string) int {
+	if value, ok := args[name].(int); ok {
+		return value
+	}
+	return 0
+}
+
+func getDCReplicasArg(args map[string]interface{}) map[string]int {
+	dcs, ok := args["dcs"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	dcReplicas := make(map[string]int, len(dcs))
+	for _, dc := range dcs {
+		dcReplica := dc.(map[string]interface{})
+		dcReplicas[dcReplica["name"].(string)] = dcReplica["replicas"].(int)
+	}
+	return dcReplicas
+}
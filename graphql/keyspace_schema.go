@@ -7,6 +7,7 @@ import (
 	"github.com/datastax/cassandra-data-apis/types"
 	"github.com/gocql/gocql"
 	"github.com/graphql-go/graphql"
+	"strings"
 )
 
 type KeyspaceGraphQLSchema struct {
@@ -22,10 +23,24 @@ type KeyspaceGraphQLSchema struct {
 	resultSelectTypes map[string]*graphql.Object
 	// A map containing the result type by table name for a update/insert/delete query
 	resultUpdateTypes map[string]*graphql.Object
+	// A map containing the Relay connection type by table name, only populated when connections are enabled
+	connectionTypes map[string]*graphql.Object
 	// A map containing the order enum by table name
 	orderEnums map[string]*graphql.Enum
+	// A map containing the group by enum by table name, with the primary key columns as values
+	groupByEnums map[string]*graphql.Enum
+	// A map containing the aggregate result type by table name
+	aggregateTypes map[string]*graphql.Object
 	// A map containing key/value types for maps
 	keyValueTypes map[string]graphql.Output
+	// A map containing the filter input types for indexed collections by type name
+	collectionFilterTypes map[string]*graphql.InputObject
+	// A map containing the indexes by table name
+	indexes map[string][]*db.IndexMetadata
+	// A map containing the writetime and ttl selectors by table name and GraphQL field name
+	metadataColumns map[string]map[string]db.SelectColumn
+	// A map containing the writetime and ttl GraphQL field names by table name and selector alias
+	metadataFields map[string]map[string]string
 
 	schemaGen *SchemaGenerator
 	naming    config.NamingConvention
@@ -49,6 +64,10 @@ var inputMutationOptions = graphql.NewInputObject(graphql.InputObjectConfig{
 		"ttl":               {Type: graphql.Int, DefaultValue: -1},
 		"consistency":       {Type: mutationConsistencyEnum, DefaultValue: config.DefaultConsistencyLevel},
 		"serialConsistency": {Type: serialConsistencyEnum, DefaultValue: config.DefaultSerialConsistencyLevel},
+		"timestamp": {
+			Type:        bigint,
+			Description: "The write timestamp in microseconds since epoch, by default the server generates it.",
+		},
 	},
 })
 
@@ -229,7 +248,9 @@ func getTypeName(t graphql.Output) string {
 func (s *KeyspaceGraphQLSchema) BuildTypes(keyspace *gocql.KeyspaceMetadata) error {
 	s.buildOrderEnums(keyspace)
 	s.buildTableTypes(keyspace)
+	s.buildAggregateTypes(keyspace)
 	s.buildResultTypes(keyspace)
+	s.buildConnectionTypes(keyspace)
 	return nil
 }
 
@@ -259,11 +280,18 @@ func (s *KeyspaceGraphQLSchema) buildOrderEnums(keyspace *gocql.KeyspaceMetadata
 
 func (s *KeyspaceGraphQLSchema) buildTableTypes(keyspace *gocql.KeyspaceMetadata) {
 	s.keyValueTypes = make(map[string]graphql.Output)
+	s.collectionFilterTypes = make(map[string]*graphql.InputObject)
 	s.tableValueTypes = make(map[string]*graphql.Object, len(keyspace.Tables))
 	s.tableScalarInputTypes = make(map[string]*graphql.InputObject, len(keyspace.Tables))
 	s.tableOperatorInputTypes = make(map[string]*graphql.InputObject, len(keyspace.Tables))
+	s.metadataColumns = make(map[string]map[string]db.SelectColumn, len(keyspace.Tables))
+	s.metadataFields = make(map[string]map[string]string, len(keyspace.Tables))
 
 	for _, table := range keyspace.Tables {
+		if s.ignoredTables[table.Name] {
+			continue
+		}
+
 		fields := graphql.Fields{}
 		inputFields := graphql.InputObjectConfigFieldMap{}
 		inputOperatorFields := graphql.InputObjectConfigFieldMap{}
@@ -294,8 +322,12 @@ func (s *KeyspaceGraphQLSchema) buildTableTypes(keyspace *gocql.KeyspaceMetadata
 			inputFields[fieldName] = &graphql.InputObjectFieldConfig{Type: inputFieldType}
 
 			t := operatorsInputTypes[column.Type.Type()]
+			if t == nil && s.isIndexed(table.Name, name) {
+				// Indexed collections can be filtered using CONTAINS, CONTAINS KEY and map entries
+				t = s.buildCollectionFilterType(column.Type, inputFieldType)
+			}
 			if t != nil {
-				// Only allow filtering for types that are supported (i.e. lists are not included)
+				// Only allow filtering for types that are supported (i.e. non-indexed lists are not included)
 				inputOperatorFields[fieldName] = &graphql.InputObjectFieldConfig{
 					Type: t,
 				}
@@ -311,6 +343,8 @@ func (s *KeyspaceGraphQLSchema) buildTableTypes(keyspace *gocql.KeyspaceMetadata
 			continue
 		}
 
+		s.buildMetadataFields(table, fields)
+
 		s.tableValueTypes[table.Name] = graphql.NewObject(graphql.ObjectConfig{
 			Description: fmt.Sprintf("Type to populate '%s' table values.", table.Name),
 			Name:        s.naming.ToGraphQLType(table.Name),
@@ -331,6 +365,193 @@ func (s *KeyspaceGraphQLSchema) buildTableTypes(keyspace *gocql.KeyspaceMetadata
 	}
 }
 
+func (s *KeyspaceGraphQLSchema) isIndexed(tableName string, columnName string) bool {
+	for _, index := range s.indexes[tableName] {
+		if index.Column == columnName {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCollectionFilterType returns the filter input type for an indexed list, set or map column or nil when the
+// collection elements are not supported
+func (s *KeyspaceGraphQLSchema) buildCollectionFilterType(
+	typeInfo gocql.TypeInfo,
+	inputType graphql.Output,
+) *graphql.InputObject {
+	list, ok := inputType.(*graphql.List)
+	if !ok {
+		return nil
+	}
+
+	typeName := getTypeName(list.OfType)
+	if typeName == "" {
+		return nil
+	}
+
+	fields := graphql.InputObjectConfigFieldMap{}
+	switch typeInfo.Type() {
+	case gocql.TypeList, gocql.TypeSet:
+		typeName = s.naming.ToGraphQLTypeUnique("List"+typeName, "FilterInput")
+		fields["contains"] = &graphql.InputObjectFieldConfig{Type: list.OfType}
+	case gocql.TypeMap:
+		key, err := s.buildType(typeInfo.(gocql.CollectionType).Key, true)
+		if err != nil {
+			return nil
+		}
+		value, err := s.buildType(typeInfo.(gocql.CollectionType).Elem, true)
+		if err != nil {
+			return nil
+		}
+		typeName = s.naming.ToGraphQLTypeUnique(strings.TrimSuffix(typeName, "MapInput"), "FilterInput")
+		fields["containsKey"] = &graphql.InputObjectFieldConfig{Type: key}
+		fields["contains"] = &graphql.InputObjectFieldConfig{Type: value}
+		fields["containsEntry"] = &graphql.InputObjectFieldConfig{Type: list.OfType}
+	default:
+		return nil
+	}
+
+	t := s.collectionFilterTypes[typeName]
+	if t == nil {
+		t = graphql.NewInputObject(graphql.InputObjectConfig{
+			Description: fmt.Sprintf("Input type to be used in filter queries for indexed %s columns.",
+				typeInfo.Type().String()),
+			Name:   typeName,
+			Fields: fields,
+		})
+		s.collectionFilterTypes[typeName] = t
+	}
+
+	return t
+}
+
+// buildMetadataFields adds the optional write timestamp and time-to-live fields of the non primary key columns
+func (s *KeyspaceGraphQLSchema) buildMetadataFields(table *gocql.TableMetadata, fields graphql.Fields) {
+	metadataColumns := make(map[string]db.SelectColumn)
+	metadataFields := make(map[string]string)
+	for name, column := range table.Columns {
+		if !db.HasCellMetadata(column) {
+			continue
+		}
+
+		fieldName := s.naming.ToGraphQLField(table.Name, name)
+		writetimeField := fieldName + "Writetime"
+		ttlField := fieldName + "Ttl"
+		if fields[writetimeField] != nil || fields[ttlField] != nil {
+			// The name is used by a column
+			continue
+		}
+
+		fields[writetimeField] = &graphql.Field{
+			Description: fmt.Sprintf("The write timestamp of '%s' in microseconds.", name),
+			Type:        bigint,
+		}
+		fields[ttlField] = &graphql.Field{
+			Description: fmt.Sprintf("The remaining time-to-live of '%s' in seconds.", name),
+			Type:        graphql.Int,
+		}
+
+		writetime := db.SelectColumn{Name: name, Function: "WRITETIME", Alias: fmt.Sprintf("writetime(%s)", name)}
+		ttl := db.SelectColumn{Name: name, Function: "TTL", Alias: fmt.Sprintf("ttl(%s)", name)}
+		metadataColumns[writetimeField] = writetime
+		metadataColumns[ttlField] = ttl
+		metadataFields[writetime.Alias] = writetimeField
+		metadataFields[ttl.Alias] = ttlField
+	}
+
+	s.metadataColumns[table.Name] = metadataColumns
+	s.metadataFields[table.Name] = metadataFields
+}
+
+func (s *KeyspaceGraphQLSchema) buildAggregateTypes(keyspace *gocql.KeyspaceMetadata) {
+	s.groupByEnums = make(map[string]*graphql.Enum, len(keyspace.Tables))
+	s.aggregateTypes = make(map[string]*graphql.Object, len(keyspace.Tables))
+
+	for _, table := range keyspace.Tables {
+		if s.ignoredTables[table.Name] {
+			continue
+		}
+
+		fields := graphql.Fields{
+			"count": {Type: bigint},
+		}
+		groupByValues := graphql.EnumValueConfigMap{}
+		values := graphql.Fields{}
+		numericValues := graphql.Fields{}
+
+		for name, column := range table.Columns {
+			fieldName := s.naming.ToGraphQLField(table.Name, name)
+			fieldType, err := s.buildType(column.Type, false)
+			if err != nil {
+				// Table types were already built at this point
+				continue
+			}
+
+			if column.Kind == gocql.ColumnPartitionKey || column.Kind == gocql.ColumnClusteringKey {
+				fields[fieldName] = &graphql.Field{Type: fieldType}
+				groupByValues[fieldName] = &graphql.EnumValueConfig{
+					Value:       column.Name,
+					Description: fmt.Sprintf("Group %s rows by %s.", table.Name, column.Name),
+				}
+			}
+
+			switch column.Type.Type() {
+			case gocql.TypeList, gocql.TypeSet, gocql.TypeMap:
+				// Collections are not supported by aggregate functions
+				continue
+			}
+
+			values[fieldName] = &graphql.Field{Type: fieldType}
+			if isNumericType(column.Type.Type()) {
+				numericValues[fieldName] = &graphql.Field{Type: fieldType}
+			}
+		}
+
+		if len(values) > 0 {
+			valuesType := graphql.NewObject(graphql.ObjectConfig{
+				Description: fmt.Sprintf("Minimum or maximum values of the '%s' table columns.", table.Name),
+				Name:        s.naming.ToGraphQLTypeUnique(table.Name, "AggregateValues"),
+				Fields:      values,
+			})
+			fields["min"] = &graphql.Field{Type: valuesType}
+			fields["max"] = &graphql.Field{Type: valuesType}
+		}
+
+		if len(numericValues) > 0 {
+			numericValuesType := graphql.NewObject(graphql.ObjectConfig{
+				Description: fmt.Sprintf("Sum or average of the '%s' table numeric columns.", table.Name),
+				Name:        s.naming.ToGraphQLTypeUnique(table.Name, "AggregateNumericValues"),
+				Fields:      numericValues,
+			})
+			fields["sum"] = &graphql.Field{Type: numericValuesType}
+			fields["avg"] = &graphql.Field{Type: numericValuesType}
+		}
+
+		s.groupByEnums[table.Name] = graphql.NewEnum(graphql.EnumConfig{
+			Description: fmt.Sprintf("Group by enumeration for the '%s' table primary key columns.", table.Name),
+			Name:        s.naming.ToGraphQLTypeUnique(table.Name, "GroupBy"),
+			Values:      groupByValues,
+		})
+
+		s.aggregateTypes[table.Name] = graphql.NewObject(graphql.ObjectConfig{
+			Description: fmt.Sprintf("Aggregate result type for the '%s' table, "+
+				"containing the group by column values along with the aggregated values.", table.Name),
+			Name:   s.naming.ToGraphQLTypeUnique(table.Name, "Aggregate"),
+			Fields: fields,
+		})
+	}
+}
+
+func isNumericType(t gocql.Type) bool {
+	switch t {
+	case gocql.TypeInt, gocql.TypeTinyInt, gocql.TypeSmallInt, gocql.TypeBigInt, gocql.TypeCounter,
+		gocql.TypeVarint, gocql.TypeFloat, gocql.TypeDouble, gocql.TypeDecimal:
+		return true
+	}
+	return false
+}
+
 func (s *KeyspaceGraphQLSchema) buildResultTypes(keyspace *gocql.KeyspaceMetadata) {
 	s.resultSelectTypes = make(map[string]*graphql.Object, len(keyspace.Tables))
 	s.resultUpdateTypes = make(map[string]*graphql.Object, len(keyspace.Tables))
@@ -352,6 +573,15 @@ func (s *KeyspaceGraphQLSchema) buildResultTypes(keyspace *gocql.KeyspaceMetadat
 			Fields: graphql.Fields{
 				"pageState": {Type: graphql.String},
 				"values":    {Type: graphql.NewList(graphql.NewNonNull(itemType))},
+				"aggregate": {
+					Description: "Aggregated values of the rows matching the query, grouped by the provided " +
+						"primary key columns. The page state and limit do not apply to aggregates.",
+					Type: graphql.NewList(graphql.NewNonNull(s.aggregateTypes[table.Name])),
+					Args: graphql.FieldConfigArgument{
+						"groupBy": {Type: graphql.NewList(graphql.NewNonNull(s.groupByEnums[table.Name]))},
+					},
+					Resolve: s.schemaGen.aggregateFieldResolver(table, s),
+				},
 			},
 		})
 
@@ -375,10 +605,18 @@ func (s *KeyspaceGraphQLSchema) adaptCondition(tableName string, data map[string
 		mapValue := value.(map[string]interface{})
 
 		for operatorName, itemValue := range mapValue {
+			if entry, ok := itemValue.(map[string]interface{}); ok && operatorName == "containsEntry" {
+				itemValue = types.MapEntry{
+					Key:   adaptParameterValue(entry["key"]),
+					Value: adaptParameterValue(entry["value"]),
+				}
+			} else {
+				itemValue = adaptParameterValue(itemValue)
+			}
 			result = append(result, types.ConditionItem{
 				Column:   s.naming.ToCQLColumn(tableName, key),
 				Operator: types.CqlOperators[operatorName],
-				Value:    adaptParameterValue(itemValue),
+				Value:    itemValue,
 			})
 		}
 	}
@@ -386,10 +624,15 @@ func (s *KeyspaceGraphQLSchema) adaptCondition(tableName string, data map[string
 }
 
 func (s *KeyspaceGraphQLSchema) adaptResult(tableName string, values []map[string]interface{}) []map[string]interface{} {
+	metadataFields := s.metadataFields[tableName]
 	result := make([]map[string]interface{}, 0, len(values))
 	for _, item := range values {
 		resultItem := make(map[string]interface{})
 		for k, v := range item {
+			if fieldName, ok := metadataFields[k]; ok {
+				resultItem[fieldName] = adaptResultValue(v)
+				continue
+			}
 			resultItem[s.naming.ToGraphQLField(tableName, k)] = adaptResultValue(v)
 		}
 		result = append(result, resultItem)
@@ -398,6 +641,48 @@ func (s *KeyspaceGraphQLSchema) adaptResult(tableName string, values []map[strin
 	return result
 }
 
+// adaptAggregateResult converts the rows containing the aggregate aliases into the aggregate type representation, with
+// the values of each function nested into a field named after the function
+func (s *KeyspaceGraphQLSchema) adaptAggregateResult(
+	tableName string,
+	aggregates []db.Aggregate,
+	values []map[string]interface{},
+) []map[string]interface{} {
+	aliases := make(map[string]db.Aggregate, len(aggregates))
+	for _, aggregate := range aggregates {
+		aliases[aggregate.Alias] = aggregate
+	}
+
+	result := make([]map[string]interface{}, 0, len(values))
+	for _, item := range values {
+		resultItem := make(map[string]interface{})
+		for k, v := range item {
+			aggregate, ok := aliases[k]
+			if !ok {
+				// Group by column
+				resultItem[s.naming.ToGraphQLField(tableName, k)] = adaptResultValue(v)
+				continue
+			}
+
+			if aggregate.Column == "" {
+				resultItem[aggregate.Alias] = adaptResultValue(v)
+				continue
+			}
+
+			function := strings.ToLower(aggregate.Function)
+			functionValues, _ := resultItem[function].(map[string]interface{})
+			if functionValues == nil {
+				functionValues = make(map[string]interface{})
+				resultItem[function] = functionValues
+			}
+			functionValues[s.naming.ToGraphQLField(tableName, aggregate.Column)] = adaptResultValue(v)
+		}
+		result = append(result, resultItem)
+	}
+
+	return result
+}
+
 func (s *KeyspaceGraphQLSchema) getModificationResult(
 	table *gocql.TableMetadata,
 	inputValues map[string]interface{},
@@ -0,0 +1,184 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/db"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxConcurrentLookups is the maximum amount of queries a loader executes in parallel
+const maxConcurrentLookups = 32
+
+type loaderKeyType int
+
+const loaderContextKey loaderKeyType = iota
+
+// selectLoader is a request-scoped loader of select queries. Resolvers enqueue their queries and return a thunk, the
+// first thunk that is evaluated executes all the pending queries concurrently. As each query is executed individually,
+// partition lookups are routed by the token-aware policy to a replica. Identical queries in the same request are
+// only executed once.
+type selectLoader struct {
+	dbClient *db.Db
+	mutex    sync.Mutex
+	entries  map[string]*loaderEntry
+	pending  []*loaderEntry
+}
+
+type loaderEntry struct {
+	info    *db.SelectInfo
+	options *db.QueryOptions
+	done    chan struct{}
+	result  db.ResultSet
+	err     error
+}
+
+func newSelectLoader(dbClient *db.Db) *selectLoader {
+	return &selectLoader{
+		dbClient: dbClient,
+		entries:  make(map[string]*loaderEntry),
+	}
+}
+
+func withSelectLoader(ctx context.Context, loader *selectLoader) context.Context {
+	return context.WithValue(ctx, loaderContextKey, loader)
+}
+
+func selectLoaderFromContext(ctx context.Context) *selectLoader {
+	if ctx == nil {
+		return nil
+	}
+	loader, _ := ctx.Value(loaderContextKey).(*selectLoader)
+	return loader
+}
+
+// load enqueues the query and returns a function that waits for its result
+func (l *selectLoader) load(info *db.SelectInfo, options *db.QueryOptions) func() (db.ResultSet, error) {
+	key := loaderKey(info, options)
+
+	l.mutex.Lock()
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &loaderEntry{info: info, options: options, done: make(chan struct{})}
+		l.entries[key] = entry
+		l.pending = append(l.pending, entry)
+	}
+	l.mutex.Unlock()
+
+	return func() (db.ResultSet, error) {
+		l.dispatch()
+		<-entry.done
+		return entry.result, entry.err
+	}
+}
+
+// dispatch executes the pending queries concurrently
+func (l *selectLoader) dispatch() {
+	l.mutex.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.mutex.Unlock()
+
+	semaphore := make(chan struct{}, maxConcurrentLookups)
+	for _, entry := range pending {
+		semaphore <- struct{}{}
+		go func(entry *loaderEntry) {
+			defer func() { <-semaphore }()
+			entry.result, entry.err = l.dbClient.Select(entry.info, entry.options)
+			close(entry.done)
+		}(entry)
+	}
+}
+
+// selectLoad executes the query using the request loader, when there's no loader in the context the query is executed
+// immediately
+func (sg *SchemaGenerator) selectLoad(
+	ctx context.Context,
+	info *db.SelectInfo,
+	options *db.QueryOptions,
+) func() (db.ResultSet, error) {
+	if loader := selectLoaderFromContext(ctx); loader != nil {
+		return loader.load(info, options)
+	}
+
+	result, err := sg.dbClient.Select(info, options)
+	return func() (db.ResultSet, error) {
+		return result, err
+	}
+}
+
+// loaderKey identifies a query by all the fields of the select info, the parameters and the execution options
+func loaderKey(info *db.SelectInfo, options *db.QueryOptions) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%q.%q|%#v|", info.Keyspace, info.Table, info.Columns)
+	for _, item := range info.Where {
+		fmt.Fprintf(&builder, "%q %s ", item.Column, item.Operator)
+		writeKeyValue(&builder, reflect.ValueOf(item.Value))
+		builder.WriteString(",")
+	}
+	fmt.Fprintf(&builder, "|%#v|%#v|%#v", info.OrderBy, info.Aggregates, info.GroupBy)
+	if info.Options != nil {
+		fmt.Fprintf(&builder, "|%#v", *info.Options)
+	}
+	fmt.Fprintf(&builder, "|%q|%d|%d|%d|%x",
+		options.UserOrRole, options.Consistency, options.SerialConsistency, options.PageSize, options.PageState)
+	return builder.String()
+}
+
+// writeKeyValue writes a parameter value, dereferencing the pointers including the ones contained in slices, maps
+// and structs, e.g. the items of an IN list or the key and value of a types.MapEntry
+func writeKeyValue(builder *strings.Builder, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Invalid:
+		builder.WriteString("nil")
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			fmt.Fprintf(builder, "(%s)(nil)", value.Type())
+			return
+		}
+		writeKeyValue(builder, value.Elem())
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			// Blobs and UUIDs
+			fmt.Fprintf(builder, "%#v", value.Interface())
+			return
+		}
+		fmt.Fprintf(builder, "%s{", value.Type())
+		for i := 0; i < value.Len(); i++ {
+			writeKeyValue(builder, value.Index(i))
+			builder.WriteString(",")
+		}
+		builder.WriteString("}")
+	case reflect.Map:
+		entries := make([]string, 0, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			var entry strings.Builder
+			writeKeyValue(&entry, iter.Key())
+			entry.WriteString(":")
+			writeKeyValue(&entry, iter.Value())
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		fmt.Fprintf(builder, "%s{%s}", value.Type(), strings.Join(entries, ","))
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if !value.Field(i).CanInterface() {
+				// Values with unexported fields, like time.Time or big.Int, are written as a whole
+				fmt.Fprintf(builder, "%#v", value.Interface())
+				return
+			}
+		}
+		fmt.Fprintf(builder, "%s{", value.Type())
+		for i := 0; i < value.NumField(); i++ {
+			writeKeyValue(builder, value.Field(i))
+			builder.WriteString(",")
+		}
+		builder.WriteString("}")
+	default:
+		fmt.Fprintf(builder, "%#v", value.Interface())
+	}
+}
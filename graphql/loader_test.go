@@ -0,0 +1,47 @@
+package graphql
+
+import (
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLoaderKey(t *testing.T) {
+	a1, a2, b := "a", "a", "b"
+	selectInfo := func(where []types.ConditionItem, aggregates []db.Aggregate, groupBy []string) *db.SelectInfo {
+		return &db.SelectInfo{
+			Keyspace:   "ks1",
+			Table:      "tbl1",
+			Where:      where,
+			Aggregates: aggregates,
+			GroupBy:    groupBy,
+			Options:    &types.QueryOptions{Limit: 10},
+		}
+	}
+	in := func(values ...interface{}) []types.ConditionItem {
+		return []types.ConditionItem{{Column: "c", Operator: "IN", Value: values}}
+	}
+	options := db.NewQueryOptions()
+
+	// Pointers are dereferenced, including the items of IN lists and map entries
+	assert.Equal(t,
+		loaderKey(selectInfo(in(&a1), nil, nil), options),
+		loaderKey(selectInfo(in(&a2), nil, nil), options))
+	assert.NotEqual(t,
+		loaderKey(selectInfo(in(&a1), nil, nil), options),
+		loaderKey(selectInfo(in(&b), nil, nil), options))
+	assert.NotEqual(t,
+		loaderKey(selectInfo([]types.ConditionItem{
+			{Column: "m", Operator: "=", Value: &types.MapEntry{Key: &a1, Value: &a2}}}, nil, nil), options),
+		loaderKey(selectInfo([]types.ConditionItem{
+			{Column: "m", Operator: "=", Value: &types.MapEntry{Key: &a1, Value: &b}}}, nil, nil), options))
+
+	// Aggregates and grouping are part of the key
+	assert.NotEqual(t,
+		loaderKey(selectInfo(nil, []db.Aggregate{{Function: "MIN", Column: "c"}}, nil), options),
+		loaderKey(selectInfo(nil, []db.Aggregate{{Function: "MAX", Column: "c"}}, nil), options))
+	assert.NotEqual(t,
+		loaderKey(selectInfo(nil, nil, []string{"a"}), options),
+		loaderKey(selectInfo(nil, nil, []string{"b"}), options))
+}
@@ -0,0 +1,187 @@
+package graphql
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchellh/mapstructure"
+	"reflect"
+)
+
+// buildRelationships adds the nested fields of the relationships to the table types, relationships referencing tables
+// or columns that don't exist are ignored
+func (s *KeyspaceGraphQLSchema) buildRelationships(
+	keyspace *gocql.KeyspaceMetadata,
+	relationships []*config.Relationship,
+) {
+	for _, relationship := range relationships {
+		if err := s.buildRelationship(keyspace, relationship); err != nil {
+			s.schemaGen.logger.Error("ignoring relationship",
+				"keyspace", keyspace.Name,
+				"table", relationship.Table,
+				"targetTable", relationship.TargetTable,
+				"error", err)
+		}
+	}
+}
+
+func (s *KeyspaceGraphQLSchema) buildRelationship(
+	keyspace *gocql.KeyspaceMetadata,
+	relationship *config.Relationship,
+) error {
+	table, target := keyspace.Tables[relationship.Table], keyspace.Tables[relationship.TargetTable]
+	if table == nil || s.ignoredTables[table.Name] {
+		return fmt.Errorf("table '%s' not found", relationship.Table)
+	}
+
+	if target == nil || s.ignoredTables[target.Name] {
+		return fmt.Errorf("target table '%s' not found", relationship.TargetTable)
+	}
+
+	if !s.schemaGen.isOperationAllowed(keyspace.Name, target.Name, config.DataRead) {
+		return fmt.Errorf("target table '%s' can not be read", relationship.TargetTable)
+	}
+
+	for _, column := range relationship.Columns {
+		if table.Columns[column] == nil {
+			return fmt.Errorf("column '%s' not found", column)
+		}
+	}
+
+	// Rows are retrieved using primary key lookups
+	primaryKey := append(append([]*gocql.ColumnMetadata{}, target.PartitionKey...), target.ClusteringColumns...)
+	if len(relationship.TargetColumns) < len(target.PartitionKey) || len(relationship.TargetColumns) > len(primaryKey) {
+		return fmt.Errorf("target columns must be a prefix of the primary key containing the partition key")
+	}
+
+	for i, column := range relationship.TargetColumns {
+		if primaryKey[i].Name != column {
+			return fmt.Errorf("target columns must be a prefix of the primary key containing the partition key")
+		}
+	}
+
+	fieldName := relationship.Field
+	if fieldName == "" {
+		fieldName = s.naming.ToGraphQLOperation("", target.Name)
+	}
+
+	tableType := s.tableValueTypes[table.Name]
+	if _, ok := tableType.Fields()[fieldName]; ok {
+		return fmt.Errorf("field '%s' already exists", fieldName)
+	}
+
+	if relationship.OneToOne {
+		tableType.AddFieldConfig(fieldName, &graphql.Field{
+			Description: fmt.Sprintf("The row of '%s' table related to this row.", target.Name),
+			Type:        s.tableValueTypes[target.Name],
+			Resolve:     s.schemaGen.relationshipFieldResolver(relationship, target, s),
+		})
+		return nil
+	}
+
+	tableType.AddFieldConfig(fieldName, &graphql.Field{
+		Description: fmt.Sprintf("The rows of '%s' table related to this row. ", target.Name) +
+			fmt.Sprintf("The amount of values is limited by the page size (defaults to %d).", config.DefaultPageSize),
+		Type: s.resultSelectTypes[target.Name],
+		Args: graphql.FieldConfigArgument{
+			"orderBy": {Type: graphql.NewList(s.orderEnums[target.Name])},
+			"options": {Type: inputQueryOptions, DefaultValue: inputQueryOptionsDefault},
+		},
+		Resolve: s.schemaGen.relationshipFieldResolver(relationship, target, s),
+	})
+	return nil
+}
+
+// relationshipFieldResolver retrieves the related rows using the values of the parent row as primary key conditions
+func (sg *SchemaGenerator) relationshipFieldResolver(
+	relationship *config.Relationship,
+	target *gocql.TableMetadata,
+	ksSchema *KeyspaceGraphQLSchema,
+) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		row, ok := params.Source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+
+		whereClause := make([]types.ConditionItem, 0, len(relationship.Columns))
+		for i, column := range relationship.Columns {
+			value := row[ksSchema.naming.ToGraphQLField(relationship.Table, column)]
+			if isNil(value) {
+				// Rows can not be related using null values
+				return nil, nil
+			}
+
+			whereClause = append(whereClause, types.ConditionItem{
+				Column:   relationship.TargetColumns[i],
+				Operator: "=",
+				Value:    value,
+			})
+		}
+
+		rowAccess, err := sg.rowAccess(params, target)
+		if err != nil {
+			return nil, err
+		}
+		if whereClause, err = rowAccess.Restrict(whereClause); err != nil {
+			return nil, err
+		}
+
+		if relationship.OneToOne {
+			userOrRole, err := sg.checkUserOrRoleAuth(params)
+			if err != nil {
+				return nil, err
+			}
+
+			access := sg.columnAccess(params, target)
+			load := sg.selectLoad(params.Context, &db.SelectInfo{
+				Keyspace: target.Keyspace,
+				Table:    target.Name,
+				Where:    whereClause,
+				Options:  &types.QueryOptions{Limit: 1},
+			}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+
+			return func() (interface{}, error) {
+				result, err := load()
+				if err != nil {
+					return nil, err
+				}
+
+				values := ksSchema.adaptResult(target.Name, access.ApplyToRows(result.Values()))
+				if len(values) == 0 {
+					return nil, nil
+				}
+				return values[0], nil
+			}, nil
+		}
+
+		var orderBy []interface{}
+		var options types.QueryOptions
+		if err := mapstructure.Decode(params.Args["options"], &options); err != nil {
+			return nil, err
+		}
+
+		if params.Args["orderBy"] != nil {
+			orderBy = params.Args["orderBy"].([]interface{})
+		}
+
+		return sg.selectQueryResult(params, target, ksSchema, &db.SelectInfo{
+			Keyspace: target.Keyspace,
+			Table:    target.Name,
+			Where:    whereClause,
+			OrderBy:  parseColumnOrder(orderBy),
+			Options:  &options,
+		})
+	}
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
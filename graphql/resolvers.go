@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/events"
 	"github.com/datastax/cassandra-data-apis/types"
 	"github.com/gocql/gocql"
 	"github.com/graphql-go/graphql"
@@ -23,6 +24,15 @@ const (
 	deleteOperation
 )
 
+// queryResult is the source of the table query result fields
+type queryResult struct {
+	PageState string                   `json:"pageState"`
+	Values    []map[string]interface{} `json:"values"`
+	// The query information, used to resolve the aggregates
+	info    *db.SelectInfo
+	options *db.QueryOptions
+}
+
 func (sg *SchemaGenerator) queryFieldResolver(
 	table *gocql.TableMetadata,
 	ksSchema *KeyspaceGraphQLSchema,
@@ -62,41 +72,176 @@ func (sg *SchemaGenerator) queryFieldResolver(
 			orderBy = params.Args["orderBy"].([]interface{})
 		}
 
-		userOrRole, err := sg.checkUserOrRoleAuth(params)
+		columnOrder := parseColumnOrder(orderBy)
+		columns := conditionColumns(whereClause)
+		for _, order := range columnOrder {
+			columns = append(columns, order.Column)
+		}
+		if err := sg.columnAccess(params, table).CheckUnrestricted(columns...); err != nil {
+			return nil, err
+		}
+
+		rowAccess, err := sg.rowAccess(params, table)
 		if err != nil {
 			return nil, err
 		}
+		if whereClause, err = rowAccess.Restrict(whereClause); err != nil {
+			return nil, err
+		}
+
+		return sg.selectQueryResult(params, table, ksSchema, &db.SelectInfo{
+			Keyspace: table.Keyspace,
+			Table:    table.Name,
+			Where:    whereClause,
+			OrderBy:  columnOrder,
+			Options:  &options,
+		})
+	}
+}
+
+// selectQueryResult executes the select query for the "values" and "pageState" fields of the result type, the
+// aggregates are resolved separately. The query is enqueued in the request loader and a thunk is returned, that way
+// sibling fields are executed concurrently.
+func (sg *SchemaGenerator) selectQueryResult(
+	params graphql.ResolveParams,
+	table *gocql.TableMetadata,
+	ksSchema *KeyspaceGraphQLSchema,
+	info *db.SelectInfo,
+) (interface{}, error) {
+	options := info.Options
+
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	pageState, err := base64.StdEncoding.DecodeString(options.PageState)
+	if err != nil {
+		return nil, err
+	}
+
+	queryOptions := db.NewQueryOptions().
+		WithUserOrRole(userOrRole).
+		WithPageSize(options.PageSize).
+		WithPageState(pageState).
+		WithConsistency(gocql.Consistency(options.Consistency))
 
-		pageState, err := base64.StdEncoding.DecodeString(options.PageState)
+	queryResult := &queryResult{info: info, options: queryOptions}
+
+	fields := selectedFields(params.Info.FieldASTs, params.Info.Fragments)
+	if fields["values"] == nil && fields["pageState"] == nil {
+		// Only aggregates were selected
+		return queryResult, nil
+	}
+
+	var metadataColumns []db.SelectColumn
+	valueFields := selectedFields(fields["values"], params.Info.Fragments)
+	for _, fieldName := range sortedFieldNames(valueFields) {
+		if column, ok := ksSchema.metadataColumns[table.Name][fieldName]; ok {
+			metadataColumns = append(metadataColumns, column)
+		}
+	}
+
+	if len(metadataColumns) > 0 {
+		// Metadata can not be selected along with the wildcard selector
+		info.Columns = append(db.NewSelectColumns(db.TableColumnNames(table)...), metadataColumns...)
+	}
+
+	access := sg.columnAccess(params, table)
+	load := sg.selectLoad(params.Context, info, queryOptions)
+	return func() (interface{}, error) {
+		result, err := load()
 		if err != nil {
 			return nil, err
 		}
 
-		result, err := sg.dbClient.Select(
-			&db.SelectInfo{
-				Keyspace: table.Keyspace,
-				Table:    table.Name,
-				Where:    whereClause,
-				OrderBy:  parseColumnOrder(orderBy),
-				Options:  &options,
-			},
-			db.NewQueryOptions().
-				WithUserOrRole(userOrRole).
-				WithPageSize(options.PageSize).
-				WithPageState(pageState).
-				WithConsistency(gocql.Consistency(options.Consistency)))
+		queryResult.PageState = base64.StdEncoding.EncodeToString(result.PageState())
+		queryResult.Values = ksSchema.adaptResult(table.Name, access.ApplyToRows(result.Values()))
+		return queryResult, nil
+	}, nil
+}
+
+// aggregateFieldResolver executes a query containing the aggregate functions selected in the "aggregate" field, using
+// the conditions of the parent query
+func (sg *SchemaGenerator) aggregateFieldResolver(
+	table *gocql.TableMetadata,
+	ksSchema *KeyspaceGraphQLSchema,
+) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		parent := params.Source.(*queryResult)
+
+		var groupBy []string
+		if params.Args["groupBy"] != nil {
+			for _, value := range params.Args["groupBy"].([]interface{}) {
+				groupBy = append(groupBy, value.(string))
+			}
+		}
+
+		if err := db.ValidateGroupBy(table, groupBy); err != nil {
+			return nil, err
+		}
+
+		var aggregates []db.Aggregate
+		fields := selectedFields(params.Info.FieldASTs, params.Info.Fragments)
+		for _, name := range sortedFieldNames(fields) {
+			if name == "count" {
+				aggregates = append(aggregates, db.Aggregate{Function: "COUNT", Alias: "count"})
+				continue
+			}
+
+			if name != "min" && name != "max" && name != "sum" && name != "avg" {
+				// Group by column
+				continue
+			}
+
+			for _, fieldName := range sortedFieldNames(selectedFields(fields[name], params.Info.Fragments)) {
+				column := ksSchema.naming.ToCQLColumn(table.Name, fieldName)
+				aggregates = append(aggregates, db.Aggregate{
+					Function: strings.ToUpper(name),
+					Column:   column,
+					Alias:    aggregateAlias(name, column),
+				})
+			}
+		}
+
+		aggregateColumns := groupBy
+		for _, aggregate := range aggregates {
+			if aggregate.Column != "" {
+				aggregateColumns = append(aggregateColumns, aggregate.Column)
+			}
+		}
+		if err := sg.columnAccess(params, table).CheckUnrestricted(aggregateColumns...); err != nil {
+			return nil, err
+		}
+
+		if len(aggregates) == 0 {
+			// Only group by columns were selected
+			aggregates = append(aggregates, db.Aggregate{Function: "COUNT", Alias: "count"})
+		}
+
+		result, err := sg.dbClient.Select(&db.SelectInfo{
+			Keyspace:   parent.info.Keyspace,
+			Table:      parent.info.Table,
+			Columns:    db.NewSelectColumns(groupBy...),
+			Where:      parent.info.Where,
+			Aggregates: aggregates,
+			GroupBy:    groupBy,
+		}, db.NewQueryOptions().
+			WithUserOrRole(parent.options.UserOrRole).
+			WithConsistency(parent.options.Consistency))
 
 		if err != nil {
 			return nil, err
 		}
 
-		return &types.QueryResult{
-			PageState: base64.StdEncoding.EncodeToString(result.PageState()),
-			Values:    ksSchema.adaptResult(table.Name, result.Values()),
-		}, nil
+		return ksSchema.adaptAggregateResult(table.Name, aggregates, result.Values()), nil
 	}
 }
 
+func aggregateAlias(function string, column string) string {
+	return fmt.Sprintf("%s(%s)", function, column)
+}
+
 func (sg *SchemaGenerator) mutationFieldResolver(
 	table *gocql.TableMetadata,
 	ksSchema *KeyspaceGraphQLSchema,
@@ -112,8 +257,8 @@ func (sg *SchemaGenerator) mutationFieldResolver(
 			queryParams = append(queryParams, adaptParameterValue(value))
 		}
 
-		var options types.MutationOptions
-		if err := mapstructure.Decode(params.Args["options"], &options); err != nil {
+		options, err := decodeMutationOptions(params.Args["options"])
+		if err != nil {
 			return nil, err
 		}
 
@@ -125,12 +270,38 @@ func (sg *SchemaGenerator) mutationFieldResolver(
 		queryOptions := db.NewQueryOptions().
 			WithUserOrRole(userOrRole).
 			WithConsistency(gocql.Consistency(options.Consistency)).
-			WithSerialConsistency(gocql.SerialConsistency(options.SerialConsistency))
+			WithSerialConsistency(gocql.SerialConsistency(options.SerialConsistency)).
+			WithContext(auditContext(params, table.Keyspace, table.Name))
+
+		var ifCondition []types.ConditionItem
+		if params.Args["ifCondition"] != nil {
+			ifCondition = ksSchema.adaptCondition(table.Name, params.Args["ifCondition"].(map[string]interface{}))
+		}
+
+		conditional := params.Args["ifNotExists"] == true || params.Args["ifExists"] == true || len(ifCondition) > 0
+		if err := db.ValidateTimestamp(options.Timestamp, conditional); err != nil {
+			return nil, err
+		}
+
+		access := sg.columnAccess(params, table)
+		if err := access.CheckUnrestricted(append(columnNames, conditionColumns(ifCondition)...)...); err != nil {
+			return nil, err
+		}
+
+		rowAccess, err := sg.rowAccess(params, table)
+		if err != nil {
+			return nil, err
+		}
+		if err := rowAccess.Check(columnNames, queryParams); err != nil {
+			return nil, err
+		}
 
 		var result db.ResultSet
+		var eventOperation events.Operation
 
 		switch operation {
 		case insertOperation:
+			eventOperation = events.Insert
 			ifNotExists := params.Args["ifNotExists"] == true
 			result, err = sg.dbClient.Insert(&db.InsertInfo{
 				Keyspace:    table.Keyspace,
@@ -139,26 +310,20 @@ func (sg *SchemaGenerator) mutationFieldResolver(
 				QueryParams: queryParams,
 				IfNotExists: ifNotExists,
 				TTL:         options.TTL,
+				Timestamp:   options.Timestamp,
 			}, queryOptions)
 		case deleteOperation:
-			var ifCondition []types.ConditionItem
-			if params.Args["ifCondition"] != nil {
-				ifCondition = ksSchema.adaptCondition(
-					table.Name, params.Args["ifCondition"].(map[string]interface{}))
-			}
+			eventOperation = events.Delete
 			result, err = sg.dbClient.Delete(&db.DeleteInfo{
 				Keyspace:    table.Keyspace,
 				Table:       table.Name,
 				Columns:     columnNames,
 				QueryParams: queryParams,
 				IfCondition: ifCondition,
-				IfExists:    params.Args["ifExists"] == true}, queryOptions)
+				IfExists:    params.Args["ifExists"] == true,
+				Timestamp:   options.Timestamp}, queryOptions)
 		case updateOperation:
-			var ifCondition []types.ConditionItem
-			if params.Args["ifCondition"] != nil {
-				ifCondition = ksSchema.adaptCondition(
-					table.Name, params.Args["ifCondition"].(map[string]interface{}))
-			}
+			eventOperation = events.Update
 			result, err = sg.dbClient.Update(&db.UpdateInfo{
 				Keyspace:    table.Keyspace,
 				Table:       table,
@@ -166,15 +331,35 @@ func (sg *SchemaGenerator) mutationFieldResolver(
 				QueryParams: queryParams,
 				IfCondition: ifCondition,
 				TTL:         options.TTL,
-				IfExists:    params.Args["ifExists"] == true}, queryOptions)
+				IfExists:    params.Args["ifExists"] == true,
+				Timestamp:   options.Timestamp}, queryOptions)
 		default:
 			return false, fmt.Errorf("operation not supported")
 		}
 
-		return ksSchema.getModificationResult(table, value, result, err)
+		modificationResult, err := ksSchema.getModificationResult(table, value, result, err)
+		if err == nil && modificationResult.Applied {
+			sg.broker.Publish(events.NewEvent(table, eventOperation, columnNames, queryParams, userOrRole))
+		}
+		return modificationResult, err
 	}
 }
 
+func decodeMutationOptions(value interface{}) (types.MutationOptions, error) {
+	var options types.MutationOptions
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		// The timestamp is represented as a BigInt string
+		WeaklyTypedInput: true,
+		Result:           &options,
+	})
+	if err != nil {
+		return options, err
+	}
+
+	err = decoder.Decode(value)
+	return options, err
+}
+
 func adaptParameterValue(value interface{}) interface{} {
 	if value == nil {
 		return nil
@@ -0,0 +1,304 @@
+package graphql
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/auth"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/graphql-go/graphql"
+	"strings"
+)
+
+type roleValue struct {
+	Name      string   `json:"name"`
+	Login     bool     `json:"login"`
+	Superuser bool     `json:"superuser"`
+	MemberOf  []string `json:"memberOf"`
+}
+
+type permissionValue struct {
+	Role       string `json:"role"`
+	Resource   string `json:"resource"`
+	Keyspace   string `json:"keyspaceName"`
+	Table      string `json:"tableName"`
+	Permission string `json:"permission"`
+}
+
+var permissionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "PermissionType",
+	Values: graphql.EnumValueConfigMap{
+		"ALL":       {Value: "ALL"},
+		"CREATE":    {Value: "CREATE"},
+		"ALTER":     {Value: "ALTER"},
+		"DROP":      {Value: "DROP"},
+		"SELECT":    {Value: "SELECT"},
+		"MODIFY":    {Value: "MODIFY"},
+		"AUTHORIZE": {Value: "AUTHORIZE"},
+		"DESCRIBE":  {Value: "DESCRIBE"},
+	},
+})
+
+var roleType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Role",
+	Fields: graphql.Fields{
+		"name":      {Type: graphql.NewNonNull(graphql.String)},
+		"login":     {Type: graphql.Boolean},
+		"superuser": {Type: graphql.Boolean},
+		"memberOf":  {Type: graphql.NewList(graphql.String)},
+	},
+})
+
+var permissionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Permission",
+	Description: "A permission of a role, the keyspace and table names are only set for data resources.",
+	Fields: graphql.Fields{
+		"role":         {Type: graphql.NewNonNull(graphql.String)},
+		"resource":     {Type: graphql.NewNonNull(graphql.String)},
+		"keyspaceName": {Type: graphql.String},
+		"tableName":    {Type: graphql.String},
+		"permission":   {Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+// BuildAdminSchema builds the schema used to manage roles and permissions
+func (sg *SchemaGenerator) BuildAdminSchema() (graphql.Schema, error) {
+	return graphql.NewSchema(
+		graphql.SchemaConfig{
+			Query:    sg.buildAdminQuery(),
+			Mutation: sg.buildAdminMutation(),
+		})
+}
+
+func (sg *SchemaGenerator) buildAdminQuery() *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"roles": &graphql.Field{
+				Type: graphql.NewList(roleType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return sg.getRoles(p, "")
+				},
+			},
+			"role": &graphql.Field{
+				Type: roleType,
+				Args: graphql.FieldConfigArgument{
+					"name": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					roles, err := sg.getRoles(p, p.Args["name"].(string))
+					if err != nil || len(roles) == 0 {
+						return nil, err
+					}
+					return roles[0], nil
+				},
+			},
+			"permissions": &graphql.Field{
+				Type: graphql.NewList(permissionType),
+				Args: graphql.FieldConfigArgument{
+					"role": {
+						Type:        graphql.String,
+						Description: "The name of the role, the permissions of all roles are listed when not set.",
+					},
+				},
+				Resolve: sg.getPermissions,
+			},
+		},
+	})
+}
+
+func (sg *SchemaGenerator) buildAdminMutation() *graphql.Object {
+	permissionArgs := graphql.FieldConfigArgument{
+		"role":         {Type: graphql.NewNonNull(graphql.String)},
+		"permission":   {Type: graphql.NewNonNull(permissionEnum)},
+		"keyspaceName": {Type: graphql.String, Description: "The keyspace, all keyspaces when not set."},
+		"tableName":    {Type: graphql.String, Description: "The table, the whole keyspace when not set."},
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: auditedFields(graphql.Fields{
+			"createRole": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: graphql.FieldConfigArgument{
+					"name":        {Type: graphql.NewNonNull(graphql.String)},
+					"password":    {Type: graphql.String},
+					"login":       {Type: graphql.Boolean},
+					"superuser":   {Type: graphql.Boolean},
+					"ifNotExists": {Type: graphql.Boolean},
+				},
+				Resolve: sg.createRole,
+			},
+			"alterRole": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: graphql.FieldConfigArgument{
+					"name":      {Type: graphql.NewNonNull(graphql.String)},
+					"password":  {Type: graphql.String},
+					"login":     {Type: graphql.Boolean},
+					"superuser": {Type: graphql.Boolean},
+				},
+				Resolve: sg.alterRole,
+			},
+			"dropRole": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: graphql.FieldConfigArgument{
+					"name":     {Type: graphql.NewNonNull(graphql.String)},
+					"ifExists": {Type: graphql.Boolean},
+				},
+				Resolve: sg.dropRole,
+			},
+			"grant": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: permissionArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return sg.changePermission(p, sg.dbClient.Grant)
+				},
+			},
+			"revoke": &graphql.Field{
+				Type: graphql.Boolean,
+				Args: permissionArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return sg.changePermission(p, sg.dbClient.Revoke)
+				},
+			},
+		}),
+	})
+}
+
+// checkSuperuser returns the role of the request when it's the configured superuser role
+func (sg *SchemaGenerator) checkSuperuser(params graphql.ResolveParams) (string, error) {
+	value := auth.ContextUserOrRole(params.Context)
+	if sg.superuserRole == "" || value != sg.superuserRole {
+		return "", fmt.Errorf("role management is only allowed for the superuser role")
+	}
+	return value, nil
+}
+
+func (sg *SchemaGenerator) getRoles(params graphql.ResolveParams, name string) ([]*roleValue, error) {
+	userOrRole, err := sg.checkSuperuser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := sg.dbClient.Roles(db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*roleValue, 0, len(roles))
+	for _, role := range roles {
+		if name != "" && role.Name != name {
+			continue
+		}
+		result = append(result, &roleValue{
+			Name:      role.Name,
+			Login:     role.Login,
+			Superuser: role.Superuser,
+			MemberOf:  role.MemberOf,
+		})
+	}
+	return result, nil
+}
+
+func (sg *SchemaGenerator) getPermissions(params graphql.ResolveParams) (interface{}, error) {
+	userOrRole, err := sg.checkSuperuser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	role, _ := params.Args["role"].(string)
+	permissions, err := sg.dbClient.Permissions(
+		role, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*permissionValue, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, &permissionValue{
+			Role:       permission.Role,
+			Resource:   permission.Resource,
+			Keyspace:   permission.Keyspace,
+			Table:      permission.Table,
+			Permission: permission.Permission,
+		})
+	}
+	return result, nil
+}
+
+func (sg *SchemaGenerator) createRole(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkSuperuser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &db.CreateRoleInfo{
+		Name:        args["name"].(string),
+		Login:       getBoolArg(args, "login"),
+		Superuser:   getBoolArg(args, "superuser"),
+		IfNotExists: getBoolArg(args, "ifNotExists"),
+	}
+	if value, ok := args["password"].(string); ok {
+		info.Password = &value
+	}
+
+	err = sg.dbClient.CreateRole(info, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
+
+func (sg *SchemaGenerator) alterRole(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkSuperuser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &db.AlterRoleInfo{
+		Name:      args["name"].(string),
+		Login:     getBoolPtrArg(args, "login"),
+		Superuser: getBoolPtrArg(args, "superuser"),
+	}
+	if value, ok := args["password"].(string); ok {
+		info.Password = &value
+	}
+
+	err = sg.dbClient.AlterRole(info, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
+
+func (sg *SchemaGenerator) dropRole(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkSuperuser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sg.dbClient.DropRole(&db.DropRoleInfo{
+		Name:     args["name"].(string),
+		IfExists: getBoolArg(args, "ifExists"),
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
+
+func (sg *SchemaGenerator) changePermission(
+	params graphql.ResolveParams, change func(*db.PermissionInfo, *db.QueryOptions) error,
+) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkSuperuser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &db.PermissionInfo{
+		Role:       args["role"].(string),
+		Permission: strings.ToUpper(args["permission"].(string)),
+	}
+	if value, ok := args["keyspaceName"].(string); ok {
+		info.Keyspace = value
+	}
+	if value, ok := args["tableName"].(string); ok {
+		info.Table = value
+	}
+
+	err = change(info, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
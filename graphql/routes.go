@@ -51,7 +51,22 @@ func (rg *RouteGenerator) RoutesSchemaManagement(pattern string, singleKeyspace
 	}
 	return routesForSchema(pattern, func(query string, urlPath string, ctx context.Context) *graphql.Result {
 		return rg.executeQuery(query, ctx, schema)
-	}), nil
+	}, nil), nil
+}
+
+// RoutesAdmin returns the routes of the role and permission management schema
+func (rg *RouteGenerator) RoutesAdmin(pattern string, ops config.SchemaOperations) ([]types.Route, error) {
+	if !ops.IsSupported(config.RoleManagement) {
+		return nil, fmt.Errorf("role management operations are not enabled")
+	}
+
+	schema, err := rg.schemaGen.BuildAdminSchema()
+	if err != nil {
+		return nil, fmt.Errorf("unable to build graphql schema for role management: %s", err)
+	}
+	return routesForSchema(pattern, func(query string, urlPath string, ctx context.Context) *graphql.Result {
+		return rg.executeQuery(query, ctx, schema)
+	}, nil), nil
 }
 
 func (rg *RouteGenerator) Routes(pattern string, singleKeyspace string) ([]types.Route, error) {
@@ -68,7 +83,7 @@ func (rg *RouteGenerator) Routes(pattern string, singleKeyspace string) ([]types
 		pattern = rg.routerInfo.UrlPattern().UrlPathFormat(path.Join(pattern, "%s"), "keyspace")
 	}
 
-	return routesForSchema(pattern, func(query string, urlPath string, ctx context.Context) *graphql.Result {
+	lookup := func(urlPath string) (string, *graphql.Schema) {
 		ksName := singleKeyspace
 		if ksName == "" {
 			// Multiple keyspace support
@@ -76,18 +91,21 @@ func (rg *RouteGenerator) Routes(pattern string, singleKeyspace string) ([]types
 			ksName = pathParser(urlPath)
 			if ksName == "" {
 				// Invalid url parameter
-				return nil
+				return "", nil
 			}
 		}
-		schema := updater.Schema(ksName)
+		return ksName, updater.Schema(ksName)
+	}
 
+	return routesForSchema(pattern, func(query string, urlPath string, ctx context.Context) *graphql.Result {
+		_, schema := lookup(urlPath)
 		if schema == nil {
 			// The keyspace was not found or is invalid
 			return nil
 		}
 
 		return rg.executeQuery(query, ctx, *schema)
-	}), nil
+	}, rg.subscriptionHandler(lookup)), nil
 }
 
 // Keyspaces gets a slice of keyspace names that are considered by the route generator.
@@ -123,12 +141,18 @@ func getPathParser(root string) func(string) string {
 	}
 }
 
-func routesForSchema(pattern string, execute executeQueryFunc) []types.Route {
+// routesForSchema returns the GET and POST routes for the schema, GET requests upgrading the connection to WebSocket are
+// served by the subscription handler when provided
+func routesForSchema(pattern string, execute executeQueryFunc, subscribe http.Handler) []types.Route {
 	return []types.Route{
 		{
 			Method:  http.MethodGet,
 			Pattern: pattern,
 			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if subscribe != nil && isWebSocketUpgrade(r) {
+					subscribe.ServeHTTP(w, r)
+					return
+				}
 				result := execute(r.URL.Query().Get("query"), r.URL.Path, r.Context())
 				if result == nil {
 					// The execution function is signaling that it shouldn't be processing this request
@@ -177,7 +201,7 @@ func (rg *RouteGenerator) executeQuery(query string, ctx context.Context, schema
 	result := graphql.Do(graphql.Params{
 		Schema:        schema,
 		RequestString: query,
-		Context:       ctx,
+		Context:       withSelectLoader(ctx, newSelectLoader(rg.dbClient)),
 	})
 	if len(result.Errors) > 0 {
 		rg.logger.Error("unexpected errors processing graphql query", "errors", result.Errors)
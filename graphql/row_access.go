@@ -0,0 +1,14 @@
+package graphql
+
+import (
+	"github.com/datastax/cassandra-data-apis/auth"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/gocql/gocql"
+	"github.com/graphql-go/graphql"
+)
+
+// rowAccess returns the partitions of the table that can be accessed with the claims of the request identity, nil
+// when it's not restricted
+func (sg *SchemaGenerator) rowAccess(params graphql.ResolveParams, table *gocql.TableMetadata) (*config.RowAccess, error) {
+	return sg.rowPolicies.Access(table, auth.ContextClaims(params.Context))
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/datastax/cassandra-data-apis/auth"
 	"github.com/datastax/cassandra-data-apis/config"
 	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/events"
 	"github.com/datastax/cassandra-data-apis/log"
 	"github.com/gocql/gocql"
 	"github.com/graphql-go/graphql"
@@ -17,10 +18,20 @@ const (
 )
 
 type SchemaGenerator struct {
+	// deployments is incremented each time a GraphQL schema is deployed, it's accessed atomically so it's kept as the
+	// first field to guarantee 64-bit alignment
+	deployments       int64
 	dbClient          *db.Db
 	namingFn          config.NamingConventionFn
 	useUserOrRoleAuth bool
+	superuserRole     string
 	ksExcluded        map[string]bool
+	relationships     map[string][]*config.Relationship
+	relayConnections  bool
+	broker            events.Broker
+	columnPolicies    *config.ColumnPolicies
+	rowPolicies       *config.RowPolicies
+	exposure          *config.Exposure
 	logger            log.Logger
 }
 
@@ -32,11 +43,27 @@ func NewSchemaGenerator(dbClient *db.Db, cfg config.Config) *SchemaGenerator {
 	for _, ksName := range cfg.ExcludedKeyspaces() {
 		ksExcluded[ksName] = true
 	}
+	broker := cfg.Broker()
+	if broker == nil {
+		broker = events.NewLocalBroker()
+	}
+	broker = events.WithSinks(broker, cfg.EventSinks(), cfg.Logger())
+	relationships := map[string][]*config.Relationship{}
+	for _, relationship := range cfg.Relationships() {
+		relationships[relationship.Keyspace] = append(relationships[relationship.Keyspace], relationship)
+	}
 	return &SchemaGenerator{
 		dbClient:          dbClient,
 		namingFn:          cfg.Naming(),
 		useUserOrRoleAuth: cfg.UseUserOrRoleAuth(),
+		superuserRole:     cfg.SuperuserRole(),
 		ksExcluded:        ksExcluded,
+		relationships:     relationships,
+		relayConnections:  cfg.RelayConnections(),
+		broker:            broker,
+		columnPolicies:    cfg.ColumnPolicies(),
+		rowPolicies:       cfg.RowPolicies(),
+		exposure:          cfg.Exposure(),
 		logger:            cfg.Logger(),
 	}
 }
@@ -47,7 +74,7 @@ func (sg *SchemaGenerator) buildQueriesFields(
 ) graphql.Fields {
 	fields := graphql.Fields{}
 	for _, table := range keyspace.Tables {
-		if ksSchema.ignoredTables[table.Name] {
+		if ksSchema.ignoredTables[table.Name] || !sg.isOperationAllowed(keyspace.Name, table.Name, config.DataRead) {
 			continue
 		}
 
@@ -80,9 +107,14 @@ func (sg *SchemaGenerator) buildQueriesFields(
 			},
 			Resolve: sg.queryFieldResolver(table, ksSchema, true),
 		}
+
+		if ksSchema.connectionTypes[table.Name] != nil {
+			fields[ksSchema.naming.ToGraphQLOperation("", table.Name)+"Connection"] =
+				sg.buildConnectionField(ksSchema, table)
+		}
 	}
 
-	if len(keyspace.Tables) == 0 {
+	if len(fields) == 0 {
 		// graphql-go requires at least a single query and a single mutation
 		fields["__keyspaceEmptyQuery"] = &graphql.Field{
 			Description: "Placeholder query that is exposed when a keyspace is empty.",
@@ -118,47 +150,54 @@ func (sg *SchemaGenerator) buildMutationFields(
 			continue
 		}
 
-		fields[ksSchema.naming.ToGraphQLOperation(insertPrefix, name)] = &graphql.Field{
-			Description: fmt.Sprintf("Inserts an entire row or upserts data into an existing row of '%s' table. ", table.Name) +
-				"Requires a value for each component of the primary key, but not for any other columns. " +
-				"Missing values are left unset.",
-			Type: ksSchema.resultUpdateTypes[table.Name],
-			Args: graphql.FieldConfigArgument{
-				"value":       {Type: graphql.NewNonNull(ksSchema.tableScalarInputTypes[table.Name])},
-				"ifNotExists": {Type: graphql.Boolean},
-				"options":     {Type: inputMutationOptions, DefaultValue: inputMutationOptionsDefault},
-			},
-			Resolve: sg.mutationFieldResolver(table, ksSchema, insertOperation),
+		ops := sg.exposure.Operations(keyspace.Name, name)
+		if ops.IsSupported(config.DataInsert) {
+			fields[ksSchema.naming.ToGraphQLOperation(insertPrefix, name)] = &graphql.Field{
+				Description: fmt.Sprintf("Inserts an entire row or upserts data into an existing row of '%s' table. ", table.Name) +
+					"Requires a value for each component of the primary key, but not for any other columns. " +
+					"Missing values are left unset.",
+				Type: ksSchema.resultUpdateTypes[table.Name],
+				Args: graphql.FieldConfigArgument{
+					"value":       {Type: graphql.NewNonNull(ksSchema.tableScalarInputTypes[table.Name])},
+					"ifNotExists": {Type: graphql.Boolean},
+					"options":     {Type: inputMutationOptions, DefaultValue: inputMutationOptionsDefault},
+				},
+				Resolve: sg.mutationFieldResolver(table, ksSchema, insertOperation),
+			}
 		}
 
-		fields[ksSchema.naming.ToGraphQLOperation(deletePrefix, name)] = &graphql.Field{
-			Description: fmt.Sprintf("Removes an entire row in '%s' table.", table.Name),
-			Type:        ksSchema.resultUpdateTypes[table.Name],
-			Args: graphql.FieldConfigArgument{
-				"value":       {Type: graphql.NewNonNull(ksSchema.tableScalarInputTypes[table.Name])},
-				"ifExists":    {Type: graphql.Boolean},
-				"ifCondition": {Type: ksSchema.tableOperatorInputTypes[table.Name]},
-				"options":     {Type: inputMutationOptions, DefaultValue: inputMutationOptionsDefault},
-			},
-			Resolve: sg.mutationFieldResolver(table, ksSchema, deleteOperation),
+		if ops.IsSupported(config.DataDelete) {
+			fields[ksSchema.naming.ToGraphQLOperation(deletePrefix, name)] = &graphql.Field{
+				Description: fmt.Sprintf("Removes an entire row in '%s' table.", table.Name),
+				Type:        ksSchema.resultUpdateTypes[table.Name],
+				Args: graphql.FieldConfigArgument{
+					"value":       {Type: graphql.NewNonNull(ksSchema.tableScalarInputTypes[table.Name])},
+					"ifExists":    {Type: graphql.Boolean},
+					"ifCondition": {Type: ksSchema.tableOperatorInputTypes[table.Name]},
+					"options":     {Type: inputMutationOptions, DefaultValue: inputMutationOptionsDefault},
+				},
+				Resolve: sg.mutationFieldResolver(table, ksSchema, deleteOperation),
+			}
 		}
 
-		fields[ksSchema.naming.ToGraphQLOperation(updatePrefix, name)] = &graphql.Field{
-			Description: fmt.Sprintf("Updates one or more column values to a row in '%s' table.", table.Name) +
-				"Like the insert operation, update is an upsert operation: if the specified row does not exist," +
-				"the command creates it.",
-			Type: ksSchema.resultUpdateTypes[table.Name],
-			Args: graphql.FieldConfigArgument{
-				"value":       {Type: graphql.NewNonNull(ksSchema.tableScalarInputTypes[table.Name])},
-				"ifExists":    {Type: graphql.Boolean},
-				"ifCondition": {Type: ksSchema.tableOperatorInputTypes[table.Name]},
-				"options":     {Type: inputMutationOptions, DefaultValue: inputMutationOptionsDefault},
-			},
-			Resolve: sg.mutationFieldResolver(table, ksSchema, updateOperation),
+		if ops.IsSupported(config.DataUpdate) {
+			fields[ksSchema.naming.ToGraphQLOperation(updatePrefix, name)] = &graphql.Field{
+				Description: fmt.Sprintf("Updates one or more column values to a row in '%s' table.", table.Name) +
+					"Like the insert operation, update is an upsert operation: if the specified row does not exist," +
+					"the command creates it.",
+				Type: ksSchema.resultUpdateTypes[table.Name],
+				Args: graphql.FieldConfigArgument{
+					"value":       {Type: graphql.NewNonNull(ksSchema.tableScalarInputTypes[table.Name])},
+					"ifExists":    {Type: graphql.Boolean},
+					"ifCondition": {Type: ksSchema.tableOperatorInputTypes[table.Name]},
+					"options":     {Type: inputMutationOptions, DefaultValue: inputMutationOptionsDefault},
+				},
+				Resolve: sg.mutationFieldResolver(table, ksSchema, updateOperation),
+			}
 		}
 	}
 
-	if len(keyspace.Tables) == 0 {
+	if len(fields) == 0 {
 		// graphql-go requires at least a single query and a single mutation
 		fields["__keyspaceEmptyMutation"] = &graphql.Field{
 			Description: "Placeholder mutation that is exposed when a keyspace is empty.",
@@ -235,25 +274,75 @@ func (sg *SchemaGenerator) buildSchema(keyspaceName string) (graphql.Schema, err
 		return graphql.Schema{}, err
 	}
 
+	indexes, err := sg.dbClient.Indexes(keyspaceName) // Used to allow filtering on indexed collections
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+
 	ksNaming := sg.dbClient.KeyspaceNamingInfo(keyspace)
 	keyspaceSchema := &KeyspaceGraphQLSchema{
 		ignoredTables: make(map[string]bool),
+		indexes:       indexes,
 		schemaGen:     sg,
 		naming:        sg.namingFn(ksNaming),
 	}
 
+	if _, ok := keyspace.Tables[sdlSchemaTable]; ok {
+		doc, err := sg.storedSDL(keyspaceName)
+		if err != nil {
+			return graphql.Schema{}, err
+		}
+
+		if doc != nil {
+			return sg.buildSDLSchema(keyspaceName, doc)
+		}
+
+		keyspaceSchema.ignoredTables[sdlSchemaTable] = true
+	}
+
+	keyspace = sg.exposedKeyspace(keyspaceSchema, keyspace)
+
 	if err := keyspaceSchema.BuildTypes(keyspace); err != nil {
 		return graphql.Schema{}, err
 	}
 
+	keyspaceSchema.buildRelationships(keyspace, sg.relationships[keyspaceName])
+
 	return graphql.NewSchema(
 		graphql.SchemaConfig{
-			Query:    sg.buildQuery(keyspaceSchema, keyspace),
-			Mutation: sg.buildMutation(keyspaceSchema, keyspace, views),
+			Query:        sg.buildQuery(keyspaceSchema, keyspace),
+			Mutation:     sg.buildMutation(keyspaceSchema, keyspace, views),
+			Subscription: sg.buildSubscription(keyspaceSchema, keyspace, views),
 		},
 	)
 }
 
+// exposedKeyspace marks the tables that are not exposed as ignored and returns a copy of the keyspace metadata without
+// the hidden columns of the tables
+func (sg *SchemaGenerator) exposedKeyspace(
+	ksSchema *KeyspaceGraphQLSchema,
+	keyspace *gocql.KeyspaceMetadata,
+) *gocql.KeyspaceMetadata {
+	if sg.exposure == nil {
+		return keyspace
+	}
+
+	result := *keyspace
+	result.Tables = make(map[string]*gocql.TableMetadata, len(keyspace.Tables))
+	for name, table := range keyspace.Tables {
+		if !sg.exposure.TableExposed(keyspace.Name, name) {
+			ksSchema.ignoredTables[name] = true
+		}
+		result.Tables[name] = sg.exposure.Table(table)
+	}
+	return &result
+}
+
+// isOperationAllowed determines whether the data operation is allowed on the table
+func (sg *SchemaGenerator) isOperationAllowed(keyspace string, table string, op config.DataOperations) bool {
+	return sg.exposure.Operations(keyspace, table).IsSupported(op)
+}
+
 func (sg *SchemaGenerator) isKeyspaceExcluded(ksName string) bool {
 	return sg.ksExcluded[ksName]
 }
@@ -0,0 +1,1145 @@
+package graphql
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/migration"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/iancoleman/strcase"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Schema-first mode: a keyspace can be defined with a GraphQL SDL annotated with cql directives. The SDL is
+// translated into DDL, stored in the keyspace and served instead of the schema generated from the tables.
+
+const (
+	sdlSchemaTable = "data_api_graphql_schema"
+	sdlSchemaKey   = "schema"
+)
+
+const (
+	directiveTable  = "cql_table"
+	directiveType   = "cql_type"
+	directiveColumn = "cql_column"
+	directiveIndex  = "cql_index"
+	directiveInsert = "cql_insert"
+	directiveDelete = "cql_delete"
+)
+
+// sdlScalar maps a GraphQL scalar to its graphql-go type and the CQL type of the columns using it
+type sdlScalar struct {
+	graphQLType *graphql.Scalar
+	cqlType     string
+}
+
+var sdlScalars = map[string]sdlScalar{
+	"String":    {graphql.String, "text"},
+	"Int":       {graphql.Int, "int"},
+	"Float":     {graphql.Float, "double"},
+	"Boolean":   {graphql.Boolean, "boolean"},
+	"ID":        {graphql.ID, "uuid"},
+	"Uuid":      {uuid, "uuid"},
+	"TimeUuid":  {timeuuid, "timeuuid"},
+	"Inet":      {ip, "inet"},
+	"BigInt":    {bigint, "bigint"},
+	"Counter":   {counter, "counter"},
+	"Ascii":     {ascii, "ascii"},
+	"Decimal":   {decimal, "decimal"},
+	"Varint":    {varint, "varint"},
+	"Float32":   {float32Scalar, "float"},
+	"Blob":      {blob, "blob"},
+	"Timestamp": {timestamp, "timestamp"},
+	"Time":      {localTime, "time"},
+}
+
+// sdlObject is an object type of the SDL mapped to a table or to a user-defined type
+type sdlObject struct {
+	name       string
+	table      bool
+	definition *ast.ObjectDefinition
+	// columns contains the CQL column name by field name
+	columns map[string]string
+}
+
+type sdlDocument struct {
+	source   string
+	objects  []*sdlObject
+	byName   map[string]*sdlObject
+	enums    map[string]*ast.EnumDefinition
+	inputs   map[string]*ast.InputObjectDefinition
+	query    *ast.ObjectDefinition
+	mutation *ast.ObjectDefinition
+}
+
+// parseSDL parses and validates an annotated SDL document
+func parseSDL(source string) (*sdlDocument, error) {
+	document, err := parser.Parse(parser.ParseParams{Source: source})
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &sdlDocument{
+		source: source,
+		byName: make(map[string]*sdlObject),
+		enums:  make(map[string]*ast.EnumDefinition),
+		inputs: make(map[string]*ast.InputObjectDefinition),
+	}
+
+	for _, definition := range document.Definitions {
+		switch def := definition.(type) {
+		case *ast.ObjectDefinition:
+			switch def.Name.Value {
+			case "Query":
+				doc.query = def
+			case "Mutation":
+				doc.mutation = def
+			default:
+				doc.addObject(def)
+			}
+		case *ast.EnumDefinition:
+			doc.enums[def.Name.Value] = def
+		case *ast.InputObjectDefinition:
+			doc.inputs[def.Name.Value] = def
+		case *ast.DirectiveDefinition:
+			// Directive declarations are allowed but not required
+		case *ast.ScalarDefinition:
+			if _, ok := sdlScalars[def.Name.Value]; !ok {
+				return nil, fmt.Errorf("scalar '%s' is not supported", def.Name.Value)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported definition of kind '%s'", definition.GetKind())
+		}
+	}
+
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// metadata returns the metadata of the table described by the object, it only contains the kind of the columns
+func (o *sdlObject) metadata(keyspace string) *gocql.TableMetadata {
+	table := &gocql.TableMetadata{
+		Keyspace: keyspace,
+		Name:     o.name,
+		Columns:  make(map[string]*gocql.ColumnMetadata, len(o.definition.Fields)),
+	}
+
+	for _, field := range o.definition.Fields {
+		directive := findDirective(field.Directives, directiveColumn)
+		kind := gocql.ColumnRegular
+		if boolArg(directive, "partitionKey") {
+			kind = gocql.ColumnPartitionKey
+		} else if stringArg(directive, "clusteringOrder", "") != "" {
+			kind = gocql.ColumnClusteringKey
+		}
+
+		name := o.columns[field.Name.Value]
+		table.Columns[name] = &gocql.ColumnMetadata{Keyspace: keyspace, Table: o.name, Name: name, Kind: kind}
+	}
+	return table
+}
+
+func (d *sdlDocument) addObject(def *ast.ObjectDefinition) {
+	object := &sdlObject{
+		name:       strcase.ToSnake(def.Name.Value),
+		definition: def,
+		columns:    make(map[string]string, len(def.Fields)),
+	}
+
+	if table := findDirective(def.Directives, directiveTable); table != nil {
+		object.table = true
+		object.name = stringArg(table, "name", object.name)
+	} else if udt := findDirective(def.Directives, directiveType); udt != nil {
+		object.name = stringArg(udt, "name", object.name)
+	}
+
+	for _, field := range def.Fields {
+		object.columns[field.Name.Value] = stringArg(
+			findDirective(field.Directives, directiveColumn), "name", strcase.ToSnake(field.Name.Value))
+	}
+
+	d.objects = append(d.objects, object)
+	d.byName[def.Name.Value] = object
+}
+
+func (d *sdlDocument) validate() error {
+	for _, object := range d.objects {
+		for _, field := range object.definition.Fields {
+			if err := d.validateColumnType(field.Type); err != nil {
+				return fmt.Errorf("field '%s.%s': %s", object.definition.Name.Value, field.Name.Value, err)
+			}
+		}
+	}
+
+	for _, input := range d.inputs {
+		for _, field := range input.Fields {
+			if err := d.validateInputType(field.Type); err != nil {
+				return fmt.Errorf("field '%s.%s': %s", input.Name.Value, field.Name.Value, err)
+			}
+		}
+	}
+
+	if d.query != nil {
+		for _, field := range d.query.Fields {
+			table := d.tableOf(field.Type)
+			if table == nil {
+				return fmt.Errorf("query '%s' must return a type annotated with @%s", field.Name.Value, directiveTable)
+			}
+
+			if err := d.validateArguments(field, table); err != nil {
+				return fmt.Errorf("query '%s': %s", field.Name.Value, err)
+			}
+
+			if err := d.validateQueryColumns(field, table); err != nil {
+				return fmt.Errorf("query '%s': %s", field.Name.Value, err)
+			}
+		}
+	}
+
+	if d.mutation != nil {
+		for _, field := range d.mutation.Fields {
+			if err := d.validateMutation(field); err != nil {
+				return fmt.Errorf("mutation '%s': %s", field.Name.Value, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *sdlDocument) validateColumnType(t ast.Type) error {
+	switch value := t.(type) {
+	case *ast.NonNull:
+		return d.validateColumnType(value.Type)
+	case *ast.List:
+		return d.validateColumnType(value.Type)
+	case *ast.Named:
+		name := value.Name.Value
+		if _, ok := sdlScalars[name]; ok {
+			return nil
+		}
+		if _, ok := d.enums[name]; ok {
+			return nil
+		}
+		if object, ok := d.byName[name]; ok {
+			if object.table {
+				return fmt.Errorf("type '%s' is a table and can not be used as a column type", name)
+			}
+			return nil
+		}
+		return fmt.Errorf("unknown type '%s'", name)
+	}
+	return fmt.Errorf("unsupported type")
+}
+
+func (d *sdlDocument) validateInputType(t ast.Type) error {
+	named := namedType(t)
+	if _, ok := d.inputs[named]; ok {
+		return nil
+	}
+	if _, ok := d.byName[named]; ok {
+		return fmt.Errorf("type '%s' can not be used as an input", named)
+	}
+	return d.validateColumnType(t)
+}
+
+// validateArguments checks that the arguments are fields of the table, arguments that are not fields must be inputs
+// containing fields of the table
+func (d *sdlDocument) validateArguments(field *ast.FieldDefinition, table *sdlObject) error {
+	for _, arg := range field.Arguments {
+		if err := d.validateInputType(arg.Type); err != nil {
+			return fmt.Errorf("argument '%s': %s", arg.Name.Value, err)
+		}
+
+		if _, ok := table.columns[arg.Name.Value]; ok {
+			continue
+		}
+
+		input, ok := d.inputs[namedType(arg.Type)]
+		if !ok {
+			return fmt.Errorf("argument '%s' is not a field of '%s'", arg.Name.Value, table.definition.Name.Value)
+		}
+
+		for _, inputField := range input.Fields {
+			if _, ok := table.columns[inputField.Name.Value]; !ok {
+				return fmt.Errorf("input field '%s' is not a field of '%s'",
+					inputField.Name.Value, table.definition.Name.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// validateQueryColumns checks that the query arguments can be used to select the rows without filtering: either
+// the full partition key followed by a prefix of the clustering key or a single indexed column
+func (d *sdlDocument) validateQueryColumns(field *ast.FieldDefinition, table *sdlObject) error {
+	columns := d.argumentColumns(field, table)
+	if len(columns) == 0 {
+		return nil
+	}
+
+	var partitionKeys, clusteringKeys []string
+	indexed := make(map[string]bool)
+	for _, tableField := range table.definition.Fields {
+		column := table.columns[tableField.Name.Value]
+		directive := findDirective(tableField.Directives, directiveColumn)
+		if boolArg(directive, "partitionKey") {
+			partitionKeys = append(partitionKeys, column)
+		} else if stringArg(directive, "clusteringOrder", "") != "" {
+			clusteringKeys = append(clusteringKeys, column)
+		}
+		if findDirective(tableField.Directives, directiveIndex) != nil {
+			indexed[column] = true
+		}
+	}
+
+	if len(columns) == 1 {
+		for column := range columns {
+			if indexed[column] {
+				return nil
+			}
+		}
+	}
+
+	for _, column := range partitionKeys {
+		required, ok := columns[column]
+		if !ok {
+			return fmt.Errorf("the arguments must contain all the partition key columns or a single indexed column")
+		}
+		if !required {
+			return fmt.Errorf("the argument of partition key column '%s' must be non null", column)
+		}
+		delete(columns, column)
+	}
+
+	for _, column := range clusteringKeys {
+		if _, ok := columns[column]; !ok {
+			break
+		}
+		delete(columns, column)
+	}
+
+	for _, column := range clusteringKeys {
+		if _, ok := columns[column]; ok {
+			return fmt.Errorf("the clustering key columns must be a prefix of '%s'", strings.Join(clusteringKeys, ", "))
+		}
+	}
+
+	if len(columns) > 0 {
+		remaining := make([]string, 0, len(columns))
+		for column := range columns {
+			remaining = append(remaining, column)
+		}
+		sort.Strings(remaining)
+		return fmt.Errorf("column '%s' is not part of the primary key", remaining[0])
+	}
+	return nil
+}
+
+// argumentColumns returns the columns of the arguments, including the fields of input arguments, and whether the
+// value is required
+func (d *sdlDocument) argumentColumns(field *ast.FieldDefinition, table *sdlObject) map[string]bool {
+	columns := make(map[string]bool, len(field.Arguments))
+	for _, arg := range field.Arguments {
+		_, required := arg.Type.(*ast.NonNull)
+		if column, ok := table.columns[arg.Name.Value]; ok {
+			columns[column] = required
+			continue
+		}
+
+		for _, inputField := range d.inputs[namedType(arg.Type)].Fields {
+			_, requiredField := inputField.Type.(*ast.NonNull)
+			columns[table.columns[inputField.Name.Value]] = required && requiredField
+		}
+	}
+	return columns
+}
+
+func (d *sdlDocument) validateMutation(field *ast.FieldDefinition) error {
+	directive := findDirective(field.Directives, directiveInsert)
+	if directive == nil {
+		directive = findDirective(field.Directives, directiveDelete)
+	}
+
+	if directive == nil {
+		return fmt.Errorf("expected @%s or @%s directive", directiveInsert, directiveDelete)
+	}
+
+	table := d.mutationTable(field, directive)
+	if table == nil {
+		return fmt.Errorf("the table type could not be determined, use the 'type' argument of the directive")
+	}
+
+	if named := namedType(field.Type); named != "Boolean" && d.byName[named] != table {
+		return fmt.Errorf("it must return Boolean or '%s'", table.definition.Name.Value)
+	}
+
+	return d.validateArguments(field, table)
+}
+
+// tableOf returns the table of the type, unwrapping lists and non null types
+func (d *sdlDocument) tableOf(t ast.Type) *sdlObject {
+	if object, ok := d.byName[namedType(t)]; ok && object.table {
+		return object
+	}
+	return nil
+}
+
+func (d *sdlDocument) mutationTable(field *ast.FieldDefinition, directive *ast.Directive) *sdlObject {
+	if name := stringArg(directive, "type", ""); name != "" {
+		if object, ok := d.byName[name]; ok && object.table {
+			return object
+		}
+		return nil
+	}
+	return d.tableOf(field.Type)
+}
+
+// keyspace translates the document into the desired state of the keyspace, including the table used to store the SDL
+func (d *sdlDocument) keyspace(name string, dcReplicas map[string]int, replicas int) (*migration.Keyspace, error) {
+	ks := &migration.Keyspace{
+		Name:              name,
+		DataCenters:       dcReplicas,
+		ReplicationFactor: replicas,
+	}
+
+	for _, object := range d.objects {
+		columns, err := d.columns(object)
+		if err != nil {
+			return nil, err
+		}
+
+		if !object.table {
+			ks.Types = append(ks.Types, &migration.UserType{Name: object.name, FieldDefinitions: columns})
+			continue
+		}
+
+		table := &migration.Table{
+			Name:              object.name,
+			ColumnDefinitions: columns,
+			PrimaryKey:        &migration.PrimaryKey{},
+		}
+
+		for _, field := range object.definition.Fields {
+			column := object.columns[field.Name.Value]
+			directive := findDirective(field.Directives, directiveColumn)
+			if boolArg(directive, "partitionKey") {
+				table.PrimaryKey.PartitionKey = append(table.PrimaryKey.PartitionKey, column)
+			} else if order := stringArg(directive, "clusteringOrder", ""); order != "" {
+				table.PrimaryKey.ClusteringKey = append(table.PrimaryKey.ClusteringKey, column)
+				table.ClusteringExpression = append(table.ClusteringExpression,
+					&migration.ClusteringExpression{Column: column, Order: order})
+			}
+
+			if index := findDirective(field.Directives, directiveIndex); index != nil {
+				table.Indexes = append(table.Indexes, &migration.Index{
+					Name:   stringArg(index, "name", fmt.Sprintf("%s_%s_idx", object.name, column)),
+					Column: column,
+					Target: strings.ToUpper(stringArg(index, "target", "")),
+					Class:  stringArg(index, "class", ""),
+				})
+			}
+		}
+
+		ks.Tables = append(ks.Tables, table)
+	}
+
+	ks.Tables = append(ks.Tables, &migration.Table{
+		Name: sdlSchemaTable,
+		ColumnDefinitions: []*migration.Column{
+			{Name: "key", TypeDefinition: "text"},
+			{Name: "schema", TypeDefinition: "text"},
+			{Name: "updated", TypeDefinition: "timestamp"},
+		},
+		PrimaryKey: &migration.PrimaryKey{PartitionKey: []string{"key"}},
+	})
+
+	schema := &migration.Schema{Keyspaces: []*migration.Keyspace{ks}}
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+func (d *sdlDocument) columns(object *sdlObject) ([]*migration.Column, error) {
+	result := make([]*migration.Column, 0, len(object.definition.Fields))
+	for _, field := range object.definition.Fields {
+		directive := findDirective(field.Directives, directiveColumn)
+		typeDefinition := stringArg(directive, "typeHint", "")
+		if typeDefinition == "" {
+			typeDefinition = d.cqlType(field.Type)
+		}
+
+		if !object.table && boolArg(directive, "static") {
+			return nil, fmt.Errorf("field '%s.%s': fields of user-defined types can not be static",
+				object.definition.Name.Value, field.Name.Value)
+		}
+
+		result = append(result, &migration.Column{
+			Name:           object.columns[field.Name.Value],
+			TypeDefinition: typeDefinition,
+			Static:         boolArg(directive, "static"),
+		})
+	}
+	return result, nil
+}
+
+// cqlType returns the CQL type of a validated column type
+func (d *sdlDocument) cqlType(t ast.Type) string {
+	switch value := t.(type) {
+	case *ast.NonNull:
+		return d.cqlType(value.Type)
+	case *ast.List:
+		return fmt.Sprintf("list<%s>", d.cqlType(value.Type))
+	case *ast.Named:
+		if scalar, ok := sdlScalars[value.Name.Value]; ok {
+			return scalar.cqlType
+		}
+		if object, ok := d.byName[value.Name.Value]; ok {
+			return fmt.Sprintf("frozen<%s>", object.name)
+		}
+	}
+	// Enums are stored using their names
+	return "text"
+}
+
+// sdlSchemaBuilder creates the GraphQL types of a document, objects and inputs can reference each other so their
+// fields are resolved lazily
+type sdlSchemaBuilder struct {
+	sg       *SchemaGenerator
+	keyspace string
+	doc      *sdlDocument
+	objects  map[string]*graphql.Object
+	enums    map[string]*graphql.Enum
+	inputs   map[string]*graphql.InputObject
+	// hidden contains the columns of the tables that are not exposed
+	hidden map[*sdlObject][]string
+}
+
+// buildSDLSchema builds the GraphQL schema of a keyspace defined using an SDL document
+func (sg *SchemaGenerator) buildSDLSchema(keyspace string, doc *sdlDocument) (graphql.Schema, error) {
+	b := &sdlSchemaBuilder{
+		sg:       sg,
+		keyspace: keyspace,
+		doc:      doc,
+		objects:  make(map[string]*graphql.Object, len(doc.objects)),
+		enums:    make(map[string]*graphql.Enum, len(doc.enums)),
+		inputs:   make(map[string]*graphql.InputObject, len(doc.inputs)),
+		hidden:   make(map[*sdlObject][]string),
+	}
+
+	for _, object := range doc.objects {
+		if object.table {
+			b.hidden[object] = sg.exposure.HiddenColumns(object.metadata(keyspace))
+		}
+	}
+
+	for name, def := range doc.enums {
+		values := graphql.EnumValueConfigMap{}
+		for _, value := range def.Values {
+			values[value.Name.Value] = &graphql.EnumValueConfig{Value: value.Name.Value}
+		}
+		b.enums[name] = graphql.NewEnum(graphql.EnumConfig{Name: name, Values: values})
+	}
+
+	for _, object := range doc.objects {
+		b.objects[object.definition.Name.Value] = b.buildObject(object)
+	}
+
+	for name, def := range doc.inputs {
+		b.inputs[name] = b.buildInput(def)
+	}
+
+	queryFields := graphql.Fields{}
+	if doc.query != nil {
+		for _, field := range doc.query.Fields {
+			table := doc.tableOf(field.Type)
+			if !b.isExposed(table, config.DataRead) {
+				continue
+			}
+
+			queryFields[field.Name.Value] = &graphql.Field{
+				Type:    b.outputType(field.Type),
+				Args:    b.arguments(field),
+				Resolve: b.queryResolver(field, table),
+			}
+		}
+	}
+
+	if len(queryFields) == 0 {
+		// graphql-go requires at least a single query
+		queryFields["__keyspaceEmptyQuery"] = &graphql.Field{
+			Description: "Placeholder query that is exposed when the schema does not define queries.",
+			Type:        graphql.Boolean,
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				return true, nil
+			},
+		}
+	}
+
+	schemaConfig := graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: queryFields}),
+	}
+
+	if doc.mutation != nil {
+		mutationFields := graphql.Fields{}
+		for _, field := range doc.mutation.Fields {
+			op, directive := config.DataInsert, findDirective(field.Directives, directiveInsert)
+			if directive == nil {
+				op, directive = config.DataDelete, findDirective(field.Directives, directiveDelete)
+			}
+			if !b.isExposed(doc.mutationTable(field, directive), op) {
+				continue
+			}
+
+			mutationFields[field.Name.Value] = &graphql.Field{
+				Type:    b.outputType(field.Type),
+				Args:    b.arguments(field),
+				Resolve: b.mutationResolver(field),
+			}
+		}
+
+		if len(mutationFields) > 0 {
+			schemaConfig.Mutation = graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutationFields})
+		}
+	}
+
+	return graphql.NewSchema(schemaConfig)
+}
+
+// isExposed determines whether the table is exposed and allows the data operation
+func (b *sdlSchemaBuilder) isExposed(table *sdlObject, op config.DataOperations) bool {
+	return b.sg.exposure.TableExposed(b.keyspace, table.name) && b.sg.isOperationAllowed(b.keyspace, table.name, op)
+}
+
+func (b *sdlSchemaBuilder) buildObject(object *sdlObject) *graphql.Object {
+	hidden := make(map[string]bool, len(b.hidden[object]))
+	for _, column := range b.hidden[object] {
+		hidden[column] = true
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: object.definition.Name.Value,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := graphql.Fields{}
+			for _, field := range object.definition.Fields {
+				if hidden[object.columns[field.Name.Value]] {
+					continue
+				}
+
+				_, isEnum := b.enums[namedType(field.Type)]
+				fields[field.Name.Value] = &graphql.Field{
+					Type:    b.outputType(field.Type),
+					Resolve: columnResolver(object.columns[field.Name.Value], isEnum),
+				}
+			}
+			return fields
+		}),
+	})
+}
+
+func (b *sdlSchemaBuilder) buildInput(def *ast.InputObjectDefinition) *graphql.InputObject {
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: def.Name.Value,
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			fields := graphql.InputObjectConfigFieldMap{}
+			for _, field := range def.Fields {
+				fields[field.Name.Value] = &graphql.InputObjectFieldConfig{Type: b.inputType(field.Type)}
+			}
+			return fields
+		}),
+	})
+}
+
+func (b *sdlSchemaBuilder) outputType(t ast.Type) graphql.Output {
+	switch value := t.(type) {
+	case *ast.NonNull:
+		return graphql.NewNonNull(b.outputType(value.Type))
+	case *ast.List:
+		return graphql.NewList(b.outputType(value.Type))
+	}
+
+	name := namedType(t)
+	if object, ok := b.objects[name]; ok {
+		return object
+	}
+	if enum, ok := b.enums[name]; ok {
+		return enum
+	}
+	return sdlScalars[name].graphQLType
+}
+
+func (b *sdlSchemaBuilder) inputType(t ast.Type) graphql.Input {
+	switch value := t.(type) {
+	case *ast.NonNull:
+		return graphql.NewNonNull(b.inputType(value.Type))
+	case *ast.List:
+		return graphql.NewList(b.inputType(value.Type))
+	}
+
+	name := namedType(t)
+	if input, ok := b.inputs[name]; ok {
+		return input
+	}
+	if enum, ok := b.enums[name]; ok {
+		return enum
+	}
+	return sdlScalars[name].graphQLType
+}
+
+func (b *sdlSchemaBuilder) arguments(field *ast.FieldDefinition) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{}
+	for _, arg := range field.Arguments {
+		args[arg.Name.Value] = &graphql.ArgumentConfig{Type: b.inputType(arg.Type)}
+	}
+	return args
+}
+
+// columnValues flattens the arguments, including the fields of input arguments, into column names and values in
+// definition order
+func (b *sdlSchemaBuilder) columnValues(
+	field *ast.FieldDefinition, table *sdlObject, args map[string]interface{},
+) ([]string, []interface{}) {
+	columns := make([]string, 0, len(args))
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range field.Arguments {
+		value, ok := args[arg.Name.Value]
+		if !ok {
+			continue
+		}
+
+		if column, ok := table.columns[arg.Name.Value]; ok {
+			columns = append(columns, column)
+			values = append(values, adaptParameterValue(value))
+			continue
+		}
+
+		input, _ := value.(map[string]interface{})
+		for _, inputField := range b.doc.inputs[namedType(arg.Type)].Fields {
+			if fieldValue, ok := input[inputField.Name.Value]; ok {
+				columns = append(columns, table.columns[inputField.Name.Value])
+				values = append(values, adaptParameterValue(fieldValue))
+			}
+		}
+	}
+	return columns, values
+}
+
+// columnAccess returns the access of the request user or role to the columns of the table, the columns that are not
+// exposed are denied
+func (b *sdlSchemaBuilder) columnAccess(params graphql.ResolveParams, table *sdlObject) (*config.ColumnAccess, error) {
+	var access *config.ColumnAccess
+	if b.sg.columnPolicies != nil {
+		metadata, err := b.sg.dbClient.Table(b.keyspace, table.name)
+		if err != nil {
+			return nil, err
+		}
+		access = b.sg.columnAccess(params, metadata)
+	}
+	return access.WithDenied(table.name, b.hidden[table]), nil
+}
+
+// rowAccess returns the partitions of the table that can be accessed with the claims of the request identity
+func (b *sdlSchemaBuilder) rowAccess(params graphql.ResolveParams, table *sdlObject) (*config.RowAccess, error) {
+	if b.sg.rowPolicies == nil {
+		return nil, nil
+	}
+
+	metadata, err := b.sg.dbClient.Table(b.keyspace, table.name)
+	if err != nil {
+		return nil, err
+	}
+	return b.sg.rowAccess(params, metadata)
+}
+
+func (b *sdlSchemaBuilder) queryResolver(field *ast.FieldDefinition, table *sdlObject) graphql.FieldResolveFn {
+	_, isList := unwrapNonNull(field.Type).(*ast.List)
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		userOrRole, err := b.sg.checkUserOrRoleAuth(params)
+		if err != nil {
+			return nil, err
+		}
+
+		columns, values := b.columnValues(field, table, params.Args)
+		where := make([]types.ConditionItem, 0, len(columns))
+		for i, column := range columns {
+			if values[i] != nil {
+				where = append(where, types.ConditionItem{Column: column, Operator: "=", Value: values[i]})
+			}
+		}
+
+		access, err := b.columnAccess(params, table)
+		if err != nil {
+			return nil, err
+		}
+		if err := access.CheckUnrestricted(conditionColumns(where)...); err != nil {
+			return nil, err
+		}
+
+		rowAccess, err := b.rowAccess(params, table)
+		if err != nil {
+			return nil, err
+		}
+		if where, err = rowAccess.Restrict(where); err != nil {
+			return nil, err
+		}
+
+		options := &types.QueryOptions{}
+		if !isList {
+			options.Limit = 1
+		}
+
+		result, err := b.sg.dbClient.Select(&db.SelectInfo{
+			Keyspace: b.keyspace,
+			Table:    table.name,
+			Where:    where,
+			Options:  options,
+		}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+		if err != nil {
+			return nil, err
+		}
+
+		rows := access.ApplyToRows(result.Values())
+		if isList {
+			return rows, nil
+		}
+
+		if len(rows) == 0 {
+			return nil, nil
+		}
+		return rows[0], nil
+	}
+}
+
+func (b *sdlSchemaBuilder) mutationResolver(field *ast.FieldDefinition) graphql.FieldResolveFn {
+	insert := findDirective(field.Directives, directiveInsert)
+	directive := insert
+	if directive == nil {
+		directive = findDirective(field.Directives, directiveDelete)
+	}
+	table := b.doc.mutationTable(field, directive)
+	returnsValue := namedType(field.Type) != "Boolean"
+
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		userOrRole, err := b.sg.checkUserOrRoleAuth(params)
+		if err != nil {
+			return nil, err
+		}
+
+		columns, values := b.columnValues(field, table, params.Args)
+		access, err := b.columnAccess(params, table)
+		if err != nil {
+			return nil, err
+		}
+		if err := access.CheckUnrestricted(columns...); err != nil {
+			return nil, err
+		}
+
+		rowAccess, err := b.rowAccess(params, table)
+		if err != nil {
+			return nil, err
+		}
+		if err := rowAccess.Check(columns, values); err != nil {
+			return nil, err
+		}
+
+		queryOptions := db.NewQueryOptions().
+			WithUserOrRole(userOrRole).
+			WithContext(auditContext(params, b.keyspace, table.name))
+		if insert != nil {
+			_, err = b.sg.dbClient.Insert(&db.InsertInfo{
+				Keyspace:    b.keyspace,
+				Table:       table.name,
+				Columns:     columns,
+				QueryParams: values,
+				TTL:         -1,
+			}, queryOptions)
+		} else {
+			_, err = b.sg.dbClient.Delete(&db.DeleteInfo{
+				Keyspace:    b.keyspace,
+				Table:       table.name,
+				Columns:     columns,
+				QueryParams: values,
+			}, queryOptions)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !returnsValue {
+			return true, nil
+		}
+
+		// The value is represented as a row, using the column names
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+		return row, nil
+	}
+}
+
+// columnResolver reads the value of a column from a row or a user-defined type value
+func columnResolver(column string, isEnum bool) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		row, ok := params.Source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+
+		value := row[column]
+		if text, ok := value.(*string); ok && isEnum {
+			if text == nil {
+				return nil, nil
+			}
+			return *text, nil
+		}
+		return adaptResultValue(value), nil
+	}
+}
+
+// checkManagedKeyspace checks that all the tables and types of the keyspace were created by a previous deployment,
+// as the objects that are not part of the deployed schema are dropped
+func (sg *SchemaGenerator) checkManagedKeyspace(keyspace string) error {
+	current, err := sg.dbClient.Keyspace(keyspace)
+	if err != nil {
+		if _, ok := err.(*db.DbObjectNotFound); ok {
+			return nil
+		}
+		return err
+	}
+
+	managed := map[string]bool{sdlSchemaTable: true}
+	if _, ok := current.Tables[sdlSchemaTable]; ok {
+		previous, err := sg.storedSDL(keyspace)
+		if err != nil {
+			return err
+		}
+
+		if previous != nil {
+			for _, object := range previous.objects {
+				managed[object.name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(current.Tables))
+	for name := range current.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !managed[name] {
+			return fmt.Errorf("keyspace '%s' contains table '%s' that was not created by a schema deployment, "+
+				"schemas can only be deployed in new keyspaces or keyspaces managed by schema deployments",
+				keyspace, name)
+		}
+	}
+
+	for _, udt := range db.UserTypesFromKeyspace(current) {
+		if !managed[udt.Name] {
+			return fmt.Errorf("keyspace '%s' contains type '%s' that was not created by a schema deployment, "+
+				"schemas can only be deployed in new keyspaces or keyspaces managed by schema deployments",
+				keyspace, udt.Name)
+		}
+	}
+
+	return nil
+}
+
+// storedSDL returns the SDL document stored in the keyspace or nil when it was not deployed
+func (sg *SchemaGenerator) storedSDL(keyspace string) (*sdlDocument, error) {
+	result, err := sg.dbClient.Select(&db.SelectInfo{
+		Keyspace: keyspace,
+		Table:    sdlSchemaTable,
+		Columns:  db.NewSelectColumns("schema"),
+		Where:    []types.ConditionItem{{Column: "key", Operator: "=", Value: sdlSchemaKey}},
+	}, db.NewQueryOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	rows := result.Values()
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	source, ok := rows[0]["schema"].(*string)
+	if !ok || source == nil {
+		return nil, nil
+	}
+
+	return parseSDL(*source)
+}
+
+func findDirective(directives []*ast.Directive, name string) *ast.Directive {
+	for _, directive := range directives {
+		if directive.Name.Value == name {
+			return directive
+		}
+	}
+	return nil
+}
+
+func directiveArg(directive *ast.Directive, name string) interface{} {
+	if directive == nil {
+		return nil
+	}
+	for _, arg := range directive.Arguments {
+		if arg.Name.Value == name && arg.Value != nil {
+			return arg.Value.GetValue()
+		}
+	}
+	return nil
+}
+
+func stringArg(directive *ast.Directive, name string, defaultValue string) string {
+	if value, ok := directiveArg(directive, name).(string); ok {
+		return value
+	}
+	return defaultValue
+}
+
+func boolArg(directive *ast.Directive, name string) bool {
+	value, _ := directiveArg(directive, name).(bool)
+	return value
+}
+
+func unwrapNonNull(t ast.Type) ast.Type {
+	if nonNull, ok := t.(*ast.NonNull); ok {
+		return nonNull.Type
+	}
+	return t
+}
+
+// namedType returns the name of the type, unwrapping lists and non null types
+func namedType(t ast.Type) string {
+	switch value := t.(type) {
+	case *ast.NonNull:
+		return namedType(value.Type)
+	case *ast.List:
+		return namedType(value.Type)
+	case *ast.Named:
+		return value.Name.Value
+	}
+	return ""
+}
+
+type migrationStatementValue struct {
+	Query       string `json:"query"`
+	Destructive bool   `json:"destructive"`
+}
+
+type schemaDeploymentValue struct {
+	Statements  []migrationStatementValue `json:"statements"`
+	Destructive bool                      `json:"destructive"`
+	Applied     *int                      `json:"applied"`
+}
+
+var migrationStatementType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "MigrationStatement",
+	Fields: graphql.Fields{
+		"query":       {Type: graphql.NewNonNull(graphql.String)},
+		"destructive": {Type: graphql.NewNonNull(graphql.Boolean)},
+	},
+})
+
+var schemaDeploymentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SchemaDeployment",
+	Description: "The schema changes needed to deploy a GraphQL schema.",
+	Fields: graphql.Fields{
+		"statements":  {Type: graphql.NewList(migrationStatementType)},
+		"destructive": {Type: graphql.NewNonNull(graphql.Boolean)},
+		"applied": {
+			Type:        graphql.Int,
+			Description: "The amount of statements executed, empty for dry runs.",
+		},
+	},
+})
+
+func (sg *SchemaGenerator) deploySchema(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	ksName := args["keyspaceName"].(string)
+
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseSDL(args["schema"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %s", err)
+	}
+
+	// Validate that the schema can be served before changing the keyspace
+	if _, err := sg.buildSDLSchema(ksName, doc); err != nil {
+		return nil, fmt.Errorf("invalid schema: %s", err)
+	}
+
+	keyspace, err := doc.keyspace(ksName, getDCReplicasArg(args), getIntArg(args, "replicas"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %s", err)
+	}
+
+	if err := sg.checkManagedKeyspace(ksName); err != nil {
+		return nil, err
+	}
+
+	migrator := migration.NewMigrator(sg.dbClient)
+	plan, err := migrator.Plan(&migration.Schema{Keyspaces: []*migration.Keyspace{keyspace}})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &schemaDeploymentValue{
+		Statements:  make([]migrationStatementValue, 0, len(plan.Statements)),
+		Destructive: plan.Destructive(),
+	}
+	for _, statement := range plan.Statements {
+		result.Statements = append(result.Statements, migrationStatementValue{
+			Query:       statement.Query,
+			Destructive: statement.Destructive,
+		})
+	}
+
+	if getBoolArg(args, "dryRun") {
+		return result, nil
+	}
+
+	applied, err := migrator.Apply(params.Context, plan, &migration.ApplyOptions{
+		AllowDestructive: getBoolArg(args, "allowDestructive"),
+		UserOrRole:       userOrRole,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Applied = &applied
+
+	_, err = sg.dbClient.Insert(&db.InsertInfo{
+		Keyspace:    ksName,
+		Table:       sdlSchemaTable,
+		Columns:     []string{"key", "schema", "updated"},
+		QueryParams: []interface{}{sdlSchemaKey, doc.source, time.Now()},
+		TTL:         -1,
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	if err != nil {
+		return nil, err
+	}
+
+	// The schema might change without DDL changes, e.g. when adding a query
+	atomic.AddInt64(&sg.deployments, 1)
+
+	return result, nil
+}
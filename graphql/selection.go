@@ -0,0 +1,50 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+	"sort"
+	"strings"
+)
+
+// selectedFields returns the fields selected in the provided field nodes by name, including the ones selected in
+// fragments and inline fragments. Introspection fields are excluded.
+func selectedFields(fieldASTs []*ast.Field, fragments map[string]ast.Definition) map[string][]*ast.Field {
+	result := make(map[string][]*ast.Field)
+	for _, field := range fieldASTs {
+		collectFields(field.SelectionSet, fragments, result)
+	}
+	return result
+}
+
+func collectFields(selectionSet *ast.SelectionSet, fragments map[string]ast.Definition, result map[string][]*ast.Field) {
+	if selectionSet == nil {
+		return
+	}
+
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			name := selection.Name.Value
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			result[name] = append(result[name], selection)
+		case *ast.InlineFragment:
+			collectFields(selection.SelectionSet, fragments, result)
+		case *ast.FragmentSpread:
+			if fragment, ok := fragments[selection.Name.Value].(*ast.FragmentDefinition); ok {
+				collectFields(fragment.SelectionSet, fragments, result)
+			}
+		}
+	}
+}
+
+// sortedFieldNames returns the names of the selected fields in a deterministic order
+func sortedFieldNames(fields map[string][]*ast.Field) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
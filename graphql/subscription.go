@@ -0,0 +1,363 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/events"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// graphqlWsProtocol is the WebSocket sub-protocol used for subscriptions
+const graphqlWsProtocol = "graphql-ws"
+
+// Message types of the graphql-ws protocol
+const (
+	gqlConnectionInit      = "connection_init"
+	gqlConnectionAck       = "connection_ack"
+	gqlConnectionError     = "connection_error"
+	gqlConnectionTerminate = "connection_terminate"
+	gqlStart               = "start"
+	gqlStop                = "stop"
+	gqlData                = "data"
+	gqlError               = "error"
+	gqlComplete            = "complete"
+)
+
+// eventRootKey is the key of the event in the root value used to execute subscriptions
+const eventRootKey = "event"
+
+var mutationOperationEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "MutationOperation",
+	Description: "The kind of mutation that produced a change.",
+	Values: graphql.EnumValueConfigMap{
+		"INSERT": {Value: events.Insert},
+		"UPDATE": {Value: events.Update},
+		"DELETE": {Value: events.Delete},
+	},
+})
+
+// buildSubscription builds the subscription type containing a field per table that can be mutated, it returns nil
+// when there are no tables
+func (sg *SchemaGenerator) buildSubscription(
+	ksSchema *KeyspaceGraphQLSchema,
+	keyspace *gocql.KeyspaceMetadata,
+	views map[string]bool,
+) *graphql.Object {
+	fields := graphql.Fields{}
+	for _, table := range keyspace.Tables {
+		if ksSchema.ignoredTables[table.Name] || views[table.Name] ||
+			!sg.isOperationAllowed(keyspace.Name, table.Name, config.DataRead) {
+			continue
+		}
+
+		keyFields := graphql.InputObjectConfigFieldMap{}
+		inputFields := ksSchema.tableScalarInputTypes[table.Name].Fields()
+		for _, column := range table.PartitionKey {
+			fieldName := ksSchema.naming.ToGraphQLField(table.Name, column.Name)
+			keyFields[fieldName] = &graphql.InputObjectFieldConfig{Type: inputFields[fieldName].Type}
+		}
+
+		eventType := graphql.NewObject(graphql.ObjectConfig{
+			Description: fmt.Sprintf("A change of the '%s' table written through the API.", table.Name),
+			Name:        ksSchema.naming.ToGraphQLTypeUnique(table.Name, "Event"),
+			Fields: graphql.Fields{
+				"operation": {Type: graphql.NewNonNull(mutationOperationEnum)},
+				"value":     {Type: graphql.NewNonNull(ksSchema.tableValueTypes[table.Name])},
+			},
+		})
+
+		fields[ksSchema.naming.ToGraphQLOperation("", table.Name)] = &graphql.Field{
+			Description: fmt.Sprintf("Subscribes to the changes of '%s' table written through the API, ", table.Name) +
+				"optionally filtered by partition key. The value contains the columns provided in the mutation.",
+			Type: eventType,
+			Args: graphql.FieldConfigArgument{
+				"value": {Type: graphql.NewInputObject(graphql.InputObjectConfig{
+					Name:   ksSchema.naming.ToGraphQLTypeUnique(table.Name, "PartitionKeyInput"),
+					Fields: keyFields,
+				})},
+			},
+			Resolve: sg.subscriptionFieldResolver(table, ksSchema),
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Subscription",
+		Fields: fields,
+	})
+}
+
+// subscriptionFieldResolver resolves the event contained in the root value when it matches the table, the partition
+// key filter and the row policy. When there's no event, the subscription is being started and the access to the table
+// is verified.
+func (sg *SchemaGenerator) subscriptionFieldResolver(
+	table *gocql.TableMetadata,
+	ksSchema *KeyspaceGraphQLSchema,
+) graphql.FieldResolveFn {
+	return func(params graphql.ResolveParams) (interface{}, error) {
+		root, _ := params.Info.RootValue.(map[string]interface{})
+		event, _ := root[eventRootKey].(*events.Event)
+		rowAccess, err := sg.rowAccess(params, table)
+		if err != nil {
+			return nil, err
+		}
+
+		if event == nil {
+			return nil, sg.checkSubscriptionAccess(params, table)
+		}
+
+		if event.Table != table.Name {
+			return nil, nil
+		}
+
+		access := sg.columnAccess(params, table)
+		if filter, ok := params.Args["value"].(map[string]interface{}); ok {
+			for key, value := range filter {
+				column := ksSchema.naming.ToCQLColumn(table.Name, key)
+				if err := access.CheckUnrestricted(column); err != nil {
+					return nil, err
+				}
+				eventValue, _ := event.Value(column)
+				if !reflect.DeepEqual(adaptParameterValue(value), eventValue) {
+					return nil, nil
+				}
+			}
+		}
+
+		// The event is shared by all the subscriptions, the column access is applied to a copy of its values
+		row := make(map[string]interface{}, len(event.PrimaryKey)+len(event.Values))
+		for _, values := range []map[string]interface{}{event.PrimaryKey, event.Values} {
+			for column, columnValue := range values {
+				row[column] = columnValue
+			}
+		}
+
+		if !rowAccess.Matches(row) {
+			return nil, nil
+		}
+
+		value := make(map[string]interface{}, len(row))
+		for column, columnValue := range access.ApplyToRow(row) {
+			if m, ok := columnValue.(map[interface{}]interface{}); ok {
+				// Maps are represented as a list of key/value pairs
+				columnValue = &m
+			}
+			value[ksSchema.naming.ToGraphQLField(table.Name, column)] = adaptResultValue(columnValue)
+		}
+
+		return map[string]interface{}{"operation": event.Operation, "value": value}, nil
+	}
+}
+
+// checkSubscriptionAccess verifies that the user or role can read the table
+func (sg *SchemaGenerator) checkSubscriptionAccess(params graphql.ResolveParams, table *gocql.TableMetadata) error {
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil || !sg.useUserOrRoleAuth {
+		return err
+	}
+
+	_, err = sg.dbClient.Select(&db.SelectInfo{
+		Keyspace: table.Keyspace,
+		Table:    table.Name,
+		Options:  &types.QueryOptions{Limit: 1},
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err
+}
+
+type operationMessage struct {
+	ID      string                 `json:"id,omitempty"`
+	Type    string                 `json:"type"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+type subscriptionConn struct {
+	rg         *RouteGenerator
+	ws         *websocket.Conn
+	keyspace   string
+	schema     graphql.Schema
+	mutex      sync.Mutex
+	operations map[string]events.Subscription
+}
+
+// subscriptionHandler serves the subscriptions using the graphql-ws protocol over WebSocket
+func (rg *RouteGenerator) subscriptionHandler(lookup func(urlPath string) (string, *graphql.Schema)) http.Handler {
+	return websocket.Server{
+		Handshake: func(cfg *websocket.Config, r *http.Request) error {
+			for _, protocol := range cfg.Protocol {
+				if protocol == graphqlWsProtocol {
+					cfg.Protocol = []string{graphqlWsProtocol}
+					return nil
+				}
+			}
+			return fmt.Errorf("the '%s' sub-protocol is required", graphqlWsProtocol)
+		},
+		Handler: func(ws *websocket.Conn) {
+			keyspace, schema := lookup(ws.Request().URL.Path)
+			if schema == nil {
+				_ = websocket.JSON.Send(ws, operationMessage{
+					Type:    gqlConnectionError,
+					Payload: map[string]interface{}{"message": "keyspace not found"},
+				})
+				return
+			}
+
+			conn := &subscriptionConn{
+				rg:         rg,
+				ws:         ws,
+				keyspace:   keyspace,
+				schema:     *schema,
+				operations: make(map[string]events.Subscription),
+			}
+			conn.serve(ws.Request().Context())
+		},
+	}
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+func (c *subscriptionConn) serve(ctx context.Context) {
+	defer c.stopAll()
+	for {
+		var message operationMessage
+		if err := websocket.JSON.Receive(c.ws, &message); err != nil {
+			return
+		}
+
+		switch message.Type {
+		case gqlConnectionInit:
+			c.send(operationMessage{Type: gqlConnectionAck})
+		case gqlStart:
+			c.start(ctx, message)
+		case gqlStop:
+			c.stop(message.ID)
+		case gqlConnectionTerminate:
+			return
+		default:
+			c.send(operationMessage{
+				ID:      message.ID,
+				Type:    gqlError,
+				Payload: map[string]interface{}{"message": fmt.Sprintf("unsupported message type '%s'", message.Type)},
+			})
+		}
+	}
+}
+
+func (c *subscriptionConn) start(ctx context.Context, message operationMessage) {
+	query, _ := message.Payload["query"].(string)
+	operationName, _ := message.Payload["operationName"].(string)
+	variables, _ := message.Payload["variables"].(map[string]interface{})
+	params := graphql.Params{
+		Schema:         c.schema,
+		RequestString:  query,
+		VariableValues: variables,
+		OperationName:  operationName,
+		Context:        ctx,
+		RootObject:     map[string]interface{}{},
+	}
+
+	// Queries and mutations are executed once, subscriptions are started after verifying the document and the
+	// access to the tables
+	result := graphql.Do(params)
+	if len(result.Errors) > 0 || !isSubscription(query, operationName) {
+		c.send(operationMessage{ID: message.ID, Type: gqlData, Payload: resultPayload(result)})
+		c.send(operationMessage{ID: message.ID, Type: gqlComplete})
+		return
+	}
+
+	subscription := c.rg.schemaGen.broker.Subscribe(c.keyspace)
+	c.mutex.Lock()
+	if previous := c.operations[message.ID]; previous != nil {
+		previous.Close()
+	}
+	c.operations[message.ID] = subscription
+	c.mutex.Unlock()
+
+	go func() {
+		for event := range subscription.Events() {
+			params.RootObject = map[string]interface{}{eventRootKey: event}
+			result := graphql.Do(params)
+			if len(result.Errors) == 0 && isEmptyResult(result) {
+				// The event doesn't match the selected tables or the filters
+				continue
+			}
+			c.send(operationMessage{ID: message.ID, Type: gqlData, Payload: resultPayload(result)})
+		}
+	}()
+}
+
+func (c *subscriptionConn) stop(id string) {
+	c.mutex.Lock()
+	subscription := c.operations[id]
+	delete(c.operations, id)
+	c.mutex.Unlock()
+
+	if subscription != nil {
+		subscription.Close()
+		c.send(operationMessage{ID: id, Type: gqlComplete})
+	}
+}
+
+func (c *subscriptionConn) stopAll() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for id, subscription := range c.operations {
+		subscription.Close()
+		delete(c.operations, id)
+	}
+}
+
+func (c *subscriptionConn) send(message operationMessage) {
+	if err := websocket.JSON.Send(c.ws, message); err != nil {
+		c.rg.logger.Error("unable to send subscription message", "error", err)
+	}
+}
+
+func isSubscription(query string, operationName string) bool {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+
+	for _, definition := range document.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok {
+			if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+				return operation.Operation == ast.OperationTypeSubscription
+			}
+		}
+	}
+	return false
+}
+
+func isEmptyResult(result *graphql.Result) bool {
+	data, _ := result.Data.(map[string]interface{})
+	for _, value := range data {
+		if value != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func resultPayload(result *graphql.Result) map[string]interface{} {
+	payload := map[string]interface{}{"data": result.Data}
+	if len(result.Errors) > 0 {
+		payload["errors"] = result.Errors
+	}
+	return payload
+}
@@ -35,8 +35,11 @@ type clusteringInfo struct {
 }
 
 type tableValue struct {
-	Name    string         `json:"name"`
-	Columns []*columnValue `json:"columns"`
+	Name     string         `json:"name"`
+	Columns  []*columnValue `json:"columns"`
+	keyspace string
+	dbClient *db.Db
+	views    *keyspaceViews
 }
 
 var basicTypeEnum = graphql.NewEnum(graphql.EnumConfig{
@@ -163,10 +166,45 @@ var tableType = graphql.NewObject(graphql.ObjectConfig{
 	Fields: graphql.Fields{
 		"name":    {Type: graphql.NewNonNull(graphql.String)},
 		"columns": {Type: graphql.NewList(columnType)},
+		"views": {
+			Type: graphql.NewList(viewType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				parent := tableSource(p.Source)
+				if parent == nil || parent.views == nil {
+					return nil, nil
+				}
+				return parent.views.values(parent.Name)
+			},
+		},
+		"options": {
+			Type: tableOptionsType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				parent := tableSource(p.Source)
+				if parent == nil || parent.dbClient == nil {
+					return nil, nil
+				}
+				options, err := parent.dbClient.TableOptions(parent.keyspace, parent.Name)
+				if err != nil {
+					return nil, err
+				}
+				return toTableOptionsValue(options), nil
+			},
+		},
 	},
 })
 
-func getTables(keyspace *gocql.KeyspaceMetadata, args map[string]interface{}) (interface{}, error) {
+func tableSource(source interface{}) *tableValue {
+	switch source := source.(type) {
+	case tableValue:
+		return &source
+	case *tableValue:
+		return source
+	}
+	return nil
+}
+
+func getTables(parent ksValue, args map[string]interface{}) (interface{}, error) {
+	keyspace := parent.keyspace
 	if args["name"] != nil {
 		// Filter by name
 		name := args["name"].(string)
@@ -181,8 +219,11 @@ func getTables(keyspace *gocql.KeyspaceMetadata, args map[string]interface{}) (i
 		}
 
 		return tableValue{
-			Name:    table.Name,
-			Columns: columns,
+			Name:     table.Name,
+			Columns:  columns,
+			keyspace: keyspace.Name,
+			dbClient: parent.dbClient,
+			views:    parent.views,
 		}, nil
 	}
 
@@ -194,8 +235,11 @@ func getTables(keyspace *gocql.KeyspaceMetadata, args map[string]interface{}) (i
 		}
 
 		tableValues = append(tableValues, &tableValue{
-			Name:    table.Name,
-			Columns: columns,
+			Name:     table.Name,
+			Columns:  columns,
+			keyspace: keyspace.Name,
+			dbClient: parent.dbClient,
+			views:    parent.views,
 		})
 	}
 	return tableValues, nil
@@ -285,6 +329,11 @@ func (sg *SchemaGenerator) createTable(params graphql.ResolveParams) (interface{
 		return nil, err
 	}
 
+	options, err := decodeTableOptions(args["options"])
+	if err != nil {
+		return nil, err
+	}
+
 	ifNotExists := getBoolArg(args, "ifNotExists")
 
 	err = sg.dbClient.CreateTable(&db.CreateTableInfo{
@@ -293,9 +342,10 @@ func (sg *SchemaGenerator) createTable(params graphql.ResolveParams) (interface{
 		PartitionKeys:  partitionKeys,
 		ClusteringKeys: clusteringKeys,
 		Values:         values,
+		Options:        options,
 		IfNotExists:    ifNotExists,
 	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
-	return err != nil, err
+	return err == nil, err
 }
 
 func (sg *SchemaGenerator) alterTableAdd(params graphql.ResolveParams) (interface{}, error) {
@@ -0,0 +1,158 @@
+package graphql
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/graphql-go/graphql"
+	"sort"
+)
+
+type tableOptionEntryValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type tableOptionsValue struct {
+	DefaultTimeToLive   *int                    `json:"defaultTimeToLive"`
+	GcGraceSeconds      *int                    `json:"gcGraceSeconds"`
+	BloomFilterFpChance *float64                `json:"bloomFilterFpChance"`
+	Comment             *string                 `json:"comment"`
+	Compaction          []tableOptionEntryValue `json:"compaction"`
+	Compression         []tableOptionEntryValue `json:"compression"`
+	Caching             []tableOptionEntryValue `json:"caching"`
+}
+
+var tableOptionEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "TableOptionEntry",
+	Fields: graphql.Fields{
+		"key":   {Type: graphql.NewNonNull(graphql.String)},
+		"value": {Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var tableOptionsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TableOptions",
+	Description: "The properties of a table.",
+	Fields: graphql.Fields{
+		"defaultTimeToLive":   {Type: graphql.Int},
+		"gcGraceSeconds":      {Type: graphql.Int},
+		"bloomFilterFpChance": {Type: graphql.Float},
+		"comment":             {Type: graphql.String},
+		"compaction":          {Type: graphql.NewList(tableOptionEntryType)},
+		"compression":         {Type: graphql.NewList(tableOptionEntryType)},
+		"caching":             {Type: graphql.NewList(tableOptionEntryType)},
+	},
+})
+
+var tableOptionEntryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "TableOptionEntryInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"key":   {Type: graphql.NewNonNull(graphql.String)},
+		"value": {Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+var tableOptionsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "TableOptionsInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"defaultTimeToLive":   {Type: graphql.Int},
+		"gcGraceSeconds":      {Type: graphql.Int},
+		"bloomFilterFpChance": {Type: graphql.Float},
+		"comment":             {Type: graphql.String},
+		"compaction":          {Type: graphql.NewList(tableOptionEntryInput)},
+		"compression":         {Type: graphql.NewList(tableOptionEntryInput)},
+		"caching":             {Type: graphql.NewList(tableOptionEntryInput)},
+	},
+})
+
+func toTableOptionsValue(options *db.TableOptions) *tableOptionsValue {
+	return &tableOptionsValue{
+		DefaultTimeToLive:   options.DefaultTimeToLive,
+		GcGraceSeconds:      options.GcGraceSeconds,
+		BloomFilterFpChance: options.BloomFilterFpChance,
+		Comment:             options.Comment,
+		Compaction:          toTableOptionEntries(options.Compaction),
+		Compression:         toTableOptionEntries(options.Compression),
+		Caching:             toTableOptionEntries(options.Caching),
+	}
+}
+
+// toTableOptionEntries returns the entries of the sub-options map sorted by key
+func toTableOptionEntries(values map[string]string) []tableOptionEntryValue {
+	entries := make([]tableOptionEntryValue, 0, len(values))
+	for key, value := range values {
+		entries = append(entries, tableOptionEntryValue{Key: key, Value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// decodeTableOptions converts the TableOptionsInput argument, returning nil when not provided
+func decodeTableOptions(arg interface{}) (*db.TableOptions, error) {
+	if arg == nil {
+		return nil, nil
+	}
+
+	input, ok := arg.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid table options")
+	}
+
+	options := &db.TableOptions{}
+	if value, ok := input["defaultTimeToLive"].(int); ok {
+		options.DefaultTimeToLive = &value
+	}
+	if value, ok := input["gcGraceSeconds"].(int); ok {
+		options.GcGraceSeconds = &value
+	}
+	if value, ok := input["bloomFilterFpChance"].(float64); ok {
+		options.BloomFilterFpChance = &value
+	}
+	if value, ok := input["comment"].(string); ok {
+		options.Comment = &value
+	}
+	options.Compaction = decodeTableOptionEntries(input["compaction"])
+	options.Compression = decodeTableOptionEntries(input["compression"])
+	options.Caching = decodeTableOptionEntries(input["caching"])
+
+	return options, nil
+}
+
+func decodeTableOptionEntries(arg interface{}) map[string]string {
+	entries, ok := arg.([]interface{})
+	if !ok || len(entries) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry, ok := entry.(map[string]interface{}); ok {
+			key, _ := entry["key"].(string)
+			value, _ := entry["value"].(string)
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func (sg *SchemaGenerator) alterTableOptions(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := decodeTableOptions(args["options"])
+	if err != nil {
+		return nil, err
+	}
+
+	err = sg.dbClient.AlterTableOptions(&db.AlterTableOptionsInfo{
+		Keyspace: args["keyspaceName"].(string),
+		Table:    args["tableName"].(string),
+		Options:  options,
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/datastax/cassandra-data-apis/log"
 	"github.com/graphql-go/graphql"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,6 +19,7 @@ type SchemaUpdater struct {
 	schemaGen      *SchemaGenerator
 	singleKeyspace string
 	schemaVersion  string
+	deployments    int64
 	logger         log.Logger
 }
 
@@ -57,6 +59,7 @@ func NewUpdater(
 			"error", err)
 	}
 	updater.schemaVersion = version
+	updater.deployments = atomic.LoadInt64(&schemaGen.deployments)
 
 	return updater, nil
 }
@@ -90,6 +93,11 @@ func (su *SchemaUpdater) update() {
 		su.schemaVersion = version
 	}
 
+	if deployments := atomic.LoadInt64(&su.schemaGen.deployments); deployments != su.deployments {
+		shouldUpdate = true
+		su.deployments = deployments
+	}
+
 	if shouldUpdate {
 		schemas, err := su.schemaGen.BuildSchemas(su.singleKeyspace)
 		if err != nil {
@@ -28,6 +28,7 @@ func TestSchemaUpdater_Update(t *testing.T) {
 		})).Once()
 
 	sessionMock.AddViews(nil)
+	sessionMock.AddIndexes(nil)
 
 	updater, err := NewUpdater(schemaGen, "store", 10*time.Second, log.NewZapLogger(zap.NewExample()))
 	assert.NoError(t, err, "unable to create updater")
@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/gocql/gocql"
+	"github.com/graphql-go/graphql"
+)
+
+type userTypeFieldValue struct {
+	Name string         `json:"name"`
+	Type *dataTypeValue `json:"type"`
+}
+
+type userTypeValue struct {
+	Name   string                `json:"name"`
+	Fields []*userTypeFieldValue `json:"fields"`
+}
+
+var userTypeFieldType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "UserTypeField",
+	Fields: graphql.Fields{
+		"name": {Type: graphql.NewNonNull(graphql.String)},
+		"type": {Type: graphql.NewNonNull(dataType)},
+	},
+})
+
+var userType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "UserType",
+	Description: "A user-defined type.",
+	Fields: graphql.Fields{
+		"name":   {Type: graphql.NewNonNull(graphql.String)},
+		"fields": {Type: graphql.NewList(userTypeFieldType)},
+	},
+})
+
+func getUserTypes(keyspace *gocql.KeyspaceMetadata) []*userTypeValue {
+	types := db.UserTypesFromKeyspace(keyspace)
+	result := make([]*userTypeValue, 0, len(types))
+	for _, udt := range types {
+		fields := make([]*userTypeFieldValue, 0, len(udt.Fields))
+		for _, field := range udt.Fields {
+			fieldType, err := toColumnType(field.Type)
+			if err != nil {
+				// Nested user-defined types and tuples are listed without sub types
+				fieldType = &dataTypeValue{Basic: field.Type.Type()}
+			}
+			fields = append(fields, &userTypeFieldValue{Name: field.Name, Type: fieldType})
+		}
+		result = append(result, &userTypeValue{Name: udt.Name, Fields: fields})
+	}
+	return result
+}
+
+func (sg *SchemaGenerator) createType(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	fields, err := decodeColumns(args["fields"].([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sg.dbClient.CreateType(&db.CreateTypeInfo{
+		Keyspace:    args["keyspaceName"].(string),
+		Name:        args["typeName"].(string),
+		Fields:      fields,
+		IfNotExists: getBoolArg(args, "ifNotExists"),
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
+
+func (sg *SchemaGenerator) alterTypeAdd(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	toAdd, err := decodeColumns(args["toAdd"].([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sg.dbClient.AlterTypeAdd(&db.AlterTypeAddInfo{
+		Keyspace: args["keyspaceName"].(string),
+		Name:     args["typeName"].(string),
+		ToAdd:    toAdd,
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
+
+func (sg *SchemaGenerator) dropType(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sg.dbClient.DropType(&db.DropTypeInfo{
+		Keyspace: args["keyspaceName"].(string),
+		Name:     args["typeName"].(string),
+		IfExists: getBoolArg(args, "ifExists"),
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
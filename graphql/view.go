@@ -0,0 +1,194 @@
+package graphql
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchellh/mapstructure"
+	"sync"
+)
+
+type viewClusteringKeyValue struct {
+	Name  string `json:"name"`
+	Order string `json:"order"`
+}
+
+type viewValue struct {
+	Name              string                   `json:"name"`
+	TableName         string                   `json:"tableName"`
+	Columns           []string                 `json:"columns"`
+	PartitionKeys     []string                 `json:"partitionKeys"`
+	ClusteringKeys    []viewClusteringKeyValue `json:"clusteringKeys"`
+	Where             string                   `json:"where"`
+	IncludeAllColumns bool                     `json:"includeAllColumns"`
+}
+
+var viewClusteringKeyType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ViewClusteringKey",
+	Fields: graphql.Fields{
+		"name":  {Type: graphql.NewNonNull(graphql.String)},
+		"order": {Type: graphql.String},
+	},
+})
+
+var viewType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "View",
+	Description: "A materialized view of a table.",
+	Fields: graphql.Fields{
+		"name":              {Type: graphql.NewNonNull(graphql.String)},
+		"tableName":         {Type: graphql.NewNonNull(graphql.String)},
+		"columns":           {Type: graphql.NewList(graphql.String)},
+		"partitionKeys":     {Type: graphql.NewList(graphql.String)},
+		"clusteringKeys":    {Type: graphql.NewList(viewClusteringKeyType)},
+		"where":             {Type: graphql.String},
+		"includeAllColumns": {Type: graphql.Boolean},
+	},
+})
+
+var viewClusteringKeyInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ViewClusteringKeyInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":  {Type: graphql.NewNonNull(graphql.String)},
+		"order": {Type: graphql.String},
+	},
+})
+
+type viewConditionValue struct {
+	Column   string `json:"column"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+var viewConditionInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ViewConditionInput",
+	Description: "A restriction on a primary key column of the base table, the operator is one of eq, gt, gte, lt " +
+		"or lte.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"column":   {Type: graphql.NewNonNull(graphql.String)},
+		"operator": {Type: graphql.NewNonNull(graphql.String)},
+		"value":    {Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+// keyspaceViews retrieves the materialized views of a keyspace the first time they are requested
+type keyspaceViews struct {
+	once  sync.Once
+	load  func() ([]*db.ViewMetadata, error)
+	views []*db.ViewMetadata
+	err   error
+}
+
+func newKeyspaceViews(dbClient *db.Db, ksName string) *keyspaceViews {
+	return &keyspaceViews{
+		load: func() ([]*db.ViewMetadata, error) {
+			return dbClient.DescribeViews(ksName)
+		},
+	}
+}
+
+// values returns the views of the keyspace, filtered by base table when tableName is not empty
+func (v *keyspaceViews) values(tableName string) ([]*viewValue, error) {
+	v.once.Do(func() {
+		v.views, v.err = v.load()
+	})
+
+	if v.err != nil {
+		return nil, v.err
+	}
+
+	result := make([]*viewValue, 0, len(v.views))
+	for _, view := range v.views {
+		if tableName != "" && view.BaseTable != tableName {
+			continue
+		}
+
+		clusteringKeys := make([]viewClusteringKeyValue, 0, len(view.ClusteringKeys))
+		for _, key := range view.ClusteringKeys {
+			clusteringKeys = append(clusteringKeys, viewClusteringKeyValue{Name: key.Column, Order: key.Order})
+		}
+
+		result = append(result, &viewValue{
+			Name:              view.Name,
+			TableName:         view.BaseTable,
+			Columns:           view.Columns,
+			PartitionKeys:     view.PartitionKeys,
+			ClusteringKeys:    clusteringKeys,
+			Where:             view.WhereClause,
+			IncludeAllColumns: view.IncludeAllColumns,
+		})
+	}
+
+	return result, nil
+}
+
+func (sg *SchemaGenerator) createView(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &db.CreateViewInfo{
+		Keyspace:    args["keyspaceName"].(string),
+		Name:        args["viewName"].(string),
+		BaseTable:   args["tableName"].(string),
+		IfNotExists: getBoolArg(args, "ifNotExists"),
+	}
+
+	if err := mapstructure.Decode(args["partitionKeys"], &info.PartitionKeys); err != nil {
+		return nil, err
+	}
+
+	if args["columns"] != nil {
+		if err := mapstructure.Decode(args["columns"], &info.Columns); err != nil {
+			return nil, err
+		}
+	}
+
+	if args["clusteringKeys"] != nil {
+		var clusteringKeys []viewClusteringKeyValue
+		if err := mapstructure.Decode(args["clusteringKeys"], &clusteringKeys); err != nil {
+			return nil, err
+		}
+		for _, key := range clusteringKeys {
+			info.ClusteringKeys = append(info.ClusteringKeys, db.ColumnOrder{Column: key.Name, Order: key.Order})
+		}
+	}
+
+	if args["where"] != nil {
+		var conditions []viewConditionValue
+		if err := mapstructure.Decode(args["where"], &conditions); err != nil {
+			return nil, err
+		}
+		for _, condition := range conditions {
+			operator, ok := types.CqlOperators[condition.Operator]
+			if !ok {
+				return nil, fmt.Errorf("operator '%s' not found", condition.Operator)
+			}
+			info.Where = append(info.Where, types.ConditionItem{
+				Column:   condition.Column,
+				Operator: operator,
+				Value:    condition.Value,
+			})
+		}
+	}
+
+	err = sg.dbClient.CreateView(info, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
+
+func (sg *SchemaGenerator) dropView(params graphql.ResolveParams) (interface{}, error) {
+	args := params.Args
+	userOrRole, err := sg.checkUserOrRoleAuth(params)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sg.dbClient.DropView(&db.DropViewInfo{
+		Keyspace: args["keyspaceName"].(string),
+		Name:     args["viewName"].(string),
+		IfExists: getBoolArg(args, "ifExists"),
+	}, db.NewQueryOptions().WithUserOrRole(userOrRole).WithContext(params.Context))
+	return err == nil, err
+}
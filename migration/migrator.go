@@ -0,0 +1,597 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/gocql/gocql"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrDestructive is returned when applying a plan that contains destructive statements without allowing them
+var ErrDestructive = errors.New("the plan contains destructive statements")
+
+// Statement is a schema change of a plan
+type Statement struct {
+	Query string
+	// Destructive is set for statements that remove objects or data
+	Destructive bool
+}
+
+// Plan contains the statements needed to change the existing schema into the desired one, in execution order
+type Plan struct {
+	Statements []*Statement
+}
+
+// Destructive returns true when at least one of the statements is destructive
+func (p *Plan) Destructive() bool {
+	for _, statement := range p.Statements {
+		if statement.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+type ApplyOptions struct {
+	AllowDestructive bool
+	UserOrRole       string
+}
+
+// Migrator diffs a desired schema against the existing one and applies the resulting plan
+type Migrator struct {
+	dbClient *db.Db
+}
+
+// Statements are grouped in stages, e.g. the types must be created before the tables that use them and the indexes
+// must be dropped before their columns
+const (
+	stageKeyspace = iota
+	stageTypes
+	stageDropIndexes
+	stageDropColumns
+	stageTables
+	stageCreateIndexes
+	stageDropTables
+	stageDropTypes
+	stageCount
+)
+
+type planBuilder struct {
+	stages [stageCount][]*Statement
+}
+
+func NewMigrator(dbClient *db.Db) *Migrator {
+	return &Migrator{dbClient: dbClient}
+}
+
+// Plan returns the statements needed to change the keyspaces into the desired state
+func (m *Migrator) Plan(schema *Schema) (*Plan, error) {
+	builder := &planBuilder{}
+	for _, desired := range schema.Keyspaces {
+		keyspace, err := m.dbClient.Keyspace(desired.Name)
+		if err != nil {
+			if _, ok := err.(*db.DbObjectNotFound); !ok {
+				return nil, err
+			}
+			keyspace = nil
+		}
+
+		var indexes map[string][]*db.IndexMetadata
+		if keyspace != nil {
+			if indexes, err = m.dbClient.Indexes(desired.Name); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := builder.diffKeyspace(desired, keyspace, indexes); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.plan(), nil
+}
+
+// Apply executes the statements of the plan in order, waiting for schema agreement after each one. It returns the
+// number of statements executed.
+func (m *Migrator) Apply(ctx context.Context, plan *Plan, options *ApplyOptions) (int, error) {
+	if plan.Destructive() && !options.AllowDestructive {
+		return 0, ErrDestructive
+	}
+
+	queryOptions := db.NewQueryOptions().WithUserOrRole(options.UserOrRole).WithContext(ctx)
+	for i, statement := range plan.Statements {
+		if err := m.dbClient.ChangeSchema(statement.Query, queryOptions); err != nil {
+			return i, fmt.Errorf("unable to execute '%s': %s", statement.Query, err)
+		}
+	}
+
+	return len(plan.Statements), nil
+}
+
+func (b *planBuilder) add(stage int, destructive bool, format string, args ...interface{}) {
+	b.stages[stage] = append(b.stages[stage], &Statement{Query: fmt.Sprintf(format, args...), Destructive: destructive})
+}
+
+func (b *planBuilder) plan() *Plan {
+	statements := make([]*Statement, 0)
+	for _, stage := range b.stages {
+		statements = append(statements, stage...)
+	}
+	return &Plan{Statements: statements}
+}
+
+func (b *planBuilder) diffKeyspace(
+	desired *Keyspace, current *gocql.KeyspaceMetadata, indexes map[string][]*db.IndexMetadata,
+) error {
+	replication := replicationStr(desired)
+	if current == nil {
+		if replication == "" {
+			return fmt.Errorf("keyspace '%s' does not exist and no replication was provided", desired.Name)
+		}
+
+		b.add(stageKeyspace, false, `CREATE KEYSPACE %s WITH REPLICATION = %s%s`,
+			db.QuoteIdentifier(desired.Name), replication, durableWritesStr(desired.DurableWrites, " AND "))
+	} else {
+		durableWrites := desired.DurableWrites
+		if durableWrites != nil && *durableWrites == current.DurableWrites {
+			durableWrites = nil
+		}
+
+		if replication != "" && !replicationEqual(desired, current) {
+			b.add(stageKeyspace, false, `ALTER KEYSPACE %s WITH REPLICATION = %s%s`,
+				db.QuoteIdentifier(desired.Name), replication, durableWritesStr(durableWrites, " AND "))
+		} else if durableWrites != nil {
+			b.add(stageKeyspace, false, `ALTER KEYSPACE %s WITH %s`,
+				db.QuoteIdentifier(desired.Name), durableWritesStr(durableWrites, ""))
+		}
+	}
+
+	if err := b.diffTypes(desired, current); err != nil {
+		return err
+	}
+
+	return b.diffTables(desired, current, indexes)
+}
+
+func (b *planBuilder) diffTypes(desired *Keyspace, current *gocql.KeyspaceMetadata) error {
+	currentTypes := make(map[string]*db.UserTypeMetadata)
+	if current != nil {
+		for _, udt := range db.UserTypesFromKeyspace(current) {
+			currentTypes[udt.Name] = udt
+		}
+	}
+
+	desiredTypes := make(map[string]bool, len(desired.Types))
+	for _, udt := range desired.Types {
+		desiredTypes[udt.Name] = true
+		existing, ok := currentTypes[udt.Name]
+		if !ok {
+			fields := make([]string, 0, len(udt.FieldDefinitions))
+			for _, field := range udt.FieldDefinitions {
+				fields = append(fields, fmt.Sprintf(`%s %s`, db.QuoteIdentifier(field.Name), field.TypeDefinition))
+			}
+			b.add(stageTypes, false, `CREATE TYPE %s (%s)`,
+				db.QualifiedName(desired.Name, udt.Name), strings.Join(fields, ", "))
+			continue
+		}
+
+		existingFields := make(map[string]*gocql.ColumnMetadata, len(existing.Fields))
+		for _, field := range existing.Fields {
+			existingFields[field.Name] = field
+		}
+
+		fields, _ := columnsByName(udt.FieldDefinitions)
+		for _, field := range existing.Fields {
+			if _, ok := fields[field.Name]; !ok {
+				return fmt.Errorf("type '%s.%s': field '%s' can not be removed", desired.Name, udt.Name, field.Name)
+			}
+		}
+
+		for _, field := range udt.FieldDefinitions {
+			existingField, ok := existingFields[field.Name]
+			if !ok {
+				b.add(stageTypes, false, `ALTER TYPE %s ADD %s %s`,
+					db.QualifiedName(desired.Name, udt.Name), db.QuoteIdentifier(field.Name), field.TypeDefinition)
+				continue
+			}
+
+			existingType, ok := typeString(existingField.Type)
+			if ok && !typeEqual(field.TypeDefinition, existingType) {
+				return fmt.Errorf("type '%s.%s': the type of field '%s' can not be changed",
+					desired.Name, udt.Name, field.Name)
+			}
+		}
+	}
+
+	if current != nil {
+		// UserTypesFromKeyspace returns the types sorted by name
+		for _, udt := range db.UserTypesFromKeyspace(current) {
+			if !desiredTypes[udt.Name] {
+				b.add(stageDropTypes, true, `DROP TYPE %s`, db.QualifiedName(desired.Name, udt.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (b *planBuilder) diffTables(
+	desired *Keyspace, current *gocql.KeyspaceMetadata, indexes map[string][]*db.IndexMetadata,
+) error {
+	currentTables := make(map[string]*gocql.TableMetadata)
+	if current != nil {
+		currentTables = current.Tables
+	}
+
+	desiredTables := make(map[string]bool, len(desired.Tables))
+	for _, table := range desired.Tables {
+		desiredTables[table.Name] = true
+		existing, ok := currentTables[table.Name]
+		if !ok {
+			b.createTable(desired.Name, table)
+			for _, index := range table.Indexes {
+				b.createIndex(desired.Name, table.Name, index)
+			}
+			continue
+		}
+
+		if err := b.alterTable(desired.Name, table, existing); err != nil {
+			return err
+		}
+
+		b.diffIndexes(desired.Name, table, indexes[table.Name])
+	}
+
+	toDrop := make([]string, 0)
+	for name := range currentTables {
+		if !desiredTables[name] {
+			toDrop = append(toDrop, name)
+		}
+	}
+
+	sort.Strings(toDrop)
+	for _, name := range toDrop {
+		b.add(stageDropTables, true, `DROP TABLE %s`, db.QualifiedName(desired.Name, name))
+	}
+
+	return nil
+}
+
+func (b *planBuilder) createTable(keyspace string, table *Table) {
+	columns, _ := columnsByName(table.ColumnDefinitions)
+	keys := make(map[string]bool)
+	definitions := make([]string, 0, len(table.ColumnDefinitions))
+
+	partitionKeys := make([]string, 0, len(table.PrimaryKey.PartitionKey))
+	for _, name := range table.PrimaryKey.PartitionKey {
+		keys[name] = true
+		definitions = append(definitions, columnDefinition(columns[name]))
+		partitionKeys = append(partitionKeys, db.QuoteIdentifier(name))
+	}
+
+	primaryKey := strings.Join(partitionKeys, ", ")
+	clusteringOrder := make([]string, 0, len(table.PrimaryKey.ClusteringKey))
+	if len(table.PrimaryKey.ClusteringKey) > 0 {
+		primaryKey = fmt.Sprintf("(%s)", primaryKey)
+		for _, name := range table.PrimaryKey.ClusteringKey {
+			keys[name] = true
+			definitions = append(definitions, columnDefinition(columns[name]))
+			primaryKey += fmt.Sprintf(`, %s`, db.QuoteIdentifier(name))
+			clusteringOrder = append(clusteringOrder,
+				fmt.Sprintf(`%s %s`, db.QuoteIdentifier(name), table.clusteringOrder(name)))
+		}
+	}
+
+	for _, column := range table.ColumnDefinitions {
+		if !keys[column.Name] {
+			definitions = append(definitions, columnDefinition(column))
+		}
+	}
+
+	query := fmt.Sprintf(`CREATE TABLE %s (%s, PRIMARY KEY (%s))`,
+		db.QualifiedName(keyspace, table.Name), strings.Join(definitions, ", "), primaryKey)
+	if len(clusteringOrder) > 0 {
+		query += fmt.Sprintf(" WITH CLUSTERING ORDER BY (%s)", strings.Join(clusteringOrder, ", "))
+	}
+
+	b.add(stageTables, false, "%s", query)
+}
+
+func (b *planBuilder) alterTable(keyspace string, table *Table, existing *gocql.TableMetadata) error {
+	if !columnNamesEqual(table.PrimaryKey.PartitionKey, existing.PartitionKey) ||
+		!columnNamesEqual(table.PrimaryKey.ClusteringKey, existing.ClusteringColumns) {
+		return fmt.Errorf("table '%s.%s': the primary key can not be changed", keyspace, table.Name)
+	}
+
+	for _, column := range existing.ClusteringColumns {
+		if !strings.EqualFold(table.clusteringOrder(column.Name), clusteringOrder(column)) {
+			return fmt.Errorf("table '%s.%s': the clustering order can not be changed", keyspace, table.Name)
+		}
+	}
+
+	toAdd := make([]string, 0)
+	for _, column := range table.ColumnDefinitions {
+		existingColumn, ok := existing.Columns[column.Name]
+		if !ok {
+			toAdd = append(toAdd, columnDefinition(column))
+			continue
+		}
+
+		if existingType, ok := columnTypeString(existingColumn); ok && !typeEqual(column.TypeDefinition, existingType) {
+			return fmt.Errorf("table '%s.%s': the type of column '%s' can not be changed",
+				keyspace, table.Name, column.Name)
+		}
+
+		if column.Static != (existingColumn.Kind == gocql.ColumnStatic) {
+			return fmt.Errorf("table '%s.%s': column '%s' can not be changed to or from static",
+				keyspace, table.Name, column.Name)
+		}
+	}
+
+	if len(toAdd) > 0 {
+		b.add(stageTables, false, `ALTER TABLE %s ADD (%s)`,
+			db.QualifiedName(keyspace, table.Name), strings.Join(toAdd, ", "))
+	}
+
+	columns, _ := columnsByName(table.ColumnDefinitions)
+	toDrop := make([]string, 0)
+	for name := range existing.Columns {
+		if _, ok := columns[name]; !ok {
+			toDrop = append(toDrop, name)
+		}
+	}
+
+	sort.Strings(toDrop)
+	for _, name := range toDrop {
+		b.add(stageDropColumns, true, `ALTER TABLE %s DROP %s`,
+			db.QualifiedName(keyspace, table.Name), db.QuoteIdentifier(name))
+	}
+
+	return nil
+}
+
+func (b *planBuilder) diffIndexes(keyspace string, table *Table, existing []*db.IndexMetadata) {
+	existingIndexes := make(map[string]*db.IndexMetadata, len(existing))
+	for _, index := range existing {
+		existingIndexes[index.Name] = index
+	}
+
+	desiredIndexes := make(map[string]bool, len(table.Indexes))
+	for _, index := range table.Indexes {
+		desiredIndexes[index.Name] = true
+		existingIndex, ok := existingIndexes[index.Name]
+		if ok && indexEqual(index, existingIndex) {
+			continue
+		}
+
+		if ok {
+			// Indexes can not be altered, it must be recreated
+			b.add(stageDropIndexes, true, `DROP INDEX %s`, db.QualifiedName(keyspace, index.Name))
+		}
+		b.createIndex(keyspace, table.Name, index)
+	}
+
+	for _, index := range existing {
+		if !desiredIndexes[index.Name] {
+			b.add(stageDropIndexes, true, `DROP INDEX %s`, db.QualifiedName(keyspace, index.Name))
+		}
+	}
+}
+
+func (b *planBuilder) createIndex(keyspace string, table string, index *Index) {
+	column := db.QuoteIdentifier(index.Column)
+	if index.Target != "" {
+		column = fmt.Sprintf("%s(%s)", strings.ToUpper(index.Target), column)
+	}
+
+	if index.Class != "" {
+		b.add(stageCreateIndexes, false, `CREATE CUSTOM INDEX %s ON %s (%s) USING '%s'`,
+			db.QuoteIdentifier(index.Name), db.QualifiedName(keyspace, table), column,
+			strings.ReplaceAll(index.Class, "'", "''"))
+		return
+	}
+
+	b.add(stageCreateIndexes, false, `CREATE INDEX %s ON %s (%s)`,
+		db.QuoteIdentifier(index.Name), db.QualifiedName(keyspace, table), column)
+}
+
+func indexEqual(index *Index, existing *db.IndexMetadata) bool {
+	target := strings.ToUpper(index.Target)
+	// The server reports the default target of collection columns as VALUES
+	if target != existing.Target && !(target == "" && existing.Target == db.IndexTargetValues) {
+		return false
+	}
+
+	className := index.Class[strings.LastIndex(index.Class, ".")+1:]
+	return index.Column == existing.Column && className == existing.Class
+}
+
+// replicationStr returns the replication map literal of the keyspace or an empty string when it's not provided
+func replicationStr(ks *Keyspace) string {
+	if len(ks.DataCenters) > 0 {
+		names := make([]string, 0, len(ks.DataCenters))
+		for name := range ks.DataCenters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		dcs := make([]string, 0, len(names))
+		for _, name := range names {
+			dcs = append(dcs, fmt.Sprintf("'%s': %d", strings.ReplaceAll(name, "'", "''"), ks.DataCenters[name]))
+		}
+		return fmt.Sprintf("{ 'class': 'NetworkTopologyStrategy', %s }", strings.Join(dcs, ", "))
+	}
+
+	if ks.ReplicationFactor > 0 {
+		return fmt.Sprintf("{ 'class': 'SimpleStrategy', 'replication_factor': %d }", ks.ReplicationFactor)
+	}
+
+	return ""
+}
+
+func replicationEqual(desired *Keyspace, current *gocql.KeyspaceMetadata) bool {
+	class := current.StrategyClass[strings.LastIndex(current.StrategyClass, ".")+1:]
+	if desired.ReplicationFactor > 0 {
+		return class == "SimpleStrategy" &&
+			strategyOptionInt(current.StrategyOptions["replication_factor"]) == desired.ReplicationFactor
+	}
+
+	if class != "NetworkTopologyStrategy" {
+		return false
+	}
+
+	// Data centers without replicas are equivalent to the ones that are not listed
+	for name, value := range current.StrategyOptions {
+		if replicas := strategyOptionInt(value); name != "class" && replicas != desired.DataCenters[name] {
+			return false
+		}
+	}
+
+	for name, replicas := range desired.DataCenters {
+		if replicas != strategyOptionInt(current.StrategyOptions[name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func strategyOptionInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case string:
+		result, _ := strconv.Atoi(v)
+		return result
+	}
+	return 0
+}
+
+func durableWritesStr(durableWrites *bool, prefix string) string {
+	if durableWrites == nil {
+		return ""
+	}
+	return fmt.Sprintf("%sDURABLE_WRITES = %t", prefix, *durableWrites)
+}
+
+func columnDefinition(column *Column) string {
+	if column.Static {
+		return fmt.Sprintf(`%s %s STATIC`, db.QuoteIdentifier(column.Name), column.TypeDefinition)
+	}
+	return fmt.Sprintf(`%s %s`, db.QuoteIdentifier(column.Name), column.TypeDefinition)
+}
+
+func columnNamesEqual(names []string, columns []*gocql.ColumnMetadata) bool {
+	if len(names) != len(columns) {
+		return false
+	}
+
+	for i, column := range columns {
+		if names[i] != column.Name {
+			return false
+		}
+	}
+	return true
+}
+
+func clusteringOrder(column *gocql.ColumnMetadata) string {
+	if column.ClusteringOrder == "" {
+		return "ASC"
+	}
+	return column.ClusteringOrder
+}
+
+// columnTypeString returns the CQL type of a column, the validator contains the type as defined in the schema tables
+func columnTypeString(column *gocql.ColumnMetadata) (string, bool) {
+	if column.Validator != "" {
+		return column.Validator, true
+	}
+	return typeString(column.Type)
+}
+
+// typeString returns the CQL type of the type info, it returns false when the type can not be determined, e.g. the
+// driver doesn't provide the name of user-defined types nested in other types
+func typeString(info gocql.TypeInfo) (string, bool) {
+	if info == nil {
+		return "", false
+	}
+
+	switch t := info.(type) {
+	case gocql.CollectionType:
+		elem, ok := typeString(t.Elem)
+		if !ok {
+			return "", false
+		}
+
+		if t.Type() == gocql.TypeMap {
+			key, ok := typeString(t.Key)
+			return fmt.Sprintf("map<%s, %s>", key, elem), ok
+		}
+		return fmt.Sprintf("%s<%s>", t.Type().String(), elem), true
+	case gocql.TupleTypeInfo:
+		elems := make([]string, 0, len(t.Elems))
+		for _, elem := range t.Elems {
+			value, ok := typeString(elem)
+			if !ok {
+				return "", false
+			}
+			elems = append(elems, value)
+		}
+		return fmt.Sprintf("tuple<%s>", strings.Join(elems, ", ")), true
+	case gocql.UDTTypeInfo:
+		return t.Name, true
+	}
+
+	if info.Type() == gocql.TypeCustom {
+		return "", false
+	}
+	return info.Type().String(), true
+}
+
+// typeEqual compares a CQL type definition with the type of an existing column, ignoring frozen qualifiers and
+// formatting differences
+func typeEqual(definition string, existing string) bool {
+	return normalizeType(definition) == normalizeType(existing)
+}
+
+var varcharRegex = regexp.MustCompile(`\bvarchar\b`)
+
+func normalizeType(value string) string {
+	value = strings.ToLower(strings.Join(strings.Fields(value), ""))
+	value = strings.ReplaceAll(value, `"`, "")
+
+	for {
+		start := strings.Index(value, "frozen<")
+		if start < 0 {
+			break
+		}
+
+		// Find the closing bracket of the frozen type
+		depth := 0
+		end := -1
+		for i := start + len("frozen"); i < len(value) && end < 0; i++ {
+			switch value[i] {
+			case '<':
+				depth++
+			case '>':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+		}
+
+		if end < 0 {
+			break
+		}
+		value = value[:start] + value[start+len("frozen<"):end] + value[end+1:]
+	}
+
+	// varchar is an alias of text
+	return varcharRegex.ReplaceAllString(value, "text")
+}
@@ -0,0 +1,228 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+func TestPlanNewKeyspace(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.On("KeyspaceMetadata", "dev").
+		Return((*gocql.KeyspaceMetadata)(nil), errors.New("keyspace does not exist"))
+
+	schema, err := ParseSchema(strings.NewReader(`{"keyspaces":[{
+  "name": "dev",
+  "replicationFactor": 1,
+  "types": [{"name":"address","fieldDefinitions":[{"name":"street","typeDefinition":"text"}]}],
+  "tables": [{
+    "name": "events",
+    "columnDefinitions": [
+      {"name":"day","typeDefinition":"date"},
+      {"name":"ts","typeDefinition":"timestamp"},
+      {"name":"location","typeDefinition":"frozen<address>"},
+      {"name":"owner","typeDefinition":"text","static":true}
+    ],
+    "primaryKey": {"partitionKey":["day"],"clusteringKey":["ts"]},
+    "clusteringExpression": [{"column":"ts","order":"desc"}],
+    "indexes": [{"name":"events_owner_idx","column":"owner"}]
+  }]
+}]}`))
+	assert.NoError(t, err)
+
+	plan, err := NewMigrator(db.NewDbWithSession(sessionMock)).Plan(schema)
+	assert.NoError(t, err)
+	assert.False(t, plan.Destructive())
+	assert.Equal(t, []string{
+		`CREATE KEYSPACE "dev" WITH REPLICATION = { 'class': 'SimpleStrategy', 'replication_factor': 1 }`,
+		`CREATE TYPE "dev"."address" ("street" text)`,
+		`CREATE TABLE "dev"."events" ("day" date, "ts" timestamp, "location" frozen<address>, "owner" text STATIC, ` +
+			`PRIMARY KEY (("day"), "ts")) WITH CLUSTERING ORDER BY ("ts" DESC)`,
+		`CREATE INDEX "events_owner_idx" ON "dev"."events" ("owner")`,
+	}, queries(plan))
+}
+
+func TestPlanExistingKeyspace(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.AddKeyspace(db.NewKeyspaceMock("store", map[string][]*gocql.ColumnMetadata{
+		"books": db.BooksColumnsMock,
+		"authors": {
+			{Name: "name", Kind: gocql.ColumnPartitionKey, Type: gocql.NewNativeType(0, gocql.TypeText, "")},
+		},
+	}))
+	sessionMock.AddIndexes([]*db.IndexMetadata{
+		{Name: "books_pages_idx", Table: "books", Column: "pages"},
+		{Name: "books_last_name_idx", Table: "books", Column: "last_name"},
+	})
+
+	schema, err := ParseSchema(strings.NewReader(`{"keyspaces":[{
+  "name": "store",
+  "dataCenters": {"dc1": 3},
+  "durableWrites": false,
+  "tables": [{
+    "name": "books",
+    "columnDefinitions": [
+      {"name":"title","typeDefinition":"varchar"},
+      {"name":"pages","typeDefinition":"int"},
+      {"name":"first_name","typeDefinition":"text"},
+      {"name":"tags","typeDefinition":"set<text>"}
+    ],
+    "primaryKey": {"partitionKey":["title"]},
+    "indexes": [
+      {"name":"books_pages_idx","column":"pages"},
+      {"name":"books_tags_idx","column":"tags"}
+    ]
+  }]
+}]}`))
+	assert.NoError(t, err)
+
+	plan, err := NewMigrator(db.NewDbWithSession(sessionMock)).Plan(schema)
+	assert.NoError(t, err)
+	assert.True(t, plan.Destructive())
+	assert.Equal(t, []string{
+		`ALTER KEYSPACE "store" WITH DURABLE_WRITES = false`,
+		`DROP INDEX "store"."books_last_name_idx"`,
+		`ALTER TABLE "store"."books" DROP "last_name"`,
+		`ALTER TABLE "store"."books" ADD ("tags" set<text>)`,
+		`CREATE INDEX "books_tags_idx" ON "store"."books" ("tags")`,
+		`DROP TABLE "store"."authors"`,
+	}, queries(plan))
+
+	destructive := make([]bool, 0, len(plan.Statements))
+	for _, statement := range plan.Statements {
+		destructive = append(destructive, statement.Destructive)
+	}
+	assert.Equal(t, []bool{false, true, true, false, false, true}, destructive)
+}
+
+func TestPlanUnsupportedChanges(t *testing.T) {
+	sessionMock := db.NewSessionMock().Default()
+	migrator := NewMigrator(db.NewDbWithSession(sessionMock))
+
+	items := []struct {
+		table    string
+		expected string
+	}{
+		{`{"name":"books","columnDefinitions":[{"name":"title","typeDefinition":"text"},` +
+			`{"name":"pages","typeDefinition":"int"}],"primaryKey":{"partitionKey":["pages"]}}`,
+			"the primary key can not be changed"},
+		{`{"name":"books","columnDefinitions":[{"name":"title","typeDefinition":"text"},` +
+			`{"name":"pages","typeDefinition":"bigint"}],"primaryKey":{"partitionKey":["title"]}}`,
+			"the type of column 'pages' can not be changed"},
+	}
+
+	for _, item := range items {
+		schema, err := ParseSchema(strings.NewReader(`{"keyspaces":[{"name":"store","tables":[` + item.table + `]}]}`))
+		assert.NoError(t, err)
+
+		_, err = migrator.Plan(schema)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), item.expected)
+	}
+}
+
+func TestApply(t *testing.T) {
+	sessionMock := db.NewSessionMock()
+	sessionMock.On("ChangeSchema", mock.Anything, mock.Anything).Return(nil)
+	migrator := NewMigrator(db.NewDbWithSession(sessionMock))
+
+	plan := &Plan{Statements: []*Statement{
+		{Query: `ALTER TABLE "store"."books" ADD ("year" int)`},
+		{Query: `DROP TABLE "store"."authors"`, Destructive: true},
+	}}
+
+	applied, err := migrator.Apply(context.Background(), plan, &ApplyOptions{})
+	assert.Equal(t, ErrDestructive, err)
+	assert.Equal(t, 0, applied)
+	sessionMock.AssertNotCalled(t, "ChangeSchema", mock.Anything, mock.Anything)
+
+	applied, err = migrator.Apply(context.Background(), plan, &ApplyOptions{AllowDestructive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	sessionMock.AssertCalled(t, "ChangeSchema", `ALTER TABLE "store"."books" ADD ("year" int)`, mock.Anything)
+	sessionMock.AssertCalled(t, "ChangeSchema", `DROP TABLE "store"."authors"`, mock.Anything)
+}
+
+func TestParseSchemaInvalid(t *testing.T) {
+	items := []struct {
+		document string
+		expected string
+	}{
+		{`{"keyspaces":[]}`, "at least one keyspace must be provided"},
+		{`{"keyspaces":[{"name":"ks1","unknown":true}]}`, "invalid schema document"},
+		{`{"keyspaces":[{"name":"ks1","replicationFactor":1,"dataCenters":{"dc1":3}}]}`,
+			"data centers and replication factor can not be used together"},
+		{`{"keyspaces":[{"name":"ks1","tables":[{"name":"tbl1","columnDefinitions":[` +
+			`{"name":"id","typeDefinition":"int"}],"primaryKey":{"partitionKey":["key"]}}]}]}`,
+			"primary key column 'key' is not defined"},
+		{`{"keyspaces":[{"name":"ks1","tables":[{"name":"tbl1","columnDefinitions":[` +
+			`{"name":"id","typeDefinition":"int"}],"primaryKey":{"partitionKey":["id"]},` +
+			`"clusteringExpression":[{"column":"id","order":"desc"}]}]}]}`,
+			"clustering expression column 'id' is not a clustering key"},
+		{`{"keyspaces":[{"name":"ks1","tables":[{"name":"tbl1","columnDefinitions":[` +
+			`{"name":"id","typeDefinition":"int) WITH comment = 'x'"}],"primaryKey":{"partitionKey":["id"]}}]}]}`,
+			"type of column 'id' is not valid"},
+		{`{"keyspaces":[{"name":"ks1","tables":[{"name":"tbl1","columnDefinitions":[` +
+			`{"name":"id","typeDefinition":"int"},{"name":"tags","typeDefinition":"set<text>"}],` +
+			`"primaryKey":{"partitionKey":["id"]},"indexes":[{"name":"tbl1_tags_idx","column":"tags",` +
+			`"target":"VALUES(\"tags\")); DROP TABLE ks1.tbl1; --"}]}]}]}`,
+			"invalid target 'VALUES(\"tags\")); DROP TABLE ks1.tbl1; --' for index 'tbl1_tags_idx'"},
+	}
+
+	for _, item := range items {
+		_, err := ParseSchema(strings.NewReader(item.document))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), item.expected)
+	}
+}
+
+func TestValidateTypeDefinition(t *testing.T) {
+	for _, definition := range []string{
+		"int", "frozen<address>", "map<text, frozen<list<int>>>", "tuple<int, text, uuid>", `"Address"`,
+		`frozen<"a""b">`,
+	} {
+		assert.NoError(t, validateTypeDefinition(definition), definition)
+	}
+
+	items := []struct {
+		definition string
+		expected   string
+	}{
+		{"int) WITH comment = 'x'; DROP TABLE ks1.tbl1; --", "unexpected ')"},
+		{"int, other text", "unexpected ','"},
+		{"map<text>", "type 'map' requires 2 parameter(s)"},
+		{"list<int", "'>' expected"},
+		{"list", "type 'list' requires parameters"},
+		{"int<text>", "type 'int' does not accept parameters"},
+		{"tuple<>", "unexpected '>'"},
+		{`"a"b"`, `unexpected '"'`},
+	}
+
+	for _, item := range items {
+		err := validateTypeDefinition(item.definition)
+		assert.Error(t, err, item.definition)
+		if err != nil {
+			assert.Contains(t, err.Error(), item.expected)
+		}
+	}
+}
+
+func TestTypeEqual(t *testing.T) {
+	assert.True(t, typeEqual("frozen<map<text, frozen<address>>>", "map<text,address>"))
+	assert.True(t, typeEqual("VARCHAR", "text"))
+	assert.True(t, typeEqual("list<varchar>", "list<text>"))
+	assert.False(t, typeEqual("list<int>", "set<int>"))
+}
+
+func queries(plan *Plan) []string {
+	result := make([]string, 0, len(plan.Statements))
+	for _, statement := range plan.Statements {
+		result = append(result, statement.Query)
+	}
+	return result
+}
@@ -0,0 +1,350 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/db"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Schema is the desired state of one or more keyspaces. Keyspaces that are not part of the document are not modified.
+type Schema struct {
+	Keyspaces []*Keyspace `json:"keyspaces"`
+}
+
+// Keyspace describes a keyspace and all the objects it contains, objects that exist in the database but are not part
+// of the document are dropped
+type Keyspace struct {
+	Name string `json:"name"`
+	// DataCenters contains the replicas per data center using NetworkTopologyStrategy
+	DataCenters map[string]int `json:"dataCenters,omitempty"`
+	// ReplicationFactor is used with SimpleStrategy when no data center replicas are provided
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+	// DurableWrites is not changed when nil
+	DurableWrites *bool       `json:"durableWrites,omitempty"`
+	Types         []*UserType `json:"types,omitempty"`
+	Tables        []*Table    `json:"tables,omitempty"`
+}
+
+type UserType struct {
+	Name             string    `json:"name"`
+	FieldDefinitions []*Column `json:"fieldDefinitions"`
+}
+
+type Table struct {
+	Name                 string                  `json:"name"`
+	ColumnDefinitions    []*Column               `json:"columnDefinitions"`
+	PrimaryKey           *PrimaryKey             `json:"primaryKey"`
+	ClusteringExpression []*ClusteringExpression `json:"clusteringExpression,omitempty"`
+	Indexes              []*Index                `json:"indexes,omitempty"`
+}
+
+// Column describes a table column or a user-defined type field using its CQL type, e.g. "map<text, int>".
+// The type definition is validated against the CQL type grammar, as it's included in the statements as is.
+type Column struct {
+	Name           string `json:"name"`
+	TypeDefinition string `json:"typeDefinition"`
+	Static         bool   `json:"static,omitempty"`
+}
+
+type PrimaryKey struct {
+	PartitionKey  []string `json:"partitionKey"`
+	ClusteringKey []string `json:"clusteringKey,omitempty"`
+}
+
+type ClusteringExpression struct {
+	Column string `json:"column"`
+	Order  string `json:"order"`
+}
+
+type Index struct {
+	Name   string `json:"name"`
+	Column string `json:"column"`
+	// Target is one of KEYS, VALUES, ENTRIES or FULL for collection columns, empty otherwise
+	Target string `json:"target,omitempty"`
+	// Class is the custom index implementation, e.g. StorageAttachedIndex, empty for a regular secondary index
+	Class string `json:"class,omitempty"`
+}
+
+// ParseSchema reads and validates a JSON schema document
+func ParseSchema(r io.Reader) (*Schema, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var schema Schema
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("invalid schema document: %s", err)
+	}
+
+	if err := schema.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+// Validate checks that the document is complete and that all the names it references are defined
+func (s *Schema) Validate() error {
+	if len(s.Keyspaces) == 0 {
+		return fmt.Errorf("at least one keyspace must be provided")
+	}
+
+	keyspaces := make(map[string]bool, len(s.Keyspaces))
+	for _, ks := range s.Keyspaces {
+		if ks == nil || ks.Name == "" {
+			return fmt.Errorf("keyspace name must be provided")
+		}
+
+		if keyspaces[ks.Name] {
+			return fmt.Errorf("keyspace '%s' is defined more than once", ks.Name)
+		}
+		keyspaces[ks.Name] = true
+
+		if err := ks.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ks *Keyspace) validate() error {
+	if len(ks.DataCenters) > 0 && ks.ReplicationFactor > 0 {
+		return fmt.Errorf("keyspace '%s': data centers and replication factor can not be used together", ks.Name)
+	}
+
+	if ks.ReplicationFactor < 0 {
+		return fmt.Errorf("keyspace '%s': invalid replication factor %d", ks.Name, ks.ReplicationFactor)
+	}
+
+	for dc, replicas := range ks.DataCenters {
+		if replicas < 0 {
+			return fmt.Errorf("keyspace '%s': invalid replicas for data center '%s'", ks.Name, dc)
+		}
+	}
+
+	types := make(map[string]bool, len(ks.Types))
+	for _, udt := range ks.Types {
+		if udt == nil || udt.Name == "" {
+			return fmt.Errorf("keyspace '%s': type name must be provided", ks.Name)
+		}
+
+		if types[udt.Name] {
+			return fmt.Errorf("keyspace '%s': type '%s' is defined more than once", ks.Name, udt.Name)
+		}
+		types[udt.Name] = true
+
+		if len(udt.FieldDefinitions) == 0 {
+			return fmt.Errorf("type '%s.%s': at least one field must be provided", ks.Name, udt.Name)
+		}
+
+		if _, err := columnsByName(udt.FieldDefinitions); err != nil {
+			return fmt.Errorf("type '%s.%s': %s", ks.Name, udt.Name, err)
+		}
+	}
+
+	tables := make(map[string]bool, len(ks.Tables))
+	indexes := make(map[string]bool)
+	for _, table := range ks.Tables {
+		if table == nil || table.Name == "" {
+			return fmt.Errorf("keyspace '%s': table name must be provided", ks.Name)
+		}
+
+		if tables[table.Name] {
+			return fmt.Errorf("keyspace '%s': table '%s' is defined more than once", ks.Name, table.Name)
+		}
+		tables[table.Name] = true
+
+		if err := table.validate(); err != nil {
+			return fmt.Errorf("table '%s.%s': %s", ks.Name, table.Name, err)
+		}
+
+		// Index names are unique per keyspace
+		for _, index := range table.Indexes {
+			if indexes[index.Name] {
+				return fmt.Errorf("keyspace '%s': index '%s' is defined more than once", ks.Name, index.Name)
+			}
+			indexes[index.Name] = true
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) validate() error {
+	columns, err := columnsByName(t.ColumnDefinitions)
+	if err != nil {
+		return err
+	}
+
+	if t.PrimaryKey == nil || len(t.PrimaryKey.PartitionKey) == 0 {
+		return fmt.Errorf("at least one partition key column must be provided")
+	}
+
+	keys := make(map[string]bool)
+	for _, name := range append(append([]string{}, t.PrimaryKey.PartitionKey...), t.PrimaryKey.ClusteringKey...) {
+		column, ok := columns[name]
+		if !ok {
+			return fmt.Errorf("primary key column '%s' is not defined", name)
+		}
+
+		if column.Static {
+			return fmt.Errorf("primary key column '%s' can not be static", name)
+		}
+
+		if keys[name] {
+			return fmt.Errorf("column '%s' is used more than once in the primary key", name)
+		}
+		keys[name] = true
+	}
+
+	clusteringKeys := make(map[string]bool, len(t.PrimaryKey.ClusteringKey))
+	for _, name := range t.PrimaryKey.ClusteringKey {
+		clusteringKeys[name] = true
+	}
+
+	for _, expression := range t.ClusteringExpression {
+		if !clusteringKeys[expression.Column] {
+			return fmt.Errorf("clustering expression column '%s' is not a clustering key", expression.Column)
+		}
+
+		if order := strings.ToUpper(expression.Order); order != "ASC" && order != "DESC" {
+			return fmt.Errorf("invalid clustering order '%s'", expression.Order)
+		}
+	}
+
+	for _, index := range t.Indexes {
+		if index == nil || index.Name == "" {
+			return fmt.Errorf("index name must be provided")
+		}
+
+		if _, ok := columns[index.Column]; !ok {
+			return fmt.Errorf("index '%s' column '%s' is not defined", index.Name, index.Column)
+		}
+
+		switch strings.ToUpper(index.Target) {
+		case "", db.IndexTargetKeys, db.IndexTargetValues, db.IndexTargetEntries, db.IndexTargetFull:
+		default:
+			return fmt.Errorf("invalid target '%s' for index '%s'", index.Target, index.Name)
+		}
+	}
+
+	return nil
+}
+
+// clusteringOrder returns the order of a clustering column, ASC when it's not part of the clustering expression
+func (t *Table) clusteringOrder(column string) string {
+	for _, expression := range t.ClusteringExpression {
+		if expression.Column == column {
+			return strings.ToUpper(expression.Order)
+		}
+	}
+	return "ASC"
+}
+
+func columnsByName(columns []*Column) (map[string]*Column, error) {
+	result := make(map[string]*Column, len(columns))
+	for _, column := range columns {
+		if column == nil || column.Name == "" {
+			return nil, fmt.Errorf("column name must be provided")
+		}
+
+		if column.TypeDefinition == "" {
+			return nil, fmt.Errorf("type of column '%s' must be provided", column.Name)
+		}
+
+		if err := validateTypeDefinition(column.TypeDefinition); err != nil {
+			return nil, fmt.Errorf("type of column '%s' is not valid: %s", column.Name, err)
+		}
+
+		if _, ok := result[column.Name]; ok {
+			return nil, fmt.Errorf("column '%s' is defined more than once", column.Name)
+		}
+		result[column.Name] = column
+	}
+	return result, nil
+}
+
+// typeArity contains the number of parameters of the parameterized CQL types, tuples accept one or more
+var typeArity = map[string]int{"frozen": 1, "list": 1, "set": 1, "map": 2, "tuple": -1}
+
+var typeTokenRegex = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z0-9_]*|"(?:[^"]|"")+"|[<>,])`)
+
+// validateTypeDefinition checks that the value is a CQL type, as it's included in the statements without quoting:
+// a native or user-defined type name, optionally followed by the type parameters, e.g. "map<text, frozen<address>>"
+func validateTypeDefinition(definition string) error {
+	tokens := make([]string, 0)
+	rest := definition
+	for strings.TrimSpace(rest) != "" {
+		match := typeTokenRegex.FindStringSubmatch(rest)
+		if match == nil {
+			return fmt.Errorf("unexpected '%s'", strings.TrimSpace(rest))
+		}
+		tokens = append(tokens, match[1])
+		rest = rest[len(match[0]):]
+	}
+
+	position, err := parseTypeTokens(tokens, 0)
+	if err != nil {
+		return err
+	}
+
+	if position != len(tokens) {
+		return fmt.Errorf("unexpected '%s'", tokens[position])
+	}
+	return nil
+}
+
+// parseTypeTokens parses a single type starting at the provided position and returns the position after it
+func parseTypeTokens(tokens []string, position int) (int, error) {
+	if position >= len(tokens) {
+		return position, fmt.Errorf("type name expected")
+	}
+
+	name := tokens[position]
+	if name == "<" || name == ">" || name == "," {
+		return position, fmt.Errorf("unexpected '%s'", name)
+	}
+	position++
+
+	arity, parameterized := typeArity[strings.ToLower(name)]
+	if !parameterized {
+		if position < len(tokens) && tokens[position] == "<" {
+			return position, fmt.Errorf("type '%s' does not accept parameters", name)
+		}
+		return position, nil
+	}
+
+	if position >= len(tokens) || tokens[position] != "<" {
+		return position, fmt.Errorf("type '%s' requires parameters", name)
+	}
+
+	parameters := 0
+	for {
+		var err error
+		if position, err = parseTypeTokens(tokens, position+1); err != nil {
+			return position, err
+		}
+		parameters++
+
+		if position >= len(tokens) {
+			return position, fmt.Errorf("'>' expected")
+		}
+
+		if tokens[position] == ">" {
+			break
+		}
+
+		if tokens[position] != "," {
+			return position, fmt.Errorf("unexpected '%s'", tokens[position])
+		}
+	}
+
+	if arity > 0 && parameters != arity {
+		return position, fmt.Errorf("type '%s' requires %d parameter(s)", name, arity)
+	}
+
+	return position + 1, nil
+}
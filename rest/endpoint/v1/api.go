@@ -9,6 +9,7 @@ import (
 	"github.com/datastax/cassandra-data-apis/config"
 	"github.com/datastax/cassandra-data-apis/db"
 	e "github.com/datastax/cassandra-data-apis/errors"
+	"github.com/datastax/cassandra-data-apis/events"
 	m "github.com/datastax/cassandra-data-apis/rest/models"
 	"github.com/datastax/cassandra-data-apis/types"
 	"github.com/go-playground/locales/en"
@@ -16,7 +17,10 @@ import (
 	"github.com/go-playground/validator/v10"
 	enTranslations "github.com/go-playground/validator/v10/translations/en"
 	"github.com/gocql/gocql"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -70,7 +74,7 @@ func (s *routeList) GetColumns(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	RespondJSONObjectWithCode(w, http.StatusOK, columnMetadataToColumnDefinition(table.Columns))
+	RespondJSONObjectWithCode(w, http.StatusOK, columnMetadataToColumnDefinition(s.exposure.Table(table).Columns))
 }
 
 func (s *routeList) GetColumn(w http.ResponseWriter, r *http.Request) {
@@ -96,7 +100,7 @@ func (s *routeList) GetColumn(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	columns := columnMetadataToColumnDefinition(table.Columns)
+	columns := columnMetadataToColumnDefinition(s.exposure.Table(table).Columns)
 	var column m.ColumnDefinition
 	found := false
 	for _, col := range columns {
@@ -142,7 +146,7 @@ func (s *routeList) AddColumn(w http.ResponseWriter, r *http.Request) {
 		ToAdd:    []*gocql.ColumnMetadata{column},
 	}
 
-	err = s.dbClient.AlterTableAdd(&tableInfo, newDbOptions(user))
+	err = s.dbClient.AlterTableAdd(&tableInfo, newAuditedDbOptions(r, user))
 	if err != nil {
 		msg := "unable to execute alter table query"
 		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
@@ -172,7 +176,7 @@ func (s *routeList) DeleteColumn(w http.ResponseWriter, r *http.Request) {
 		Keyspace: keyspaceName,
 		Table:    tableName,
 		ToDrop:   []string{columnName},
-	}, newDbOptions(user))
+	}, newAuditedDbOptions(r, user))
 
 	if err != nil {
 		msg := "unable to execute alter table query"
@@ -221,6 +225,12 @@ func (s *routeList) GetRow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	access := s.columnAccess(r, tblMetadata)
+	if err := access.CheckUnrestricted(columns...); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	where := make([]types.ConditionItem, len(columns))
 	for i, columnName := range columns {
 		where[i] = types.ConditionItem{
@@ -230,6 +240,11 @@ func (s *routeList) GetRow(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if where, err = s.restrictRows(r, tblMetadata, where); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	rs, err := s.dbClient.Select(&db.SelectInfo{
 		Keyspace: keyspaceName,
 		Table:    tableName,
@@ -251,7 +266,7 @@ func (s *routeList) GetRow(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rowsModel := m.Rows{
-		Rows:  types.ToJsonValues(rs.Values(), tblMetadata),
+		Rows:  types.ToJsonValues(access.ApplyToRows(rs.Values()), tblMetadata),
 		Count: length,
 	}
 
@@ -314,13 +329,43 @@ func (s *routeList) AddRow(w http.ResponseWriter, r *http.Request) {
 		values[i] = convertedType
 	}
 
-	_, err = s.dbClient.Insert(&db.InsertInfo{
+	access := s.columnAccess(r, tblMetadata)
+	if err := access.CheckUnrestricted(columns...); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := s.checkRowAccess(r, tblMetadata, columns, values); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	ttl, err := ttlValue(rowAdd.TTL)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	options, err := writeOptions(r, user)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := db.ValidateTimestamp(rowAdd.Timestamp, rowAdd.IfNotExists); err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs, err := s.dbClient.Insert(&db.InsertInfo{
 		Keyspace:    keyspaceName,
 		Table:       tableName,
 		Columns:     columns,
 		QueryParams: values,
-		TTL:         0,
-	}, newDbOptions(user))
+		IfNotExists: rowAdd.IfNotExists,
+		TTL:         ttl,
+		Timestamp:   rowAdd.Timestamp,
+	}, options)
 
 	if err != nil {
 		msg := "unable to execute insert query"
@@ -329,10 +374,15 @@ func (s *routeList) AddRow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	RespondJSONObjectWithCode(w, http.StatusCreated, m.RowsResponse{
-		Success:      true,
-		RowsModified: 1,
-	})
+	response := modificationResponse(rs, tblMetadata, rowAdd.IfNotExists, access)
+	status := http.StatusCreated
+	if response.Applied != nil && !*response.Applied {
+		status = http.StatusOK
+	} else {
+		s.publish(events.NewEvent(tblMetadata, events.Insert, columns, values, user))
+	}
+
+	RespondJSONObjectWithCode(w, status, response)
 }
 
 func (s *routeList) Query(w http.ResponseWriter, r *http.Request) {
@@ -373,10 +423,27 @@ func (s *routeList) Query(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		var value interface{} = filter.Value
+		switch filter.Operator {
+		case "contains", "containsKey":
+			if len(filter.Value) != 1 {
+				RespondWithError(w, fmt.Sprintf("operator '%s' requires a single value", filter.Operator),
+					http.StatusBadRequest)
+				return
+			}
+			value = filter.Value[0]
+		case "containsEntry":
+			if len(filter.Value) != 2 {
+				RespondWithError(w, "operator 'containsEntry' requires a key and a value", http.StatusBadRequest)
+				return
+			}
+			value = types.MapEntry{Key: filter.Value[0], Value: filter.Value[1]}
+		}
+
 		where[i] = types.ConditionItem{
 			Column:   filter.ColumnName,
 			Operator: operator,
-			Value:    filter.Value,
+			Value:    value,
 		}
 	}
 
@@ -399,14 +466,144 @@ func (s *routeList) Query(w http.ResponseWriter, r *http.Request) {
 		}}
 	}
 
-	rs, err := s.dbClient.Select(&db.SelectInfo{
-		Keyspace: keyspaceName,
-		Table:    tableName,
-		Columns:  queryModel.ColumnNames,
-		Where:    where,
-		OrderBy:  orderBy,
+	aggregates := make([]db.Aggregate, len(queryModel.Aggregates))
+	for i, aggregate := range queryModel.Aggregates {
+		if err := db.ValidateAggregate(aggregate.Function, aggregate.ColumnName); err != nil {
+			RespondWithError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if _, ok := tblMetadata.Columns[aggregate.ColumnName]; aggregate.ColumnName != "" && !ok {
+			RespondWithError(w, fmt.Sprintf("aggregate column '%s' not found in table", aggregate.ColumnName),
+				http.StatusBadRequest)
+			return
+		}
+
+		if aggregate.Alias != "" {
+			if err := db.ValidateAlias(aggregate.Alias); err != nil {
+				RespondWithError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		function := strings.ToLower(aggregate.Function)
+		column := aggregate.ColumnName
+		if column == "" {
+			column = "*"
+		}
+
+		alias := aggregate.Alias
+		if alias == "" {
+			alias = fmt.Sprintf("%s(%s)", function, column)
+		}
+
+		aggregates[i] = db.Aggregate{
+			Function: strings.ToUpper(function),
+			Column:   aggregate.ColumnName,
+			Alias:    alias,
+		}
+	}
+
+	if err := db.ValidateGroupBy(tblMetadata, queryModel.GroupBy); err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	columns := db.NewSelectColumns(queryModel.ColumnNames...)
+	if len(columns) == 0 && len(aggregates) > 0 {
+		// Include the group by columns along with the aggregated values
+		columns = db.NewSelectColumns(queryModel.GroupBy...)
+	}
+
+	if len(queryModel.MetadataColumns) > 0 {
+		if len(aggregates) > 0 {
+			RespondWithError(w, "metadata columns can not be combined with aggregates", http.StatusBadRequest)
+			return
+		}
+
+		if len(columns) == 0 {
+			// Metadata can not be selected along with the wildcard selector
+			columns = db.NewSelectColumns(db.TableColumnNames(tblMetadata)...)
+		}
+
+		for _, metadata := range queryModel.MetadataColumns {
+			function := strings.ToLower(metadata.Function)
+			if function != "writetime" && function != "ttl" {
+				RespondWithError(w, fmt.Sprintf("metadata function '%s' not found", metadata.Function),
+					http.StatusBadRequest)
+				return
+			}
+
+			column, ok := tblMetadata.Columns[metadata.ColumnName]
+			if !ok || !db.HasCellMetadata(column) {
+				RespondWithError(w, fmt.Sprintf("metadata can only be selected for non primary key columns that are "+
+					"not collections or counters, '%s' is not valid", metadata.ColumnName), http.StatusBadRequest)
+				return
+			}
+
+			alias := metadata.Alias
+			if alias == "" {
+				alias = fmt.Sprintf("%s(%s)", function, metadata.ColumnName)
+			} else if err := db.ValidateAlias(alias); err != nil {
+				RespondWithError(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			columns = append(columns, db.SelectColumn{
+				Name:     metadata.ColumnName,
+				Function: strings.ToUpper(function),
+				Alias:    alias,
+			})
+		}
+	}
+
+	restricted := append(conditionColumns(where), queryModel.GroupBy...)
+	for _, order := range orderBy {
+		restricted = append(restricted, order.Column)
+	}
+	for _, aggregate := range aggregates {
+		if aggregate.Column != "" {
+			restricted = append(restricted, aggregate.Column)
+		}
+	}
+	for _, metadata := range queryModel.MetadataColumns {
+		restricted = append(restricted, metadata.ColumnName)
+	}
+
+	access := s.columnAccess(r, tblMetadata)
+	if err := access.CheckUnrestricted(restricted...); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if where, err = s.restrictRows(r, tblMetadata, where); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	rows, err := s.dbClient.SelectIter(&db.SelectInfo{
+		Keyspace:   keyspaceName,
+		Table:      tableName,
+		Columns:    columns,
+		Where:      where,
+		OrderBy:    orderBy,
+		Aggregates: aggregates,
+		GroupBy:    queryModel.GroupBy,
 	}, newDbOptions(user).WithPageSize(queryModel.PageSize).WithPageState(pageState))
 
+	if err == nil && access != nil {
+		rows = &accessRowIterator{RowIterator: rows, access: access}
+	}
+
+	if err == nil && !rows.Next() {
+		// Empty page or the query failed
+		err = rows.Close()
+		if err == nil {
+			respondEmptyRows(w, r, rows.PageState())
+			return
+		}
+	}
+
 	if err != nil {
 		msg := "unable to execute select query"
 		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
@@ -414,12 +611,20 @@ func (s *routeList) Query(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rowsModel := m.Rows{
-		Rows:      types.ToJsonValues(rs.Values(), tblMetadata),
-		PageState: base64.StdEncoding.EncodeToString(rs.PageState()),
-		Count:     len(rs.Values()),
+	// The first row was scanned, from this point the rows are streamed as they are read
+	var count int
+	if acceptsNDJSON(r) {
+		count, err = writeRowsNDJSON(w, rows, tblMetadata)
+	} else {
+		count, err = writeRowsJSON(w, rows, tblMetadata)
+	}
+
+	if err != nil {
+		_ = rows.Close()
+		// The status code was already sent, the client gets a truncated JSON body or a NDJSON error line
+		s.logger.Debug("unable to stream query rows",
+			"keyspace", keyspaceName, "table", tableName, "rows", count, "error", err)
 	}
-	RespondJSONObjectWithCode(w, http.StatusOK, rowsModel)
 }
 
 func (s *routeList) UpdateRow(w http.ResponseWriter, r *http.Request) {
@@ -486,13 +691,50 @@ func (s *routeList) UpdateRow(w http.ResponseWriter, r *http.Request) {
 		index++
 	}
 
-	_, err = s.dbClient.Update(&db.UpdateInfo{
+	ttl, err := ttlValue(rowUpdate.TTL)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ifCondition, err := conditions(rowUpdate.IfConditions, tblMetadata)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	access := s.columnAccess(r, tblMetadata)
+	if err := access.CheckUnrestricted(append(columns, conditionColumns(ifCondition)...)...); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := s.checkRowAccess(r, tblMetadata, columns, values); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	options, err := writeOptions(r, user)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := db.ValidateTimestamp(rowUpdate.Timestamp, rowUpdate.IfExists || len(ifCondition) > 0); err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs, err := s.dbClient.Update(&db.UpdateInfo{
 		Keyspace:    keyspaceName,
 		Table:       tblMetadata,
 		Columns:     columns,
 		QueryParams: values,
-		TTL:         -1,
-	}, newDbOptions(user))
+		IfExists:    rowUpdate.IfExists,
+		IfCondition: ifCondition,
+		TTL:         ttl,
+		Timestamp:   rowUpdate.Timestamp,
+	}, options)
 
 	if err != nil {
 		msg := "Unable to execute update query"
@@ -501,10 +743,12 @@ func (s *routeList) UpdateRow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	RespondJSONObjectWithCode(w, http.StatusOK, &m.RowsResponse{
-		Success:      true,
-		RowsModified: 1,
-	})
+	response := modificationResponse(rs, tblMetadata, rowUpdate.IfExists || len(ifCondition) > 0, access)
+	if response.Applied == nil || *response.Applied {
+		s.publish(events.NewEvent(tblMetadata, events.Update, columns, values, user))
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, response)
 }
 
 func (s *routeList) DeleteRow(w http.ResponseWriter, r *http.Request) {
@@ -537,12 +781,68 @@ func (s *routeList) DeleteRow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = s.dbClient.Delete(&db.DeleteInfo{
+	var timestamp int64
+	if value := r.URL.Query().Get("timestamp"); value != "" {
+		if timestamp, err = strconv.ParseInt(value, 10, 64); err != nil {
+			RespondWithError(w, "Invalid timestamp", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ifExists, err := boolParam(r, "ifExists")
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The body is optional, it only contains the conditions
+	var rowDelete m.RowDelete
+	if r.ContentLength != 0 && r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&rowDelete); err != nil && err != io.EOF {
+			msg := "unable to parse payload"
+			s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+			RespondWithError(w, msg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	ifCondition, err := conditions(rowDelete.IfConditions, tblMetadata)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	access := s.columnAccess(r, tblMetadata)
+	if err := access.CheckUnrestricted(append(columns, conditionColumns(ifCondition)...)...); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	if err := s.checkRowAccess(r, tblMetadata, columns, values); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	options, err := writeOptions(r, user)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := db.ValidateTimestamp(timestamp, ifExists || len(ifCondition) > 0); err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs, err := s.dbClient.Delete(&db.DeleteInfo{
 		Keyspace:    keyspaceName,
 		Table:       tableName,
 		Columns:     columns,
 		QueryParams: values,
-	}, newDbOptions(user))
+		IfExists:    ifExists,
+		IfCondition: ifCondition,
+		Timestamp:   timestamp,
+	}, options)
 
 	if err != nil {
 		msg := "unable to execute delete query"
@@ -551,6 +851,16 @@ func (s *routeList) DeleteRow(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if ifExists || len(ifCondition) > 0 {
+		response := modificationResponse(rs, tblMetadata, true, access)
+		if response.Applied != nil && *response.Applied {
+			s.publish(events.NewEvent(tblMetadata, events.Delete, columns, values, user))
+		}
+		RespondJSONObjectWithCode(w, http.StatusOK, response)
+		return
+	}
+
+	s.publish(events.NewEvent(tblMetadata, events.Delete, columns, values, user))
 	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
 }
 
@@ -586,7 +896,7 @@ func (s *routeList) GetTables(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	RespondJSONObjectWithCode(w, http.StatusOK, tables)
+	RespondJSONObjectWithCode(w, http.StatusOK, s.exposedTables(keyspaceName, tables))
 }
 
 func (s *routeList) GetTable(w http.ResponseWriter, r *http.Request) {
@@ -611,7 +921,80 @@ func (s *routeList) GetTable(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	RespondJSONObjectWithCode(w, http.StatusOK, tableMetadataToTable(table))
+	views, err := s.dbClient.DescribeViews(keyspaceName)
+	if err != nil {
+		msg := "unable to describe views"
+		s.logger.Error(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	options, err := s.dbClient.TableOptions(keyspaceName, tableName)
+	if err != nil {
+		msg := "unable to describe table options"
+		s.logger.Error(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, tableMetadataToTable(s.exposure.Table(table), options, views))
+}
+
+func (s *routeList) UpdateTable(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var tableUpdate m.TableUpdate
+	if err := parseAndValidatePayload(&tableUpdate, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if len(tableUpdate.TableOptions.ClusteringExpression) > 0 {
+		RespondWithError(w, "clustering order can not be changed", http.StatusBadRequest)
+		return
+	}
+
+	options := toDbTableOptions(tableUpdate.TableOptions)
+	if len(options.Compaction) == 0 && len(options.Compression) == 0 && len(options.Caching) == 0 &&
+		options.DefaultTimeToLive == nil && options.GcGraceSeconds == nil && options.BloomFilterFpChance == nil &&
+		options.Comment == nil {
+		RespondWithError(w, "at least one table option must be provided", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.dbClient.DescribeTable(keyspaceName, tableName, user); err != nil {
+		msg := "unable to describe table"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+
+		switch err.(type) {
+		case *e.NotFoundError:
+			RespondWithError(w, msg, http.StatusNotFound)
+			return
+		default:
+			RespondWithError(w, msg, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err := s.dbClient.AlterTableOptions(&db.AlterTableOptionsInfo{
+		Keyspace: keyspaceName,
+		Table:    tableName,
+		Options:  options,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute alter table query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, m.TablesResponse{Success: true})
 }
 
 func (s *routeList) AddTable(w http.ResponseWriter, r *http.Request) {
@@ -631,6 +1014,7 @@ func (s *routeList) AddTable(w http.ResponseWriter, r *http.Request) {
 	tableInfo := db.CreateTableInfo{
 		Keyspace:    keyspaceName,
 		Table:       tableAdd.Name,
+		Options:     toDbTableOptions(tableAdd.TableOptions),
 		IfNotExists: tableAdd.IfNotExists,
 	}
 
@@ -659,7 +1043,7 @@ func (s *routeList) AddTable(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err := s.dbClient.CreateTable(&tableInfo, newDbOptions(user))
+	err := s.dbClient.CreateTable(&tableInfo, newAuditedDbOptions(r, user))
 	if err != nil {
 		msg := "unable to execute create table query"
 		s.logger.Debug(msg, "keyspace", keyspaceName, "error", err)
@@ -680,7 +1064,7 @@ func (s *routeList) DeleteTable(w http.ResponseWriter, r *http.Request) {
 	err := s.dbClient.DropTable(&db.DropTableInfo{
 		Keyspace: keyspaceName,
 		Table:    tableName,
-	}, newDbOptions(user))
+	}, newAuditedDbOptions(r, user))
 
 	if err != nil {
 		msg := "unable to execute drop table query"
@@ -699,34 +1083,662 @@ func (s *routeList) DeleteTable(w http.ResponseWriter, r *http.Request) {
 	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
 }
 
-func (s *routeList) GetKeyspaces(w http.ResponseWriter, r *http.Request) {
+func (s *routeList) GetIndexes(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
 	user := auth.ContextUserOrRole(r.Context())
 
-	keyspaces, err := s.dbClient.Keyspaces(user)
+	table, err := s.dbClient.DescribeTable(keyspaceName, tableName, user)
 	if err != nil {
-		msg := "unable to describe keyspaces"
-		s.logger.Error(msg, "error", err)
+		msg := "unable to describe table"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+
+		switch err.(type) {
+		case *e.NotFoundError:
+			RespondWithError(w, msg, http.StatusNotFound)
+			return
+		default:
+			RespondWithError(w, msg, http.StatusInternalServerError)
+			return
+		}
+	}
 
+	indexes, err := s.dbClient.Indexes(keyspaceName)
+	if err != nil {
+		msg := "unable to retrieve indexes"
+		s.logger.Error(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
 		RespondWithError(w, msg, http.StatusInternalServerError)
 		return
 	}
 
-	var result []string
-	if s.singleKeyspace != "" {
-		if !lookup(keyspaces, s.singleKeyspace) {
-			// The single keyspace was not found, maybe it's added later
-			RespondWithError(w, "Keyspace not found", http.StatusNotFound)
-			return
+	result := make([]m.Index, 0, len(indexes[tableName]))
+	for _, index := range indexes[tableName] {
+		if !s.exposure.ColumnExposed(table, index.Column) {
+			// Indexes on hidden columns are not listed
+			continue
 		}
+		result = append(result, m.Index{
+			Name:       index.Name,
+			ColumnName: index.Column,
+			Target:     strings.ToLower(index.Target),
+			Type:       index.Class,
+		})
+	}
 
-		// Only list the configured keyspace
-		result = []string{s.singleKeyspace}
-	} else {
-		// Filter out excluded keyspaces
-		result = make([]string, 0, len(keyspaces))
-		for _, ks := range keyspaces {
+	RespondJSONObjectWithCode(w, http.StatusOK, result)
+}
+
+func (s *routeList) AddIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var indexAdd m.IndexAdd
+	if err := parseAndValidatePayload(&indexAdd, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	err := s.dbClient.CreateIndex(&db.CreateIndexInfo{
+		Keyspace:    keyspaceName,
+		Table:       tableName,
+		Name:        indexAdd.Name,
+		Column:      indexAdd.ColumnName,
+		Target:      indexAdd.Target,
+		Class:       indexAdd.Type,
+		IfNotExists: indexAdd.IfNotExists,
+	}, newAuditedDbOptions(r, user))
+
+	if err != nil {
+		msg := "unable to execute create index query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusCreated, m.IndexesResponse{Success: true})
+}
+
+func (s *routeList) DeleteIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
+	indexName := s.params(r, "indexName")
+	user := auth.ContextUserOrRole(r.Context())
+
+	ifExists, err := boolParam(r, "ifExists")
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	indexes, err := s.dbClient.Indexes(keyspaceName)
+	if err != nil {
+		msg := "unable to retrieve indexes"
+		s.logger.Error(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	// Index names are unique per keyspace, the index is only dropped when it belongs to the table in the path
+	if indexTable := findIndexTable(indexes, indexName); indexTable != tableName {
+		if indexTable == "" && ifExists {
+			RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+			return
+		}
+		RespondWithError(w, fmt.Sprintf("Index '%s' not found in table '%s'", indexName, tableName),
+			http.StatusNotFound)
+		return
+	}
+
+	err = s.dbClient.DropIndex(&db.DropIndexInfo{
+		Keyspace: keyspaceName,
+		Name:     indexName,
+		IfExists: ifExists,
+	}, newAuditedDbOptions(r, user))
+
+	if err != nil {
+		msg := "unable to execute drop index query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "index", indexName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+}
+
+// findIndexTable returns the name of the table the index belongs to, empty when the index doesn't exist
+func findIndexTable(indexes map[string][]*db.IndexMetadata, indexName string) string {
+	for tableName, tableIndexes := range indexes {
+		for _, index := range tableIndexes {
+			if index.Name == indexName {
+				return tableName
+			}
+		}
+	}
+	return ""
+}
+
+func (s *routeList) AddView(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var viewAdd m.ViewAdd
+	if err := parseAndValidatePayload(&viewAdd, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	tblMetadata, err := s.dbClient.Table(keyspaceName, tableName)
+	if err != nil {
+		if _, ok := err.(*db.DbObjectNotFound); ok {
+			RespondWithError(w, fmt.Sprintf(`Table "%s"."%s" not found`, keyspaceName, tableName), http.StatusNotFound)
+			return
+		}
+
+		msg := "Unable to get table metadata"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	where, err := viewConditions(viewAdd.Where)
+	if err == nil {
+		err = db.ValidateViewWhere(tblMetadata, where)
+	}
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	viewInfo := db.CreateViewInfo{
+		Keyspace:      keyspaceName,
+		Name:          viewAdd.Name,
+		BaseTable:     tableName,
+		Columns:       viewAdd.ColumnNames,
+		PartitionKeys: viewAdd.PrimaryKey.PartitionKey,
+		Where:         where,
+		IfNotExists:   viewAdd.IfNotExists,
+	}
+
+	for _, name := range viewAdd.PrimaryKey.ClusteringKey {
+		key := db.ColumnOrder{Column: name}
+		for _, ck := range viewAdd.ClusteringExpression {
+			if ck.Column != nil && ck.Order != nil && *ck.Column == name {
+				key.Order = *ck.Order
+				break
+			}
+		}
+		viewInfo.ClusteringKeys = append(viewInfo.ClusteringKeys, key)
+	}
+
+	err = s.dbClient.CreateView(&viewInfo, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute create view query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusCreated, m.ViewsResponse{Success: true})
+}
+
+func (s *routeList) DeleteView(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
+	viewName := s.params(r, "viewName")
+	user := auth.ContextUserOrRole(r.Context())
+
+	ifExists, err := boolParam(r, "ifExists")
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	views, err := s.dbClient.DescribeViews(keyspaceName)
+	if err != nil {
+		msg := "unable to retrieve views"
+		s.logger.Error(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	// The view is only dropped when its base table is the table in the path
+	baseTable := ""
+	for _, view := range views {
+		if view.Name == viewName {
+			baseTable = view.BaseTable
+			break
+		}
+	}
+	if baseTable != tableName {
+		if baseTable == "" && ifExists {
+			RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+			return
+		}
+		RespondWithError(w, fmt.Sprintf("View '%s' not found in table '%s'", viewName, tableName),
+			http.StatusNotFound)
+		return
+	}
+
+	err = s.dbClient.DropView(&db.DropViewInfo{
+		Keyspace: keyspaceName,
+		Name:     viewName,
+		IfExists: ifExists,
+	}, newAuditedDbOptions(r, user))
+
+	if err != nil {
+		msg := "unable to execute drop view query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "view", viewName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+}
+
+func (s *routeList) GetUserTypes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+
+	types, err := s.dbClient.UserTypes(keyspaceName)
+	if err != nil {
+		if _, ok := err.(*db.DbObjectNotFound); ok {
+			RespondWithError(w, fmt.Sprintf("Keyspace '%s' not found", keyspaceName), http.StatusNotFound)
+			return
+		}
+		msg := "unable to retrieve user-defined types"
+		s.logger.Error(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]m.UserType, 0, len(types))
+	for _, udt := range types {
+		result = append(result, userTypeMetadataToUserType(udt))
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, result)
+}
+
+func (s *routeList) GetUserType(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	typeName := s.params(r, typeParam)
+
+	types, err := s.dbClient.UserTypes(keyspaceName)
+	if err != nil {
+		if _, ok := err.(*db.DbObjectNotFound); ok {
+			RespondWithError(w, fmt.Sprintf("Keyspace '%s' not found", keyspaceName), http.StatusNotFound)
+			return
+		}
+		msg := "unable to retrieve user-defined types"
+		s.logger.Error(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	for _, udt := range types {
+		if udt.Name == typeName {
+			RespondJSONObjectWithCode(w, http.StatusOK, userTypeMetadataToUserType(udt))
+			return
+		}
+	}
+
+	RespondWithError(w, fmt.Sprintf("Type '%s' not found", typeName), http.StatusNotFound)
+}
+
+func (s *routeList) AddUserType(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var typeAdd m.UserTypeAdd
+	if err := parseAndValidatePayload(&typeAdd, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	fields, err := userTypeFields(typeAdd.FieldDefinitions)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.dbClient.CreateType(&db.CreateTypeInfo{
+		Keyspace:    keyspaceName,
+		Name:        typeAdd.Name,
+		Fields:      fields,
+		IfNotExists: typeAdd.IfNotExists,
+	}, newAuditedDbOptions(r, user))
+
+	if err != nil {
+		msg := "unable to execute create type query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusCreated, m.UserTypesResponse{Success: true})
+}
+
+func (s *routeList) AddUserTypeField(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	typeName := s.params(r, typeParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var fieldDefinition m.ColumnDefinition
+	if err := parseAndValidatePayload(&fieldDefinition, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "type", typeName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	fields, err := userTypeFields([]m.ColumnDefinition{fieldDefinition})
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.dbClient.AlterTypeAdd(&db.AlterTypeAddInfo{
+		Keyspace: keyspaceName,
+		Name:     typeName,
+		ToAdd:    fields,
+	}, newAuditedDbOptions(r, user))
+
+	if err != nil {
+		msg := "unable to execute alter type query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "type", typeName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusCreated, m.UserTypesResponse{Success: true})
+}
+
+func (s *routeList) DeleteUserType(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	typeName := s.params(r, typeParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	ifExists, err := boolParam(r, "ifExists")
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.dbClient.DropType(&db.DropTypeInfo{
+		Keyspace: keyspaceName,
+		Name:     typeName,
+		IfExists: ifExists,
+	}, newAuditedDbOptions(r, user))
+
+	if err != nil {
+		msg := "unable to execute drop type query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "type", typeName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+}
+
+func (s *routeList) GetKeyspace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	keyspace, err := s.dbClient.Keyspace(keyspaceName)
+	if err != nil {
+		if _, ok := err.(*db.DbObjectNotFound); ok {
+			RespondWithError(w, fmt.Sprintf("Keyspace '%s' not found", keyspaceName), http.StatusNotFound)
+			return
+		}
+		msg := "error retrieving the keyspace"
+		s.logger.Error(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	tables, err := s.dbClient.DescribeTables(keyspaceName, user)
+	if err != nil {
+		msg := "unable to describe tables"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	tables = s.exposedTables(keyspaceName, tables)
+	RespondJSONObjectWithCode(w, http.StatusOK, keyspaceMetadataToKeyspace(keyspace, tables))
+}
+
+// exposedTables filters out the tables that are not exposed
+func (s *routeList) exposedTables(keyspaceName string, tables []string) []string {
+	result := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if s.exposure.TableExposed(keyspaceName, table) {
+			result = append(result, table)
+		}
+	}
+	return result
+}
+
+func (s *routeList) AddKeyspace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	user := auth.ContextUserOrRole(r.Context())
+
+	var keyspaceAdd m.KeyspaceAdd
+	if err := parseAndValidatePayload(&keyspaceAdd, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if s.singleKeyspace != "" || s.excludedKeyspaces[keyspaceAdd.Name] {
+		RespondWithKeyspaceNotAllowed(w)
+		return
+	}
+
+	if len(keyspaceAdd.DataCenters) > 0 && keyspaceAdd.ReplicationFactor > 0 {
+		RespondWithError(w, "data centers and replication factor can not be used together", http.StatusBadRequest)
+		return
+	}
+
+	if len(keyspaceAdd.DataCenters) == 0 && keyspaceAdd.ReplicationFactor == 0 {
+		RespondWithError(w, "data centers or a replication factor must be provided", http.StatusBadRequest)
+		return
+	}
+
+	err := s.dbClient.CreateKeyspace(&db.CreateKeyspaceInfo{
+		Name:              keyspaceAdd.Name,
+		DCReplicas:        dcReplicas(keyspaceAdd.DataCenters),
+		ReplicationFactor: keyspaceAdd.ReplicationFactor,
+		DurableWrites:     keyspaceAdd.DurableWrites,
+		IfNotExists:       keyspaceAdd.IfNotExists,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute create keyspace query"
+		s.logger.Debug(msg, "keyspace", keyspaceAdd.Name, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusCreated, m.KeyspacesResponse{Success: true})
+}
+
+func (s *routeList) DeleteKeyspace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	if s.singleKeyspace != "" {
+		RespondWithKeyspaceNotAllowed(w)
+		return
+	}
+
+	ifExists, err := boolParam(r, "ifExists")
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.dbClient.DropKeyspace(&db.DropKeyspaceInfo{
+		Name:     keyspaceName,
+		IfExists: ifExists,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute drop keyspace query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+}
+
+func (s *routeList) UpdateKeyspace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var keyspaceUpdate m.KeyspaceUpdate
+	if err := parseAndValidatePayload(&keyspaceUpdate, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if len(keyspaceUpdate.DataCenters) > 0 && keyspaceUpdate.ReplicationFactor > 0 {
+		RespondWithError(w, "data centers and replication factor can not be used together", http.StatusBadRequest)
+		return
+	}
+
+	if len(keyspaceUpdate.DataCenters) == 0 && keyspaceUpdate.ReplicationFactor == 0 &&
+		keyspaceUpdate.DurableWrites == nil {
+		RespondWithError(w, "replication or durable writes must be provided", http.StatusBadRequest)
+		return
+	}
+
+	err := s.dbClient.AlterKeyspace(&db.AlterKeyspaceInfo{
+		Name:              keyspaceName,
+		DCReplicas:        dcReplicas(keyspaceUpdate.DataCenters),
+		ReplicationFactor: keyspaceUpdate.ReplicationFactor,
+		DurableWrites:     keyspaceUpdate.DurableWrites,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute alter keyspace query"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, m.KeyspacesResponse{Success: true})
+}
+
+func keyspaceMetadataToKeyspace(keyspace *gocql.KeyspaceMetadata, tables []string) m.Keyspace {
+	result := m.Keyspace{
+		Name:                keyspace.Name,
+		ReplicationStrategy: keyspace.StrategyClass[strings.LastIndex(keyspace.StrategyClass, ".")+1:],
+		DurableWrites:       keyspace.DurableWrites,
+		Tables:              tables,
+		Types:               make([]m.UserType, 0, len(keyspace.Views)),
+	}
+
+	names := make([]string, 0, len(keyspace.StrategyOptions))
+	for name := range keyspace.StrategyOptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		replicas, err := strconv.Atoi(fmt.Sprint(keyspace.StrategyOptions[name]))
+		if err != nil {
+			continue
+		}
+		if name == "replication_factor" {
+			result.ReplicationFactor = &replicas
+		} else {
+			result.DataCenters = append(result.DataCenters, m.DataCenter{Name: name, Replicas: replicas})
+		}
+	}
+
+	for _, udt := range db.UserTypesFromKeyspace(keyspace) {
+		result.Types = append(result.Types, userTypeMetadataToUserType(udt))
+	}
+
+	return result
+}
+
+func dcReplicas(dataCenters []m.DataCenter) map[string]int {
+	if len(dataCenters) == 0 {
+		return nil
+	}
+
+	result := make(map[string]int, len(dataCenters))
+	for _, dc := range dataCenters {
+		result[dc.Name] = dc.Replicas
+	}
+	return result
+}
+
+func (s *routeList) GetKeyspaces(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	user := auth.ContextUserOrRole(r.Context())
+
+	keyspaces, err := s.dbClient.Keyspaces(user)
+	if err != nil {
+		msg := "unable to describe keyspaces"
+		s.logger.Error(msg, "error", err)
+
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	var result []string
+	if s.singleKeyspace != "" {
+		if !lookup(keyspaces, s.singleKeyspace) {
+			// The single keyspace was not found, maybe it's added later
+			RespondWithError(w, "Keyspace not found", http.StatusNotFound)
+			return
+		}
+
+		// Only list the configured keyspace
+		result = []string{s.singleKeyspace}
+	} else {
+		// Filter out excluded keyspaces
+		result = make([]string, 0, len(keyspaces))
+		for _, ks := range keyspaces {
 			if s.excludedKeyspaces[ks] {
 				continue
 			}
@@ -737,7 +1749,8 @@ func (s *routeList) GetKeyspaces(w http.ResponseWriter, r *http.Request) {
 	RespondJSONObjectWithCode(w, http.StatusOK, result)
 }
 
-func tableMetadataToTable(tableMetadata *gocql.TableMetadata) interface{} {
+func tableMetadataToTable(
+	tableMetadata *gocql.TableMetadata, options *db.TableOptions, views []*db.ViewMetadata) interface{} {
 	partitionKeys := make([]string, 0)
 	for _, key := range tableMetadata.PartitionKey {
 		partitionKeys = append(partitionKeys, key.Name)
@@ -759,9 +1772,17 @@ func tableMetadataToTable(tableMetadata *gocql.TableMetadata) interface{} {
 	}
 
 	tableOptions := &m.TableOptions{
-		DefaultTimeToLive:    nil,
 		ClusteringExpression: clusteringExpression,
 	}
+	if options != nil {
+		tableOptions.DefaultTimeToLive = toInt32Ptr(options.DefaultTimeToLive)
+		tableOptions.GcGraceSeconds = toInt32Ptr(options.GcGraceSeconds)
+		tableOptions.BloomFilterFpChance = options.BloomFilterFpChance
+		tableOptions.Comment = options.Comment
+		tableOptions.Compaction = options.Compaction
+		tableOptions.Compression = options.Compression
+		tableOptions.Caching = options.Caching
+	}
 
 	columnDefinitions := columnMetadataToColumnDefinition(tableMetadata.Columns)
 
@@ -771,10 +1792,78 @@ func tableMetadataToTable(tableMetadata *gocql.TableMetadata) interface{} {
 		ColumnDefinitions: columnDefinitions,
 		PrimaryKey:        primaryKey,
 		TableOptions:      tableOptions,
+		Views:             viewsMetadataToViews(tableMetadata.Name, views),
 	}
 	return table
 }
 
+// toDbTableOptions converts the table properties, the clustering expression is handled by the primary key definition
+func toDbTableOptions(options *m.TableOptions) *db.TableOptions {
+	if options == nil {
+		return nil
+	}
+
+	return &db.TableOptions{
+		DefaultTimeToLive:   toIntPtr(options.DefaultTimeToLive),
+		GcGraceSeconds:      toIntPtr(options.GcGraceSeconds),
+		BloomFilterFpChance: options.BloomFilterFpChance,
+		Comment:             options.Comment,
+		Compaction:          options.Compaction,
+		Compression:         options.Compression,
+		Caching:             options.Caching,
+	}
+}
+
+func toIntPtr(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	result := int(*value)
+	return &result
+}
+
+func toInt32Ptr(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+	result := int32(*value)
+	return &result
+}
+
+func viewsMetadataToViews(tableName string, views []*db.ViewMetadata) []m.View {
+	result := make([]m.View, 0)
+	for _, view := range views {
+		if view.BaseTable != tableName {
+			continue
+		}
+
+		clusteringKeys := make([]string, 0, len(view.ClusteringKeys))
+		clusteringExpression := make([]m.ClusteringExpression, 0, len(view.ClusteringKeys))
+		for _, key := range view.ClusteringKeys {
+			column := key.Column
+			order := strings.ToLower(key.Order)
+			clusteringKeys = append(clusteringKeys, column)
+			clusteringExpression = append(clusteringExpression, m.ClusteringExpression{
+				Column: &column,
+				Order:  &order,
+			})
+		}
+
+		result = append(result, m.View{
+			Name:        view.Name,
+			ColumnNames: view.Columns,
+			PrimaryKey: &m.PrimaryKey{
+				PartitionKey:  view.PartitionKeys,
+				ClusteringKey: clusteringKeys,
+			},
+			ClusteringExpression: clusteringExpression,
+			WhereClause:          view.WhereClause,
+			IncludeAllColumns:    view.IncludeAllColumns,
+		})
+	}
+	return result
+}
+
 func columnMetadataToColumnDefinition(columns map[string]*gocql.ColumnMetadata) []m.ColumnDefinition {
 	columnDefinitions := make([]m.ColumnDefinition, 0)
 	for _, col := range columns {
@@ -792,6 +1881,34 @@ func columnMetadataToColumnDefinition(columns map[string]*gocql.ColumnMetadata)
 	return columnDefinitions
 }
 
+func userTypeFields(definitions []m.ColumnDefinition) ([]*gocql.ColumnMetadata, error) {
+	fields := make([]*gocql.ColumnMetadata, 0, len(definitions))
+	for _, definition := range definitions {
+		field, err := m.ToDbColumn(definition)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func userTypeMetadataToUserType(udt *db.UserTypeMetadata) m.UserType {
+	fieldDefinitions := make([]m.ColumnDefinition, 0, len(udt.Fields))
+	for _, field := range udt.Fields {
+		fieldDefinitions = append(fieldDefinitions, m.ColumnDefinition{
+			Name:           field.Name,
+			TypeDefinition: field.Type.Type().String(),
+		})
+	}
+
+	return m.UserType{
+		Name:             udt.Name,
+		Keyspace:         udt.Keyspace,
+		FieldDefinitions: fieldDefinitions,
+	}
+}
+
 func parseAndValidatePayload(obj interface{}, r *http.Request) error {
 	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
 		return err
@@ -840,6 +1957,54 @@ func primaryKeyValues(identifier string, tblMetadata *gocql.TableMetadata) ([]st
 	return columns, values, nil
 }
 
+// publish sends the change event of a successful mutation to the broker and the event sinks
+func (s *routeList) publish(event *events.Event) {
+	if s.broker != nil {
+		s.broker.Publish(event)
+	}
+}
+
+// columnAccess returns the access of the request user or role to the columns of the table, the columns that are not
+// exposed are denied. It returns nil when it's not restricted.
+func (s *routeList) columnAccess(r *http.Request, table *gocql.TableMetadata) *config.ColumnAccess {
+	return s.columnPolicies.Access(table, auth.ContextUserOrRole(r.Context())).
+		WithDenied(table.Name, s.exposure.HiddenColumns(table))
+}
+
+// rowAccess returns the partitions of the table that can be accessed with the claims of the request identity, nil
+// when it's not restricted
+func (s *routeList) rowAccess(r *http.Request, table *gocql.TableMetadata) (*config.RowAccess, error) {
+	return s.rowPolicies.Access(table, auth.ContextClaims(r.Context()))
+}
+
+// restrictRows returns the conditions including the partition key conditions of the row policy of the table
+func (s *routeList) restrictRows(
+	r *http.Request, table *gocql.TableMetadata, where []types.ConditionItem,
+) ([]types.ConditionItem, error) {
+	access, err := s.rowAccess(r, table)
+	if err != nil {
+		return nil, err
+	}
+	return access.Restrict(where)
+}
+
+// checkRowAccess verifies that the values of a mutation belong to a partition that can be accessed
+func (s *routeList) checkRowAccess(
+	r *http.Request, table *gocql.TableMetadata, columns []string, values []interface{},
+) error {
+	access, err := s.rowAccess(r, table)
+	if err != nil {
+		return err
+	}
+	return access.Check(columns, values)
+}
+
+// newAuditedDbOptions returns the options of the mutations and schema changes, the request context marks the statements
+// as part of the audited operation
+func newAuditedDbOptions(r *http.Request, user string) *db.QueryOptions {
+	return newDbOptions(user).WithContext(r.Context())
+}
+
 func newDbOptions(user string) *db.QueryOptions {
 	return db.NewQueryOptions().
 		WithUserOrRole(user).
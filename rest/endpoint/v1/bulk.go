@@ -0,0 +1,233 @@
+package endpoint
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/auth"
+	"github.com/datastax/cassandra-data-apis/bulk"
+	"github.com/datastax/cassandra-data-apis/db"
+	m "github.com/datastax/cassandra-data-apis/rest/models"
+	"github.com/gocql/gocql"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxExportConcurrency = 32
+	maxImportConcurrency = 32
+	maxImportBatchSize   = 1000
+)
+
+// ExportRows streams all the rows of a table as NDJSON or CSV
+func (s *routeList) ExportRows(w http.ResponseWriter, r *http.Request) {
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	options, err := exportOptions(r)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	options.UserOrRole = user
+
+	tblMetadata, err := s.dbClient.Table(keyspaceName, tableName)
+	if err != nil {
+		if _, ok := err.(*db.DbObjectNotFound); ok {
+			RespondWithError(w, fmt.Sprintf(`Table "%s"."%s" not found`, keyspaceName, tableName), http.StatusNotFound)
+			return
+		}
+
+		msg := "Unable to get table metadata"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	options.Access = s.columnAccess(r, tblMetadata)
+	if options.RowAccess, err = s.rowAccess(r, tblMetadata); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	// Error responses override the content type
+	w.Header().Set("Content-Type", options.Format.ContentType())
+	sw := &streamWriter{w: w}
+	count, err := bulk.NewExporter(s.dbClient).Export(r.Context(), keyspaceName, tblMetadata, sw, options)
+	if err != nil {
+		msg := "unable to export table"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "rows", count, "error", err)
+		if !sw.written {
+			RespondWithError(w, msg, http.StatusInternalServerError)
+		}
+		// The response can not be changed once the rows started streaming, the client gets a truncated body
+	}
+}
+
+// ImportRows inserts the rows contained in a NDJSON or CSV body
+func (s *routeList) ImportRows(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	keyspaceName := s.params(r, keyspaceParam)
+	tableName := s.params(r, tableParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	options, err := importOptions(r)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	options.UserOrRole = user
+
+	tblMetadata, err := s.dbClient.Table(keyspaceName, tableName)
+	if err != nil {
+		if _, ok := err.(*db.DbObjectNotFound); ok {
+			RespondWithError(w, fmt.Sprintf(`Table "%s"."%s" not found`, keyspaceName, tableName), http.StatusNotFound)
+			return
+		}
+
+		msg := "Unable to get table metadata"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	options.Access = s.columnAccess(r, tblMetadata)
+	if options.RowAccess, err = s.rowAccess(r, tblMetadata); err != nil {
+		RespondWithError(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	result, err := bulk.NewImporter(s.dbClient).Import(r.Context(), keyspaceName, tblMetadata, r.Body, options)
+	if err != nil && result == nil {
+		msg := "unable to read import body"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		RespondWithError(w, fmt.Sprintf("%s: %s", msg, err), http.StatusBadRequest)
+		return
+	}
+
+	response := m.RowsImportResponse{
+		Inserted: result.Inserted,
+		Failed:   result.Failed,
+		Errors:   make([]m.RowImportError, len(result.Errors)),
+	}
+	for i, importErr := range result.Errors {
+		response.Errors[i] = m.RowImportError{Line: importErr.Line, Message: importErr.Message}
+	}
+
+	code := http.StatusOK
+	if err != nil {
+		// The body failed to be read partway through, the rows read before the failure were already imported
+		msg := "unable to read import body"
+		s.logger.Debug(msg, "keyspace", keyspaceName, "table", tableName, "error", err)
+		response.Error = fmt.Sprintf("%s: %s", msg, err)
+		code = http.StatusBadRequest
+	}
+
+	RespondJSONObjectWithCode(w, code, response)
+}
+
+func exportOptions(r *http.Request) (*bulk.ExportOptions, error) {
+	query := r.URL.Query()
+
+	format, err := bulk.ParseFormat(query.Get("format"))
+	if err != nil {
+		return nil, err
+	}
+
+	options := &bulk.ExportOptions{
+		Format:      format,
+		Concurrency: bulk.DefaultConcurrency,
+		Consistency: gocql.LocalQuorum,
+	}
+
+	if value := query.Get("concurrency"); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 || concurrency > maxExportConcurrency {
+			return nil, fmt.Errorf("concurrency must be a number between 1 and %d", maxExportConcurrency)
+		}
+		options.Concurrency = concurrency
+	}
+
+	if value := query.Get("consistency"); value != "" {
+		consistency, err := gocql.ParseConsistencyWrapper(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consistency level '%s'", value)
+		}
+		options.Consistency = consistency
+	}
+
+	return options, nil
+}
+
+func importOptions(r *http.Request) (*bulk.ImportOptions, error) {
+	query := r.URL.Query()
+
+	formatName := query.Get("format")
+	if formatName == "" && strings.HasPrefix(r.Header.Get("Content-Type"), bulk.CSV.ContentType()) {
+		formatName = bulk.CSV.String()
+	}
+
+	format, err := bulk.ParseFormat(formatName)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &bulk.ImportOptions{
+		Format:      format,
+		Concurrency: bulk.DefaultConcurrency,
+		Consistency: gocql.LocalQuorum,
+	}
+
+	if value := query.Get("concurrency"); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 || concurrency > maxImportConcurrency {
+			return nil, fmt.Errorf("concurrency must be a number between 1 and %d", maxImportConcurrency)
+		}
+		options.Concurrency = concurrency
+	}
+
+	if value := query.Get("consistency"); value != "" {
+		consistency, err := gocql.ParseConsistencyWrapper(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consistency level '%s'", value)
+		}
+		options.Consistency = consistency
+	}
+
+	if value := query.Get("batchByPartition"); value != "" {
+		batchByPartition, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batchByPartition value '%s'", value)
+		}
+		options.BatchByPartition = batchByPartition
+	}
+
+	if value := query.Get("batchSize"); value != "" {
+		batchSize, err := strconv.Atoi(value)
+		if err != nil || batchSize < 1 || batchSize > maxImportBatchSize {
+			return nil, fmt.Errorf("batchSize must be a number between 1 and %d", maxImportBatchSize)
+		}
+		options.BatchSize = batchSize
+	}
+
+	return options, nil
+}
+
+// streamWriter tracks whether the body started being written, allowing to respond with an error status code when
+// it fails before writing any data
+type streamWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.written = true
+	return s.w.Write(p)
+}
+
+func (s *streamWriter) Flush() {
+	if f, ok := s.w.(http.Flusher); ok && s.written {
+		f.Flush()
+	}
+}
@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/auth"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/migration"
+	m "github.com/datastax/cassandra-data-apis/rest/models"
+	"net/http"
+)
+
+// PlanMigration returns the statements needed to change the schema into the state described by the body
+func (s *routeList) PlanMigration(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	plan, ok := s.migrationPlan(w, r)
+	if !ok {
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, planToMigrationPlan(plan))
+}
+
+// ApplyMigration changes the schema into the state described by the body, destructive statements are only executed
+// when the allowDestructive parameter is set
+func (s *routeList) ApplyMigration(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	allowDestructive, err := boolParam(r, "allowDestructive")
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, ok := s.migrationPlan(w, r)
+	if !ok {
+		return
+	}
+
+	applied, err := migration.NewMigrator(s.dbClient).Apply(r.Context(), plan, &migration.ApplyOptions{
+		AllowDestructive: allowDestructive,
+		UserOrRole:       auth.ContextUserOrRole(r.Context()),
+	})
+	if err == migration.ErrDestructive {
+		RespondWithError(w, "the plan contains destructive statements, use allowDestructive=true to apply it",
+			http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		msg := "unable to apply migration"
+		s.logger.Debug(msg, "applied", applied, "error", err)
+		RespondWithError(w, fmt.Sprintf("%s, %d statements applied", msg, applied), http.StatusInternalServerError)
+		return
+	}
+
+	result := planToMigrationPlan(plan)
+	result.Applied = &applied
+	RespondJSONObjectWithCode(w, http.StatusOK, result)
+}
+
+// migrationPlan parses the schema document and returns the plan, it responds with an error when it's not valid
+func (s *routeList) migrationPlan(w http.ResponseWriter, r *http.Request) (*migration.Plan, bool) {
+	schema, err := migration.ParseSchema(r.Body)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	systemKeyspaces := make(map[string]bool, len(config.SystemKeyspaces))
+	for _, ks := range config.SystemKeyspaces {
+		systemKeyspaces[ks] = true
+	}
+
+	for _, ks := range schema.Keyspaces {
+		if (s.singleKeyspace != "" && s.singleKeyspace != ks.Name) || s.excludedKeyspaces[ks.Name] ||
+			systemKeyspaces[ks.Name] {
+			RespondWithKeyspaceNotAllowed(w)
+			return nil, false
+		}
+	}
+
+	plan, err := migration.NewMigrator(s.dbClient).Plan(schema)
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	return plan, true
+}
+
+func planToMigrationPlan(plan *migration.Plan) m.MigrationPlan {
+	statements := make([]m.MigrationStatement, 0, len(plan.Statements))
+	for _, statement := range plan.Statements {
+		statements = append(statements, m.MigrationStatement{
+			Query:       statement.Query,
+			Destructive: statement.Destructive,
+		})
+	}
+
+	return m.MigrationPlan{
+		Statements:  statements,
+		Destructive: plan.Destructive(),
+	}
+}
@@ -0,0 +1,177 @@
+package endpoint
+
+import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	m "github.com/datastax/cassandra-data-apis/rest/models"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// writeOptions returns the options for a write operation, reading the consistency levels from the query parameters
+func writeOptions(r *http.Request, user string) (*db.QueryOptions, error) {
+	options := newAuditedDbOptions(r, user)
+	query := r.URL.Query()
+
+	if value := query.Get("consistency"); value != "" {
+		consistency, err := gocql.ParseConsistencyWrapper(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consistency level '%s'", value)
+		}
+		options.WithConsistency(consistency)
+	}
+
+	if value := query.Get("serialConsistency"); value != "" {
+		var serialConsistency gocql.SerialConsistency
+		if err := serialConsistency.UnmarshalText([]byte(strings.ToUpper(value))); err != nil {
+			return nil, fmt.Errorf("invalid serial consistency level '%s'", value)
+		}
+		options.WithSerialConsistency(serialConsistency)
+	}
+
+	return options, nil
+}
+
+// conditionColumns returns the columns used in the conditions
+func conditionColumns(conditions []types.ConditionItem) []string {
+	columns := make([]string, 0, len(conditions))
+	for _, item := range conditions {
+		columns = append(columns, item.Column)
+	}
+	return columns
+}
+
+// ttlValue returns the TTL to use in the query, -1 represents the table default time-to-live
+func ttlValue(ttl *int) (int, error) {
+	if ttl == nil {
+		return -1, nil
+	}
+
+	if *ttl < 0 {
+		return 0, fmt.Errorf("invalid ttl %d", *ttl)
+	}
+
+	return *ttl, nil
+}
+
+// boolParam returns the value of a boolean query parameter, false when not set
+func boolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for '%s'", name)
+	}
+
+	return result, nil
+}
+
+// conditions converts the lightweight transaction conditions, converting the values to the column types
+func conditions(items []m.Condition, table *gocql.TableMetadata) ([]types.ConditionItem, error) {
+	result := make([]types.ConditionItem, 0, len(items))
+	for _, item := range items {
+		column, ok := table.Columns[item.ColumnName]
+		if !ok {
+			return nil, fmt.Errorf("column '%s' not found", item.ColumnName)
+		}
+
+		operator, ok := types.CqlOperators[item.Operator]
+		if !ok {
+			return nil, fmt.Errorf("operator '%s' not found", item.Operator)
+		}
+
+		var value interface{}
+		var err error
+		if operator == "IN" {
+			list, isList := item.Value.([]interface{})
+			if !isList {
+				return nil, fmt.Errorf("value for operator 'in' must be a list")
+			}
+			values := make([]interface{}, len(list))
+			for i, element := range list {
+				if values[i], err = types.FromJsonValue(element, column.Type); err != nil {
+					break
+				}
+			}
+			value = values
+		} else {
+			value, err = types.FromJsonValue(item.Value, column.Type)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("wrong type provided for column '%s'", item.ColumnName)
+		}
+
+		result = append(result, types.ConditionItem{
+			Column:   item.ColumnName,
+			Operator: operator,
+			Value:    value,
+		})
+	}
+
+	return result, nil
+}
+
+// viewConditions converts the restrictions of a view, the values are validated against the column types when the
+// view is created
+func viewConditions(items []m.Condition) ([]types.ConditionItem, error) {
+	result := make([]types.ConditionItem, 0, len(items))
+	for _, item := range items {
+		operator, ok := types.CqlOperators[item.Operator]
+		if !ok {
+			return nil, fmt.Errorf("operator '%s' not found", item.Operator)
+		}
+
+		result = append(result, types.ConditionItem{
+			Column:   item.ColumnName,
+			Operator: operator,
+			Value:    item.Value,
+		})
+	}
+
+	return result, nil
+}
+
+// modificationResponse returns the response of a write operation, including whether it was applied and the current
+// values of the row for conditional operations
+func modificationResponse(
+	rs db.ResultSet, table *gocql.TableMetadata, conditional bool, access *config.ColumnAccess,
+) *m.RowsResponse {
+	if !conditional {
+		return &m.RowsResponse{
+			Success:      true,
+			RowsModified: 1,
+		}
+	}
+
+	applied := true
+	var value map[string]interface{}
+	if rows := rs.Values(); len(rows) > 0 {
+		row := rows[0]
+		appliedValue, _ := row["[applied]"].(*bool)
+		applied = appliedValue != nil && *appliedValue
+		if !applied && len(row) > 1 {
+			value = types.ToJsonRow(access.ApplyToRow(row), table)
+			delete(value, "[applied]")
+		}
+	}
+
+	response := &m.RowsResponse{
+		Success: true,
+		Applied: &applied,
+		Value:   value,
+	}
+
+	if applied {
+		response.RowsModified = 1
+	}
+
+	return response
+}
@@ -0,0 +1,265 @@
+package endpoint
+
+import (
+	"github.com/datastax/cassandra-data-apis/auth"
+	"github.com/datastax/cassandra-data-apis/db"
+	m "github.com/datastax/cassandra-data-apis/rest/models"
+	"net/http"
+	"strings"
+)
+
+// validateSuperuser only allows the request when it's made using the configured superuser role
+func (s *routeList) validateSuperuser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := auth.ContextUserOrRole(r.Context())
+		if s.superuserRole == "" || user != s.superuserRole {
+			RespondWithError(w, "role management is only allowed for the superuser role", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *routeList) GetRoles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	user := auth.ContextUserOrRole(r.Context())
+
+	roles, err := s.dbClient.Roles(newDbOptions(user))
+	if err != nil {
+		msg := "unable to retrieve roles"
+		s.logger.Error(msg, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]m.Role, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, roleMetadataToRole(role))
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, result)
+}
+
+func (s *routeList) GetRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	roleName := s.params(r, roleParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	roles, err := s.dbClient.Roles(newDbOptions(user))
+	if err != nil {
+		msg := "unable to retrieve roles"
+		s.logger.Error(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	for _, role := range roles {
+		if role.Name == roleName {
+			RespondJSONObjectWithCode(w, http.StatusOK, roleMetadataToRole(role))
+			return
+		}
+	}
+
+	RespondWithError(w, "role not found", http.StatusNotFound)
+}
+
+func (s *routeList) AddRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	user := auth.ContextUserOrRole(r.Context())
+
+	var roleAdd m.RoleAdd
+	if err := parseAndValidatePayload(&roleAdd, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	err := s.dbClient.CreateRole(&db.CreateRoleInfo{
+		Name:        roleAdd.Name,
+		Password:    roleAdd.Password,
+		Login:       roleAdd.Login,
+		Superuser:   roleAdd.Superuser,
+		IfNotExists: roleAdd.IfNotExists,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute create role query"
+		s.logger.Debug(msg, "role", roleAdd.Name, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusCreated, m.RolesResponse{Success: true})
+}
+
+func (s *routeList) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	roleName := s.params(r, roleParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var roleUpdate m.RoleUpdate
+	if err := parseAndValidatePayload(&roleUpdate, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if roleUpdate.Password == nil && roleUpdate.Login == nil && roleUpdate.Superuser == nil {
+		RespondWithError(w, "at least one role option must be provided", http.StatusBadRequest)
+		return
+	}
+
+	err := s.dbClient.AlterRole(&db.AlterRoleInfo{
+		Name:      roleName,
+		Password:  roleUpdate.Password,
+		Login:     roleUpdate.Login,
+		Superuser: roleUpdate.Superuser,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute alter role query"
+		s.logger.Debug(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, m.RolesResponse{Success: true})
+}
+
+func (s *routeList) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	roleName := s.params(r, roleParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	ifExists, err := boolParam(r, "ifExists")
+	if err != nil {
+		RespondWithError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.dbClient.DropRole(&db.DropRoleInfo{
+		Name:     roleName,
+		IfExists: ifExists,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute drop role query"
+		s.logger.Debug(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+}
+
+func (s *routeList) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	roleName := s.params(r, roleParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	permissions, err := s.dbClient.Permissions(roleName, newDbOptions(user))
+	if err != nil {
+		msg := "unable to list permissions"
+		s.logger.Debug(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]m.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, m.Permission{
+			Role:       permission.Role,
+			Resource:   permission.Resource,
+			Keyspace:   permission.Keyspace,
+			Table:      permission.Table,
+			Permission: permission.Permission,
+		})
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, result)
+}
+
+func (s *routeList) GrantPermission(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	roleName := s.params(r, roleParam)
+	user := auth.ContextUserOrRole(r.Context())
+
+	var grant m.PermissionGrant
+	if err := parseAndValidatePayload(&grant, r); err != nil {
+		msg := "unable to parse payload"
+		s.logger.Debug(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if grant.Table != "" && grant.Keyspace == "" {
+		RespondWithError(w, "keyspace must be provided when table is set", http.StatusBadRequest)
+		return
+	}
+
+	err := s.dbClient.Grant(&db.PermissionInfo{
+		Role:       roleName,
+		Permission: strings.ToUpper(grant.Permission),
+		Keyspace:   grant.Keyspace,
+		Table:      grant.Table,
+	}, newAuditedDbOptions(r, user))
+	if err != nil {
+		msg := "unable to execute grant query"
+		s.logger.Debug(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusCreated, m.RolesResponse{Success: true})
+}
+
+func (s *routeList) RevokePermission(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	roleName := s.params(r, roleParam)
+	permission := strings.ToUpper(s.params(r, permissionParam))
+	user := auth.ContextUserOrRole(r.Context())
+	query := r.URL.Query()
+
+	info := &db.PermissionInfo{
+		Role:       roleName,
+		Permission: permission,
+		Keyspace:   query.Get("keyspace"),
+		Table:      query.Get("table"),
+	}
+
+	if !db.Permissions[permission] {
+		RespondWithError(w, "invalid permission", http.StatusBadRequest)
+		return
+	}
+
+	if info.Table != "" && info.Keyspace == "" {
+		RespondWithError(w, "keyspace must be provided when table is set", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.dbClient.Revoke(info, newAuditedDbOptions(r, user)); err != nil {
+		msg := "unable to execute revoke query"
+		s.logger.Debug(msg, "role", roleName, "error", err)
+		RespondWithError(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusNoContent, nil)
+}
+
+func roleMetadataToRole(role *db.RoleMetadata) m.Role {
+	return m.Role{
+		Name:      role.Name,
+		Login:     role.Login,
+		Superuser: role.Superuser,
+		MemberOf:  role.MemberOf,
+	}
+}
@@ -1,30 +1,60 @@
 package endpoint
 
 import (
+	"fmt"
+	"github.com/datastax/cassandra-data-apis/audit"
 	"github.com/datastax/cassandra-data-apis/config"
 	"github.com/datastax/cassandra-data-apis/db"
+	"github.com/datastax/cassandra-data-apis/events"
 	"github.com/datastax/cassandra-data-apis/log"
 	"github.com/datastax/cassandra-data-apis/types"
 	"net/http"
 	"path"
+	"strings"
 )
 
 const (
-	keyspaceParam = "keyspaceName"
-	tableParam    = "tableName"
+	keyspaceParam   = "keyspaceName"
+	tableParam      = "tableName"
+	typeParam       = "typeName"
+	roleParam       = "roleName"
+	permissionParam = "permission"
 )
 
 const (
-	KeyspacesPathFormat    = "v1/keyspaces"
-	TablesPathFormat       = "v1/keyspaces/%s/tables"
-	TableSinglePathFormat  = "v1/keyspaces/%s/tables/%s"
-	ColumnsPathFormat      = "v1/keyspaces/%s/tables/%s/columns"
-	ColumnSinglePathFormat = "v1/keyspaces/%s/tables/%s/columns/%s"
-	RowsPathFormat         = "v1/keyspaces/%s/tables/%s/rows"
-	RowSinglePathFormat    = "v1/keyspaces/%s/tables/%s/rows/%s"
-	QueryPathFormat        = "v1/keyspaces/%s/tables/%s/rows/query"
+	KeyspacesPathFormat      = "v1/keyspaces"
+	KeyspaceSinglePathFormat = "v1/keyspaces/%s"
+	TablesPathFormat         = "v1/keyspaces/%s/tables"
+	TableSinglePathFormat    = "v1/keyspaces/%s/tables/%s"
+	ColumnsPathFormat        = "v1/keyspaces/%s/tables/%s/columns"
+	ColumnSinglePathFormat   = "v1/keyspaces/%s/tables/%s/columns/%s"
+	RowsPathFormat           = "v1/keyspaces/%s/tables/%s/rows"
+	RowSinglePathFormat      = "v1/keyspaces/%s/tables/%s/rows/%s"
+	QueryPathFormat          = "v1/keyspaces/%s/tables/%s/rows/query"
+	ExportPathFormat         = "v1/keyspaces/%s/tables/%s/export"
+	ImportPathFormat         = "v1/keyspaces/%s/tables/%s/rows:import"
+	IndexesPathFormat        = "v1/keyspaces/%s/tables/%s/indexes"
+	IndexSinglePathFormat    = "v1/keyspaces/%s/tables/%s/indexes/%s"
+	ViewsPathFormat          = "v1/keyspaces/%s/tables/%s/views"
+	ViewSinglePathFormat     = "v1/keyspaces/%s/tables/%s/views/%s"
+	TypesPathFormat          = "v1/keyspaces/%s/types"
+	TypeSinglePathFormat     = "v1/keyspaces/%s/types/%s"
+	TypeFieldsPathFormat     = "v1/keyspaces/%s/types/%s/fields"
+	RolesPathFormat          = "v1/roles"
+	RoleSinglePathFormat     = "v1/roles/%s"
+	PermissionsPathFormat    = "v1/roles/%s/permissions"
+	PermissionPathFormat     = "v1/roles/%s/permissions/%s"
+	MigrationPlanPathFormat  = "v1/migrations/plan"
+	MigrationApplyPathFormat = "v1/migrations/apply"
 )
 
+// importColonPathFormat is the path of the import route when using a router with the colon pattern, these routers
+// treat ':' as the start of a parameter so the requests to ImportPathFormat are rewritten using RowsActions()
+const importColonPathFormat = "v1/keyspaces/%s/tables/%s/import"
+
+// rowsImportSuffix is the suffix of the import path
+const rowsImportSuffix = "/rows:import"
+
 // routeList describes how to route an endpoint
 type routeList struct {
 	logger            log.Logger
@@ -33,6 +63,11 @@ type routeList struct {
 	operations        config.SchemaOperations
 	excludedKeyspaces map[string]bool
 	singleKeyspace    string
+	superuserRole     string
+	broker            events.Broker
+	columnPolicies    *config.ColumnPolicies
+	rowPolicies       *config.RowPolicies
+	exposure          *config.Exposure
 }
 
 // Routes returns a slice of all the REST endpoint routes
@@ -49,11 +84,17 @@ func Routes(prefix string, operations config.SchemaOperations, singleKeyspace st
 		operations:        operations,
 		excludedKeyspaces: excludedKeyspaces,
 		singleKeyspace:    singleKeyspace,
+		superuserRole:     cfg.SuperuserRole(),
+		broker:            events.WithSinks(cfg.Broker(), cfg.EventSinks(), cfg.Logger()),
+		columnPolicies:    cfg.ColumnPolicies(),
+		rowPolicies:       cfg.RowPolicies(),
+		exposure:          cfg.Exposure(),
 	}
 
 	urlPattern := cfg.RouterInfo().UrlPattern()
 
 	urlKeyspaces := url(prefix, urlPattern, KeyspacesPathFormat)
+	urlSingleKeyspace := url(prefix, urlPattern, KeyspaceSinglePathFormat, keyspaceParam)
 	urlTables := url(prefix, urlPattern, TablesPathFormat, keyspaceParam)
 	urlSingleTable := url(prefix, urlPattern, TableSinglePathFormat, keyspaceParam, tableParam)
 	urlColumns := url(prefix, urlPattern, ColumnsPathFormat, keyspaceParam, tableParam)
@@ -61,12 +102,31 @@ func Routes(prefix string, operations config.SchemaOperations, singleKeyspace st
 	urlRows := url(prefix, urlPattern, RowsPathFormat, keyspaceParam, tableParam)
 	urlSingleRow := url(prefix, urlPattern, RowSinglePathFormat, keyspaceParam, tableParam, "rowIdentifier")
 	urlQuery := url(prefix, urlPattern, QueryPathFormat, keyspaceParam, tableParam)
+	urlExport := url(prefix, urlPattern, ExportPathFormat, keyspaceParam, tableParam)
+	importPathFormat := ImportPathFormat
+	if urlPattern == config.UrlPatternColon {
+		importPathFormat = importColonPathFormat
+	}
+	urlImport := url(prefix, urlPattern, importPathFormat, keyspaceParam, tableParam)
+	urlIndexes := url(prefix, urlPattern, IndexesPathFormat, keyspaceParam, tableParam)
+	urlSingleIndex := url(prefix, urlPattern, IndexSinglePathFormat, keyspaceParam, tableParam, "indexName")
+	urlViews := url(prefix, urlPattern, ViewsPathFormat, keyspaceParam, tableParam)
+	urlSingleView := url(prefix, urlPattern, ViewSinglePathFormat, keyspaceParam, tableParam, "viewName")
+	urlTypes := url(prefix, urlPattern, TypesPathFormat, keyspaceParam)
+	urlSingleType := url(prefix, urlPattern, TypeSinglePathFormat, keyspaceParam, typeParam)
+	urlTypeFields := url(prefix, urlPattern, TypeFieldsPathFormat, keyspaceParam, typeParam)
+	urlRoles := url(prefix, urlPattern, RolesPathFormat)
+	urlSingleRole := url(prefix, urlPattern, RoleSinglePathFormat, roleParam)
+	urlPermissions := url(prefix, urlPattern, PermissionsPathFormat, roleParam)
+	urlSinglePermission := url(prefix, urlPattern, PermissionPathFormat, roleParam, permissionParam)
+	urlMigrationPlan := url(prefix, urlPattern, MigrationPlanPathFormat)
+	urlMigrationApply := url(prefix, urlPattern, MigrationApplyPathFormat)
 
 	routes := []types.Route{
 		{
 			Method:  http.MethodGet,
 			Pattern: urlColumns,
-			Handler: rl.validateKeyspace(rl.GetColumns),
+			Handler: rl.validateKeyspace(rl.validateTableExposed(rl.GetColumns)),
 		},
 		{
 			Method:  http.MethodPost,
@@ -81,32 +141,92 @@ func Routes(prefix string, operations config.SchemaOperations, singleKeyspace st
 		{
 			Method:  http.MethodGet,
 			Pattern: urlSingleColumn,
-			Handler: rl.validateKeyspace(rl.GetColumn),
+			Handler: rl.validateKeyspace(rl.validateTableExposed(rl.GetColumn)),
 		},
 		{
 			Method:  http.MethodPost,
 			Pattern: urlRows,
-			Handler: rl.validateKeyspace(rl.AddRow),
+			Handler: rl.validateKeyspace(rl.validateTable(config.DataInsert, rl.AddRow)),
 		},
 		{
 			Method:  http.MethodGet,
 			Pattern: urlSingleRow,
-			Handler: rl.validateKeyspace(rl.GetRow),
+			Handler: rl.validateKeyspace(rl.validateTable(config.DataRead, rl.GetRow)),
 		},
 		{
 			Method:  http.MethodPut,
 			Pattern: urlSingleRow,
-			Handler: rl.validateKeyspace(rl.UpdateRow),
+			Handler: rl.validateKeyspace(rl.validateTable(config.DataUpdate, rl.UpdateRow)),
 		},
 		{
 			Method:  http.MethodDelete,
 			Pattern: urlSingleRow,
-			Handler: rl.validateKeyspace(rl.DeleteRow),
+			Handler: rl.validateKeyspace(rl.validateTable(config.DataDelete, rl.DeleteRow)),
 		},
 		{
 			Method:  http.MethodPost,
 			Pattern: urlQuery,
-			Handler: rl.validateKeyspace(rl.Query),
+			Handler: rl.validateKeyspace(rl.validateTable(config.DataRead, rl.Query)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlExport,
+			Handler: rl.validateKeyspace(rl.validateTable(config.DataRead, rl.ExportRows)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlImport,
+			Handler: rl.validateKeyspace(rl.validateTable(config.DataInsert, rl.ImportRows)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlIndexes,
+			Handler: rl.validateKeyspace(rl.validateTableExposed(rl.GetIndexes)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlIndexes,
+			Handler: rl.validateKeyspace(rl.isSupported(config.IndexCreate, rl.AddIndex)),
+		},
+		{
+			Method:  http.MethodDelete,
+			Pattern: urlSingleIndex,
+			Handler: rl.validateKeyspace(rl.isSupported(config.IndexDrop, rl.DeleteIndex)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlViews,
+			Handler: rl.validateKeyspace(rl.isSupported(config.ViewCreate, rl.AddView)),
+		},
+		{
+			Method:  http.MethodDelete,
+			Pattern: urlSingleView,
+			Handler: rl.validateKeyspace(rl.isSupported(config.ViewDrop, rl.DeleteView)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlTypes,
+			Handler: rl.validateKeyspace(rl.GetUserTypes),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlTypes,
+			Handler: rl.validateKeyspace(rl.isSupported(config.TypeCreate, rl.AddUserType)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlSingleType,
+			Handler: rl.validateKeyspace(rl.GetUserType),
+		},
+		{
+			Method:  http.MethodDelete,
+			Pattern: urlSingleType,
+			Handler: rl.validateKeyspace(rl.isSupported(config.TypeDrop, rl.DeleteUserType)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlTypeFields,
+			Handler: rl.validateKeyspace(rl.isSupported(config.TypeAlterAdd, rl.AddUserTypeField)),
 		},
 		{
 			Method:  http.MethodGet,
@@ -121,7 +241,12 @@ func Routes(prefix string, operations config.SchemaOperations, singleKeyspace st
 		{
 			Method:  http.MethodGet,
 			Pattern: urlSingleTable,
-			Handler: rl.validateKeyspace(rl.GetTable),
+			Handler: rl.validateKeyspace(rl.validateTableExposed(rl.GetTable)),
+		},
+		{
+			Method:  http.MethodPut,
+			Pattern: urlSingleTable,
+			Handler: rl.validateKeyspace(rl.isSupported(config.TableAlterOptions, rl.UpdateTable)),
 		},
 		{
 			Method:  http.MethodDelete,
@@ -133,6 +258,82 @@ func Routes(prefix string, operations config.SchemaOperations, singleKeyspace st
 			Pattern: urlKeyspaces,
 			Handler: http.HandlerFunc(rl.GetKeyspaces),
 		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlKeyspaces,
+			Handler: rl.isSupported(config.KeyspaceCreate, rl.AddKeyspace),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlSingleKeyspace,
+			Handler: rl.validateKeyspace(rl.GetKeyspace),
+		},
+		{
+			Method:  http.MethodPut,
+			Pattern: urlSingleKeyspace,
+			Handler: rl.validateKeyspace(rl.isSupported(config.KeyspaceAlter, rl.UpdateKeyspace)),
+		},
+		{
+			Method:  http.MethodDelete,
+			Pattern: urlSingleKeyspace,
+			Handler: rl.validateKeyspace(rl.isSupported(config.KeyspaceDrop, rl.DeleteKeyspace)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlRoles,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.GetRoles)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlRoles,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.AddRole)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlSingleRole,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.GetRole)),
+		},
+		{
+			Method:  http.MethodPut,
+			Pattern: urlSingleRole,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.UpdateRole)),
+		},
+		{
+			Method:  http.MethodDelete,
+			Pattern: urlSingleRole,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.DeleteRole)),
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: urlPermissions,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.GetPermissions)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlPermissions,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.GrantPermission)),
+		},
+		{
+			Method:  http.MethodDelete,
+			Pattern: urlSinglePermission,
+			Handler: rl.isSupported(config.RoleManagement, rl.validateSuperuser(rl.RevokePermission)),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlMigrationPlan,
+			Handler: rl.isSupported(config.SchemaMigration, rl.PlanMigration),
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: urlMigrationApply,
+			Handler: rl.isSupported(config.SchemaMigration, rl.ApplyMigration),
+		},
+	}
+
+	for i, route := range routes {
+		if route.Method != http.MethodGet {
+			routes[i].Handler = rl.audited(route.Method, route.Pattern, route.Handler)
+		}
 	}
 
 	return routes
@@ -142,6 +343,22 @@ func url(prefix string, urlPattern config.UrlPattern, format string, parameterNa
 	return path.Join(prefix, urlPattern.UrlPathFormat(format, parameterNames...))
 }
 
+// RowsActions rewrites the path of the actions on the rows of a table, e.g. ".../rows:import", to the path of their
+// routes. It's required when the routes are served by a router using the colon pattern.
+func RowsActions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, rowsImportSuffix) {
+			u := *r.URL
+			u.Path = strings.TrimSuffix(u.Path, rowsImportSuffix) + "/import"
+			u.RawPath = ""
+			r2 := r.Clone(r.Context())
+			r2.URL = &u
+			r = r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (s *routeList) validateKeyspace(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		keyspaceName := s.params(r, keyspaceParam)
@@ -161,6 +378,47 @@ func (s *routeList) validateKeyspace(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// validateTable verifies that the table is exposed and that the data operation is allowed on it
+func (s *routeList) validateTable(op config.DataOperations, next http.HandlerFunc) http.HandlerFunc {
+	return s.validateTableExposed(func(w http.ResponseWriter, r *http.Request) {
+		if !s.exposure.Operations(s.params(r, keyspaceParam), s.params(r, tableParam)).IsSupported(op) {
+			forbiddenHandler(w, r)
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+// validateTableExposed verifies that the table is exposed, tables that are not exposed are reported as not found
+func (s *routeList) validateTableExposed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keyspaceName := s.params(r, keyspaceParam)
+		tableName := s.params(r, tableParam)
+
+		if !s.exposure.TableExposed(keyspaceName, tableName) {
+			RespondWithError(w, fmt.Sprintf(`Table "%s"."%s" not found`, keyspaceName, tableName), http.StatusNotFound)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// audited adds the audit operation to the request context, the statements executed with the context are audited
+func (s *routeList) audited(method string, pattern string, next http.Handler) http.Handler {
+	name := method + " " + pattern
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithOperation(r.Context(), &audit.Operation{
+			Api:      audit.REST,
+			Name:     name,
+			Keyspace: s.params(r, keyspaceParam),
+			Table:    s.params(r, tableParam),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func (s *routeList) isSupported(requiredOp config.SchemaOperations, handler http.HandlerFunc) http.HandlerFunc {
 	if s.operations.IsSupported(requiredOp) {
 		return handler
@@ -0,0 +1,146 @@
+package endpoint
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/datastax/cassandra-data-apis/config"
+	"github.com/datastax/cassandra-data-apis/db"
+	m "github.com/datastax/cassandra-data-apis/rest/models"
+	"github.com/datastax/cassandra-data-apis/types"
+	"github.com/gocql/gocql"
+	"net/http"
+	"strings"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	// pageStateHeader contains the page state for NDJSON responses, as the body only contains the rows
+	pageStateHeader = "X-Page-State"
+)
+
+func acceptsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ndjsonContentType)
+}
+
+func respondEmptyRows(w http.ResponseWriter, r *http.Request, pageState []byte) {
+	if acceptsNDJSON(r) {
+		setNDJSONHeaders(w, pageState)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	RespondJSONObjectWithCode(w, http.StatusOK, m.Rows{
+		PageState: base64.StdEncoding.EncodeToString(pageState),
+	})
+}
+
+func setNDJSONHeaders(w http.ResponseWriter, pageState []byte) {
+	w.Header().Set("Content-Type", ndjsonContentType)
+	if len(pageState) > 0 {
+		w.Header().Set(pageStateHeader, base64.StdEncoding.EncodeToString(pageState))
+	}
+}
+
+// writeRowsJSON writes the rows as they are scanned, producing the same output as a m.Rows instance. The iterator must
+// be positioned on the first row, it's closed before writing the end of the object: when the iteration fails, the
+// object is not closed so the client gets a truncated body.
+func writeRowsJSON(w http.ResponseWriter, rows db.RowIterator, table *gocql.TableMetadata) (int, error) {
+	setCommonHeaders(w)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write([]byte(`{"rows":[`)); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for hasRow := true; hasRow; hasRow = rows.Next() {
+		if count > 0 {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return count, err
+			}
+		}
+		if err := writeJSONRow(w, rows.Row(), table); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := rows.Close(); err != nil {
+		return count, err
+	}
+
+	trailer := struct {
+		PageState string `json:"pageState,omitempty"`
+		Count     int    `json:"_count,omitempty"`
+	}{
+		PageState: base64.StdEncoding.EncodeToString(rows.PageState()),
+		Count:     count,
+	}
+
+	buf, err := json.Marshal(trailer)
+	if err != nil {
+		return count, err
+	}
+
+	// Replace the opening brace of the trailer object to continue the rows object
+	buf[0] = ','
+	if _, err := w.Write([]byte("]")); err != nil {
+		return count, err
+	}
+	_, err = w.Write(buf)
+	return count, err
+}
+
+// ndjsonStreamError is the last line of a NDJSON body when the iteration fails after the rows started streaming
+type ndjsonStreamError struct {
+	Error string `json:"_error"`
+}
+
+// writeRowsNDJSON writes a row per line as they are scanned. The iterator must be positioned on the first row, it's
+// closed before terminating the last line: when the iteration fails, an error object is written as the last line as
+// the status code was already sent.
+func writeRowsNDJSON(w http.ResponseWriter, rows db.RowIterator, table *gocql.TableMetadata) (int, error) {
+	setNDJSONHeaders(w, rows.PageState())
+	w.WriteHeader(http.StatusOK)
+
+	count := 0
+	for hasRow := true; hasRow; hasRow = rows.Next() {
+		if count > 0 {
+			if _, err := w.Write([]byte("\n")); err != nil {
+				return count, err
+			}
+		}
+		if err := writeJSONRow(w, rows.Row(), table); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := rows.Close(); err != nil {
+		buf, _ := json.Marshal(ndjsonStreamError{Error: "unable to read the rows"})
+		_, _ = w.Write(append(append([]byte("\n"), buf...), '\n'))
+		return count, err
+	}
+
+	_, err := w.Write([]byte("\n"))
+	return count, err
+}
+
+func writeJSONRow(w http.ResponseWriter, row map[string]interface{}, table *gocql.TableMetadata) error {
+	buf, err := json.Marshal(types.ToJsonRow(row, table))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// accessRowIterator applies the column access to the rows
+type accessRowIterator struct {
+	db.RowIterator
+	access *config.ColumnAccess
+}
+
+func (r *accessRowIterator) Row() map[string]interface{} {
+	return r.access.ApplyToRow(r.RowIterator.Row())
+}
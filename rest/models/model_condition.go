@@ -0,0 +1,11 @@
+package models
+
+// Condition is a lightweight transaction condition on the current value of a column.
+type Condition struct {
+	ColumnName string `json:"columnName" validate:"required"`
+
+	Operator string `json:"operator" validate:"required,oneof=eq notEq gt gte lt lte in"`
+
+	// The value to compare to, it must be a list of values for the "in" operator.
+	Value interface{} `json:"value"`
+}
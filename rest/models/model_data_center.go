@@ -0,0 +1,7 @@
+package models
+
+// DataCenter defines the number of replicas of a keyspace in a data center
+type DataCenter struct {
+	Name     string `json:"name" validate:"required"`
+	Replicas int    `json:"replicas" validate:"gte=0"`
+}
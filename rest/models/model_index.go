@@ -0,0 +1,9 @@
+package models
+
+// Index describes an existing secondary index of a table
+type Index struct {
+	Name       string `json:"name"`
+	ColumnName string `json:"columnName"`
+	Target     string `json:"target,omitempty"`
+	Type       string `json:"type,omitempty"`
+}
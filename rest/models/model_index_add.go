@@ -0,0 +1,19 @@
+package models
+
+// IndexAdd defines a secondary index to be added to an existing table
+type IndexAdd struct {
+	// The column to index
+	ColumnName string `json:"columnName" validate:"required"`
+
+	// The name of the index, generated by the server when not provided
+	Name string `json:"name,omitempty"`
+
+	// The part of a collection column to index: keys, values or entries of a map, or full for frozen collections
+	Target string `json:"target,omitempty" validate:"omitempty,oneof=keys values entries full KEYS VALUES ENTRIES FULL"`
+
+	// The custom index class, e.g. StorageAttachedIndex. A regular secondary index is created when not provided.
+	Type string `json:"type,omitempty"`
+
+	// Attempting to create an existing index returns an error unless the IF NOT EXISTS option is used
+	IfNotExists bool `json:"ifNotExists,omitempty"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type IndexesResponse struct {
+	Success bool `json:"success,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// Keyspace describes an existing keyspace, its replication and the tables and types it contains
+type Keyspace struct {
+	Name string `json:"name"`
+
+	// The replication strategy class name, e.g. SimpleStrategy or NetworkTopologyStrategy.
+	ReplicationStrategy string `json:"replicationStrategy,omitempty"`
+
+	// The replication factor of keyspaces using SimpleStrategy.
+	ReplicationFactor *int `json:"replicationFactor,omitempty"`
+
+	// The replicas per data center of keyspaces using NetworkTopologyStrategy.
+	DataCenters []DataCenter `json:"dataCenters,omitempty"`
+
+	DurableWrites bool       `json:"durableWrites"`
+	Tables        []string   `json:"tables"`
+	Types         []UserType `json:"types"`
+}
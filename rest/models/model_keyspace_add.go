@@ -0,0 +1,17 @@
+package models
+
+// KeyspaceAdd defines the keyspace to be created
+type KeyspaceAdd struct {
+	Name string `json:"name" validate:"required"`
+
+	// Attempting to create an existing keyspace returns an error unless the IF NOT EXISTS option is used.
+	IfNotExists bool `json:"ifNotExists,omitempty"`
+
+	// The replicas per data center using NetworkTopologyStrategy.
+	DataCenters []DataCenter `json:"dataCenters,omitempty" validate:"dive"`
+
+	// The replication factor using SimpleStrategy, used when no data centers are provided.
+	ReplicationFactor int `json:"replicationFactor,omitempty" validate:"gte=0"`
+
+	DurableWrites *bool `json:"durableWrites,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// KeyspaceUpdate defines the replication and durable writes changes of an existing keyspace
+type KeyspaceUpdate struct {
+	// The replicas per data center using NetworkTopologyStrategy.
+	DataCenters []DataCenter `json:"dataCenters,omitempty" validate:"dive"`
+
+	// The replication factor using SimpleStrategy, used when no data centers are provided.
+	ReplicationFactor int `json:"replicationFactor,omitempty" validate:"gte=0"`
+
+	DurableWrites *bool `json:"durableWrites,omitempty"`
+}
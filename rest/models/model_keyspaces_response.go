@@ -0,0 +1,5 @@
+package models
+
+type KeyspacesResponse struct {
+	Success bool `json:"success,omitempty"`
+}
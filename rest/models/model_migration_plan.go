@@ -0,0 +1,15 @@
+package models
+
+// MigrationPlan contains the statements needed to change the existing schema into the desired one, in execution order
+type MigrationPlan struct {
+	Statements  []MigrationStatement `json:"statements"`
+	Destructive bool                 `json:"destructive"`
+	// Applied is the number of statements executed, it's only set when the plan is applied
+	Applied *int `json:"applied,omitempty"`
+}
+
+type MigrationStatement struct {
+	Query string `json:"query"`
+	// Destructive is set for statements that remove objects or data
+	Destructive bool `json:"destructive"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// Permission describes a permission of a role, the keyspace and table are only set for data resources
+type Permission struct {
+	Role       string `json:"role"`
+	Resource   string `json:"resource"`
+	Keyspace   string `json:"keyspace,omitempty"`
+	Table      string `json:"table,omitempty"`
+	Permission string `json:"permission"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// PermissionGrant defines a permission to be granted to a role
+type PermissionGrant struct {
+	Permission string `json:"permission" validate:"required,oneof=all create alter drop select modify authorize describe ALL CREATE ALTER DROP SELECT MODIFY AUTHORIZE DESCRIBE"`
+
+	// The keyspace of the resource, all keyspaces when not set.
+	Keyspace string `json:"keyspace,omitempty"`
+
+	// The table of the resource, the whole keyspace when not set.
+	Table string `json:"table,omitempty"`
+}
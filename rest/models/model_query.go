@@ -6,10 +6,26 @@ type Query struct {
 	OrderBy     *ClusteringExpression `json:"orderBy,omitempty"`
 	PageSize    int                   `json:"pageSize,omitempty"`
 	PageState   string                `json:"pageState,omitempty"`
+	Aggregates  []Aggregate           `json:"aggregates,omitempty"`
+	GroupBy     []string              `json:"groupBy,omitempty"`
+	// MetadataColumns contains the write timestamp and time-to-live of regular columns to include in each row
+	MetadataColumns []MetadataColumn `json:"metadataColumns,omitempty"`
 }
 
 type Filter struct {
 	ColumnName string        `json:"columnName" validate:"required"`
-	Operator   string        `json:"operator" validate:"required,oneof=eq notEq gt gte lt lte in"`
+	Operator   string        `json:"operator" validate:"required,oneof=eq notEq gt gte lt lte in contains containsKey containsEntry"`
 	Value      []interface{} `json:"value" validate:"required"`
 }
+
+type Aggregate struct {
+	Function   string `json:"function" validate:"required"`
+	ColumnName string `json:"columnName,omitempty"`
+	Alias      string `json:"alias,omitempty"`
+}
+
+type MetadataColumn struct {
+	ColumnName string `json:"columnName" validate:"required"`
+	Function   string `json:"function" validate:"required,oneof=writetime ttl"`
+	Alias      string `json:"alias,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// Role describes an existing role
+type Role struct {
+	Name      string   `json:"name"`
+	Login     bool     `json:"login"`
+	Superuser bool     `json:"superuser"`
+	MemberOf  []string `json:"memberOf,omitempty"`
+}
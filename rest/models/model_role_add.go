@@ -0,0 +1,17 @@
+package models
+
+// RoleAdd defines the role to be created
+type RoleAdd struct {
+	Name string `json:"name" validate:"required"`
+
+	// The password of the role, the role is created without password when not set.
+	Password *string `json:"password,omitempty"`
+
+	// Whether the role is allowed to log in.
+	Login bool `json:"login,omitempty"`
+
+	Superuser bool `json:"superuser,omitempty"`
+
+	// Attempting to create an existing role returns an error unless the IF NOT EXISTS option is used.
+	IfNotExists bool `json:"ifNotExists,omitempty"`
+}
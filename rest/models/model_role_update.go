@@ -0,0 +1,8 @@
+package models
+
+// RoleUpdate defines the options to change in an existing role, options that are not set are not changed
+type RoleUpdate struct {
+	Password  *string `json:"password,omitempty"`
+	Login     *bool   `json:"login,omitempty"`
+	Superuser *bool   `json:"superuser,omitempty"`
+}
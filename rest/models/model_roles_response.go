@@ -0,0 +1,5 @@
+package models
+
+type RolesResponse struct {
+	Success bool `json:"success,omitempty"`
+}
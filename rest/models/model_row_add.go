@@ -3,4 +3,14 @@ package models
 // RowAdd defines a row to be added to a table
 type RowAdd struct {
 	Columns []Column `json:"columns" validate:"required"`
+
+	// The write timestamp in microseconds since epoch, by default the server generates it. It can not be combined with
+	// the conditions.
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// The time-to-live of the values in seconds, by default the table default time-to-live is used.
+	TTL *int `json:"ttl,omitempty"`
+
+	// Only insert the row when it doesn't exist.
+	IfNotExists bool `json:"ifNotExists,omitempty"`
 }
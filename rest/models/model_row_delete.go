@@ -0,0 +1,7 @@
+package models
+
+// RowDelete defines the optional conditions of a delete operation.
+type RowDelete struct {
+	// Only delete the row when the current values match the conditions.
+	IfConditions []Condition `json:"ifConditions,omitempty"`
+}
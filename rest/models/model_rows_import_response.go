@@ -0,0 +1,17 @@
+package models
+
+type RowsImportResponse struct {
+	Inserted int `json:"inserted"`
+
+	Failed int `json:"failed"`
+
+	Errors []RowImportError `json:"errors,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+type RowImportError struct {
+	Line int `json:"line"`
+
+	Message string `json:"message"`
+}
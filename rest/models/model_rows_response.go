@@ -4,4 +4,10 @@ type RowsResponse struct {
 	Success bool `json:"success,omitempty"`
 
 	RowsModified int32 `json:"rowsModified,omitempty"`
+
+	// Applied is only included for conditional operations.
+	Applied *bool `json:"applied,omitempty"`
+
+	// The current values of the row when a conditional operation was not applied.
+	Value map[string]interface{} `json:"value,omitempty"`
 }
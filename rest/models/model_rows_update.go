@@ -3,6 +3,19 @@ package models
 // RowsUpdate defines an update operation on rows within a table.
 type RowsUpdate struct {
 	Changeset []Changeset `json:"changeset" validate:"required"`
+
+	// The write timestamp in microseconds since epoch, by default the server generates it. It can not be combined with
+	// the conditions.
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// The time-to-live of the values in seconds, by default the table default time-to-live is used.
+	TTL *int `json:"ttl,omitempty"`
+
+	// Only update the row when it exists.
+	IfExists bool `json:"ifExists,omitempty"`
+
+	// Only update the row when the current values match the conditions.
+	IfConditions []Condition `json:"ifConditions,omitempty"`
 }
 
 // Changeset is a column and associated value to be used when updating a row.
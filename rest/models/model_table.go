@@ -6,4 +6,5 @@ type Table struct {
 	ColumnDefinitions []ColumnDefinition `json:"columnDefinitions,omitempty"`
 	PrimaryKey        *PrimaryKey        `json:"primaryKey,omitempty"`
 	TableOptions      *TableOptions      `json:"tableOptions,omitempty"`
+	Views             []View             `json:"views,omitempty"`
 }
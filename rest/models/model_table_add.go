@@ -33,7 +33,26 @@ type TableOptions struct {
 	// TTL (Time To Live) in seconds, where zero is disabled. The maximum configurable value is 630720000 (20 years). If
 	// the value is greater than zero, TTL is enabled for the entire table and an expiration timestamp is added to each
 	// column. A new TTL timestamp is calculated each time the data is updated and the row is removed after all the data expires.
-	DefaultTimeToLive *int32 `json:"defaultTimeToLive,omitempty" validate:"gte=0,lte=630720000"`
+	DefaultTimeToLive *int32 `json:"defaultTimeToLive,omitempty" validate:"omitempty,gte=0,lte=630720000"`
+
+	// The number of seconds after data is marked with a tombstone (deletion marker) before it is eligible for
+	// garbage-collection.
+	GcGraceSeconds *int32 `json:"gcGraceSeconds,omitempty" validate:"omitempty,gte=0"`
+
+	// The desired false-positive probability for SSTable bloom filters, a value greater than 0 and less than or equal to 1.
+	BloomFilterFpChance *float64 `json:"bloomFilterFpChance,omitempty" validate:"omitempty,gt=0,lte=1"`
+
+	// A human readable comment describing the table.
+	Comment *string `json:"comment,omitempty"`
+
+	// The compaction strategy and its sub-options, e.g. {"class": "LeveledCompactionStrategy"}.
+	Compaction map[string]string `json:"compaction,omitempty"`
+
+	// The compression algorithm and its sub-options, e.g. {"class": "LZ4Compressor"}.
+	Compression map[string]string `json:"compression,omitempty"`
+
+	// The caching settings, e.g. {"keys": "ALL", "rows_per_partition": "NONE"}.
+	Caching map[string]string `json:"caching,omitempty"`
 
 	ClusteringExpression []ClusteringExpression `json:"clusteringExpression,omitempty"`
 }
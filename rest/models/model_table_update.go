@@ -0,0 +1,6 @@
+package models
+
+// TableUpdate defines the options to change in an existing table
+type TableUpdate struct {
+	TableOptions *TableOptions `json:"tableOptions" validate:"required"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// UserType describes a user-defined type
+type UserType struct {
+	Name             string             `json:"name"`
+	Keyspace         string             `json:"keyspace,omitempty"`
+	FieldDefinitions []ColumnDefinition `json:"fieldDefinitions"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// UserTypeAdd defines a user-defined type to be added to an existing keyspace
+type UserTypeAdd struct {
+	Name string `json:"name" validate:"required"`
+
+	// Attempting to create an existing type returns an error unless the IF NOT EXISTS option is used
+	IfNotExists bool `json:"ifNotExists,omitempty"`
+
+	// The fields of the type, the static flag of the definitions is not used
+	FieldDefinitions []ColumnDefinition `json:"fieldDefinitions" validate:"required"`
+}
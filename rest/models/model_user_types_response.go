@@ -0,0 +1,5 @@
+package models
+
+type UserTypesResponse struct {
+	Success bool `json:"success,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// View describes a materialized view of a table
+type View struct {
+	Name        string   `json:"name"`
+	ColumnNames []string `json:"columnNames,omitempty"`
+
+	PrimaryKey *PrimaryKey `json:"primaryKey,omitempty"`
+
+	ClusteringExpression []ClusteringExpression `json:"clusteringExpression,omitempty"`
+
+	// The restrictions on the base table rows included in the view
+	WhereClause string `json:"whereClause,omitempty"`
+
+	IncludeAllColumns bool `json:"includeAllColumns,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// ViewAdd defines a materialized view to be added to an existing table
+type ViewAdd struct {
+	Name string `json:"name" validate:"required"`
+
+	// Attempting to create an existing view returns an error unless the IF NOT EXISTS option is used
+	IfNotExists bool `json:"ifNotExists,omitempty"`
+
+	// The columns of the base table to include in the view, all columns are included when not provided
+	ColumnNames []string `json:"columnNames,omitempty"`
+
+	// The primary key of the view, it must contain all the primary key columns of the base table
+	PrimaryKey *PrimaryKey `json:"primaryKey" validate:"required"`
+
+	ClusteringExpression []ClusteringExpression `json:"clusteringExpression,omitempty"`
+
+	// Additional restrictions on the primary key columns of the base table, using the eq, gt, gte, lt and lte
+	// operators. The primary key columns of the view are always restricted to be not null.
+	Where []Condition `json:"where,omitempty"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type ViewsResponse struct {
+	Success bool `json:"success,omitempty"`
+}
@@ -27,8 +27,7 @@ func ToJsonValues(rows []map[string]interface{}, table *gocql.TableMetadata) []m
 	result := make([]map[string]interface{}, rowsLength)
 
 	for columnName := range firstRow {
-		column := table.Columns[columnName]
-		converters[columnName] = jsonConverterPerType(column.Type)
+		converters[columnName] = jsonConverterPerColumn(table, columnName)
 	}
 
 	for i := 0; i < rowsLength; i++ {
@@ -50,6 +49,21 @@ func ToJsonValues(rows []map[string]interface{}, table *gocql.TableMetadata) []m
 	return result
 }
 
+// ToJsonRow converts the values of a single row, it's the equivalent of ToJsonValues for rows that are scanned one at
+// a time
+func ToJsonRow(row map[string]interface{}, table *gocql.TableMetadata) map[string]interface{} {
+	item := make(map[string]interface{}, len(row))
+	for columnName, value := range row {
+		if value == nil {
+			item[columnName] = nil
+			continue
+		}
+		item[columnName] = jsonConverterPerColumn(table, columnName)(value)
+	}
+
+	return item
+}
+
 func FromJsonValue(value interface{}, typeInfo gocql.TypeInfo) (interface{}, error) {
 	switch typeInfo.Type() {
 	case gocql.TypeTimestamp:
@@ -70,6 +84,31 @@ func FromJsonValue(value interface{}, typeInfo gocql.TypeInfo) (interface{}, err
 	return value, nil
 }
 
+// jsonConverterPerColumn returns the converter for the table column or, for selectors that are not table columns
+// like aggregates, a converter based on the value type
+func jsonConverterPerColumn(table *gocql.TableMetadata, columnName string) toJsonFn {
+	if column, ok := table.Columns[columnName]; ok {
+		return jsonConverterPerType(column.Type)
+	}
+
+	return jsonConverterPerValue
+}
+
+func jsonConverterPerValue(value interface{}) interface{} {
+	switch value.(type) {
+	case *time.Duration:
+		return DurationToCqlFormattedString(value)
+	case *time.Time:
+		return TimeAsString(value)
+	case *[]byte:
+		return ByteArrayToBase64String(value)
+	case fmt.Stringer:
+		return StringerToString(value)
+	}
+
+	return value
+}
+
 func jsonConverterPerType(typeInfo gocql.TypeInfo) toJsonFn {
 	switch typeInfo.Type() {
 	case gocql.TypeVarint, gocql.TypeDecimal:
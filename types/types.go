@@ -23,9 +23,10 @@ type QueryOptions struct {
 }
 
 type MutationOptions struct {
-	TTL               int `json:"ttl"`
-	Consistency       int `json:"consistency"`
-	SerialConsistency int `json:"serialConsistency"`
+	TTL               int   `json:"ttl"`
+	Consistency       int   `json:"consistency"`
+	SerialConsistency int   `json:"serialConsistency"`
+	Timestamp         int64 `json:"timestamp"`
 }
 
 type ConditionItem struct {
@@ -43,6 +44,24 @@ var CqlOperators = map[string]string{
 	"lt":    "<",
 	"lte":   "<=",
 	"in":    "IN",
+	// Operators for indexed collection columns
+	"contains":      "CONTAINS",
+	"containsKey":   "CONTAINS KEY",
+	"containsEntry": "=",
+}
+
+// MapEntry is the value of a condition on a single entry of an indexed map column, e.g. "col"[key] = value
+type MapEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// ColumnTuple is the value of a condition on multiple columns, e.g. ("a", "b") > (?, ?) or, when Token is set,
+// token("a", "b") > token(?, ?)
+type ColumnTuple struct {
+	Columns []string
+	Values  []interface{}
+	Token   bool
 }
 
 // Route represents a request route to be served